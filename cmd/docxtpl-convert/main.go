@@ -0,0 +1,65 @@
+// Convert Word mail merge templates into templates for go-docx-template.
+//
+//	docxtpl-convert [-strict] mailmerge.docx template.docx
+//
+// MERGEFIELD and IF fields are converted into tags and anything which couldn't be converted is listed.
+// With -strict the exit code is 1 when there is anything which couldn't be converted.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	docxtpl "github.com/tomwatkins1994/go-docx-template"
+)
+
+var errNotConverted = errors.New("some fields couldn't be converted")
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, output io.Writer) error {
+	flags := flag.NewFlagSet("docxtpl-convert", flag.ContinueOnError)
+	flags.SetOutput(output)
+	strict := flags.Bool("strict", false, "exit with an error if any fields couldn't be converted")
+	flags.Usage = func() {
+		fmt.Fprintln(output, "Usage: docxtpl-convert [-strict] input.docx output.docx")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("an input and output file are required")
+	}
+
+	doc, err := docxtpl.ParseFromFilename(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	issues, err := doc.ConvertMergeFields()
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Fprintln(output, issue)
+	}
+
+	if err := doc.SaveToFile(flags.Arg(1)); err != nil {
+		return err
+	}
+
+	if *strict && len(issues) > 0 {
+		return errNotConverted
+	}
+	return nil
+}
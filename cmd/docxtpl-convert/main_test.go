@@ -0,0 +1,75 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	docxtpl "github.com/tomwatkins1994/go-docx-template"
+)
+
+// Create a mail merge document from the basic test template with a different body.
+func createMailMergeDocument(t *testing.T, body string) string {
+	reader, err := zip.OpenReader("../../test_templates/test_basic.docx")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	filename := filepath.Join(t.TempDir(), "mailmerge.docx")
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	defer f.Close()
+
+	writer := zip.NewWriter(f)
+	for _, file := range reader.File {
+		w, err := writer.Create(file.Name)
+		require.NoError(t, err)
+		if file.Name == "word/document.xml" {
+			_, err = io.WriteString(w, `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`+body+`</w:body></w:document>`)
+			require.NoError(t, err)
+			continue
+		}
+		r, err := file.Open()
+		require.NoError(t, err)
+		_, err = io.Copy(w, r)
+		r.Close()
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	return filename
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Should convert the fields and list those which couldn't be converted", func(t *testing.T) {
+		input := createMailMergeDocument(t, `<w:p><w:fldSimple w:instr=" MERGEFIELD Name "/><w:fldSimple w:instr=" MERGEREC "/></w:p>`)
+		output := filepath.Join(t.TempDir(), "template.docx")
+
+		buf := &bytes.Buffer{}
+		err := run([]string{input, output}, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "{ MERGEREC }: MERGEREC fields depend on the records being merged, which a template can't do\n", buf.String())
+
+		doc, err := docxtpl.ParseFromFilename(output)
+		require.NoError(t, err)
+		require.NoError(t, doc.Render(map[string]any{"Name": "Tom"}))
+	})
+
+	t.Run("Should error in strict mode when fields couldn't be converted", func(t *testing.T) {
+		input := createMailMergeDocument(t, `<w:p><w:fldSimple w:instr=" SKIPIF "/></w:p>`)
+
+		err := run([]string{"-strict", input, filepath.Join(t.TempDir(), "template.docx")}, io.Discard)
+		assert.ErrorIs(t, err, errNotConverted)
+	})
+
+	t.Run("Should error without an input and output file", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := run([]string{"mailmerge.docx"}, buf)
+		assert.EqualError(t, err, "an input and output file are required")
+		assert.Contains(t, buf.String(), "Usage: docxtpl-convert")
+	})
+}
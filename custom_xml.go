@@ -0,0 +1,32 @@
+package docxtpl
+
+import (
+	"errors"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/customxml"
+	"github.com/tomwatkins1994/go-docx-template/internal/docxwrappers"
+)
+
+// Write data into a custom XML part of the document, which content controls can be bound to in Word.
+// The data is written under a root element in a namespace, replacing any existing part with the same root
+// so bindings made in the template keep working. Bound content controls are updated to show the data.
+// Maps and structs become elements, slices become repeated elements and other values become text.
+//
+//	data := map[string]any{
+//		"client": "TW Software",
+//		"date":   time.Now(),
+//	}
+//	err := doc.SetCustomXml("urn:example:invoice", "invoice", data)
+func (d *DocxTmpl) SetCustomXml(namespace string, rootName string, data any) error {
+	docx, ok := d.docx.(docxwrappers.CustomXmlDocxWrapper)
+	if !ok {
+		return errors.New("custom XML is not supported by this document")
+	}
+
+	xmlString, err := customxml.Marshal(namespace, rootName, data)
+	if err != nil {
+		return err
+	}
+
+	return docx.SetCustomXml(xmlString)
+}
@@ -0,0 +1,53 @@
+package docxtpl
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/docxwrappers"
+	"github.com/tomwatkins1994/go-docx-template/internal/templatedata"
+)
+
+// Set document variables from a map or struct, which { DOCVARIABLE Name } fields in the document show.
+// Variables with the same names are replaced. The fields in the document, headers and footers are updated
+// to show the values straight away, rather than when they are next updated in Word.
+//
+// Values can be strings, numbers, booleans or dates, which are written as 2006-01-02.
+//
+//	err := doc.SetDocumentVariables(map[string]any{
+//		"ClientName": "TW Software",
+//		"Reference":  1042,
+//	})
+func (d *DocxTmpl) SetDocumentVariables(data any) error {
+	docx, ok := d.docx.(docxwrappers.FieldsDocxWrapper)
+	if !ok {
+		return errors.New("document variables are not supported by this document")
+	}
+
+	convertedData, err := templatedata.DataToMap(data)
+	if err != nil {
+		return err
+	}
+
+	variables := make(map[string]string, len(convertedData))
+	for name, value := range convertedData {
+		switch v := value.(type) {
+		case string:
+			variables[name] = v
+		case time.Time:
+			variables[name] = v.Format("2006-01-02")
+		default:
+			switch reflect.ValueOf(value).Kind() {
+			case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+				variables[name] = fmt.Sprint(value)
+			default:
+				return fmt.Errorf("document variable %q: %T values can't be stored", name, value)
+			}
+		}
+	}
+
+	return docx.SetDocumentVariables(variables)
+}
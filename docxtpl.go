@@ -1,15 +1,17 @@
 package docxtpl
 
 import (
+	"fmt"
 	"io"
-	"maps"
 	"os"
 	"reflect"
 	"text/template"
+	"time"
 
 	"github.com/tomwatkins1994/go-docx-template/internal/docxwrappers"
-	"github.com/tomwatkins1994/go-docx-template/internal/functions"
 	"github.com/tomwatkins1994/go-docx-template/internal/images"
+	"github.com/tomwatkins1994/go-docx-template/internal/numbering"
+	"github.com/tomwatkins1994/go-docx-template/internal/revisions"
 	"github.com/tomwatkins1994/go-docx-template/internal/tags"
 	"github.com/tomwatkins1994/go-docx-template/internal/templatedata"
 	"github.com/tomwatkins1994/go-docx-template/internal/xmlutils"
@@ -18,9 +20,13 @@ import (
 type DocxTmpl struct {
 	docx    docxwrappers.DocxWrapper
 	funcMap template.FuncMap
+	// The Sprig compatible functions, if they have been registered
+	sprigFuncMap template.FuncMap
 }
 
 // Parse the document from a reader and store it in memory.
+// The XML of the document is worked with directly, so elements such as tracked changes, content
+// controls and fields are kept when the document is saved.
 // You can it invoke from a file.
 //
 //	reader, err := os.Open("path_to_doc.docx")
@@ -33,8 +39,17 @@ type DocxTmpl struct {
 //	}
 //	size := fileinfo.Size()
 //	doc, err := docxtpl.Parse(reader, int64(size))
-func Parse(reader io.ReaderAt, size int64) (*DocxTmpl, error) {
-	docx, err := docxwrappers.NewFumiamaDocx(reader, size)
+//
+// Options can be passed to clean up the template before it is rendered.
+//
+//	doc, err := docxtpl.Parse(reader, int64(size), docxtpl.WithAcceptedRevisions())
+func Parse(reader io.ReaderAt, size int64, options ...ParseOption) (*DocxTmpl, error) {
+	docx, err := docxwrappers.NewXmlDocx(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	err = applyParseOptions(docx, newParseOptions(options...))
 	if err != nil {
 		return nil, err
 	}
@@ -43,17 +58,19 @@ func Parse(reader io.ReaderAt, size int64) (*DocxTmpl, error) {
 }
 
 func newDocxTmpl(docx docxwrappers.DocxWrapper) *DocxTmpl {
-	funcMap := make(template.FuncMap)
-	maps.Copy(funcMap, functions.DefaultFuncMap)
-
-	return &DocxTmpl{docx, funcMap}
+	return &DocxTmpl{docx, make(template.FuncMap), nil}
 }
 
 // Parse the document from a filename and store it in memory.
 //
 //	doc, err := docxtpl.ParseFromFilename("path_to_doc.docx")
-func ParseFromFilename(filename string) (*DocxTmpl, error) {
-	docx, err := docxwrappers.NewFumiamaDocxFromFilename(filename)
+func ParseFromFilename(filename string, options ...ParseOption) (*DocxTmpl, error) {
+	docx, err := docxwrappers.NewXmlDocxFromFilename(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	err = applyParseOptions(docx, newParseOptions(options...))
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +78,27 @@ func ParseFromFilename(filename string) (*DocxTmpl, error) {
 	return newDocxTmpl(docx), nil
 }
 
+func applyParseOptions(docx docxwrappers.RevisionsDocxWrapper, options *parseOptions) error {
+	switch options.revisions {
+	case acceptRevisions:
+		if err := docx.AcceptRevisions(); err != nil {
+			return err
+		}
+	case rejectRevisions:
+		if err := docx.RejectRevisions(); err != nil {
+			return err
+		}
+	}
+
+	if options.removeComments {
+		if err := docx.RemoveComments(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Replace the placeholders in the document with passed in data.
 // Data can be a struct or map
 //
@@ -85,9 +123,25 @@ func ParseFromFilename(filename string) (*DocxTmpl, error) {
 //	}
 //
 // err = doc.Render(data)
-func (d *DocxTmpl) Render(data any) error {
+//
+// Options can be passed to change how the document is rendered.
+//
+//	err = doc.Render(data, docxtpl.WithLocale(language.German), docxtpl.WithDelimiters("[[", "]]"))
+func (d *DocxTmpl) Render(data any, options ...RenderOption) error {
+	renderOptions := newRenderOptions(options...)
+
+	delims, err := tags.NewDelimiters(renderOptions.leftDelim, renderOptions.rightDelim)
+	if err != nil {
+		return err
+	}
+
 	// Ensure that there are no 'part tags' in the XML document
-	d.docx.MergeTags()
+	d.docx.MergeTags(delims)
+
+	// Fill in content controls along with the tags
+	if controls, ok := d.docx.(docxwrappers.ContentControlsDocxWrapper); ok {
+		controls.AddContentControlTags(delims)
+	}
 
 	// Process the template data
 	processedData, err := d.processTemplateData(data)
@@ -95,6 +149,52 @@ func (d *DocxTmpl) Render(data any) error {
 		return err
 	}
 
+	// Paragraphs in these styles are numbered, so loops repeating them can restart their numbering
+	var numberedStyles []string
+	if lists, ok := d.docx.(docxwrappers.ListsDocxWrapper); ok {
+		numberedStyles = lists.GetNumberedStyles()
+	}
+
+	// Replace the tags in other parts such as footnotes first, so notes added from the document aren't rendered twice
+	if parts, ok := d.docx.(docxwrappers.PartsDocxWrapper); ok {
+		for _, partName := range parts.GetTemplatePartNames() {
+			partXmlString, err := parts.GetPartXml(partName)
+			if err != nil {
+				return err
+			}
+			partXmlString, err = replaceTagsInXml(partXmlString, processedData, d.getFuncMap(renderOptions), delims, renderOptions, numberedStyles)
+			if err != nil {
+				return fmt.Errorf("error rendering %s: %w", partName, err)
+			}
+			if err := parts.ReplacePartXml(partName, partXmlString); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Set the properties of the document and fill in the tags in them, so fields showing them are up to date
+	if props, ok := d.docx.(docxwrappers.PropertiesDocxWrapper); ok {
+		if renderOptions.properties != nil {
+			if err := props.SetProperties(renderOptions.properties); err != nil {
+				return err
+			}
+		}
+		for _, partName := range props.GetPropertiesPartNames() {
+			partXmlString, err := props.GetPartXml(partName)
+			if err != nil {
+				return err
+			}
+			// Properties are plain text so are never shown as tracked changes
+			partXmlString, err = tags.ReplaceTagsInXml(partXmlString, processedData, d.getFuncMap(renderOptions), delims)
+			if err != nil {
+				return fmt.Errorf("error rendering %s: %w", partName, err)
+			}
+			if err := props.ReplacePartXml(partName, partXmlString); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Get the document XML
 	documentXmlString, err := d.docx.GetDocumentXml()
 	if err != nil {
@@ -102,16 +202,49 @@ func (d *DocxTmpl) Render(data any) error {
 	}
 
 	// Replace the tags in XML
-	documentXmlString, err = tags.ReplaceTagsInXml(documentXmlString, processedData, d.funcMap)
+	documentXmlString, err = replaceTagsInXml(documentXmlString, processedData, d.getDocumentFuncMap(renderOptions), delims, renderOptions, numberedStyles)
 	if err != nil {
 		return err
 	}
+	// Documents which support lists number the lists repeated by loops from the start in each iteration
+	if _, ok := d.docx.(docxwrappers.ListsDocxWrapper); !ok {
+		documentXmlString = numbering.RemoveMarkers(documentXmlString)
+	}
 
-	d.docx.ReplaceDocumentXml(documentXmlString)
+	err = d.docx.ReplaceDocumentXml(documentXmlString)
+	if err != nil {
+		return err
+	}
+
+	// Show the rendered properties, headings and numbers in the fields which display them
+	if fields, ok := d.docx.(docxwrappers.FieldsDocxWrapper); ok {
+		if err := fields.UpdateFieldResults(); err != nil {
+			return err
+		}
+		if renderOptions.updateFieldsOnOpen {
+			if err := fields.SetUpdateFieldsOnOpen(); err != nil {
+				return err
+			}
+		}
+	}
 
 	return nil
 }
 
+// Replace the tags in XML, showing the changes as tracked changes if the options ask for them.
+func replaceTagsInXml(xmlString string, data map[string]any, funcMap template.FuncMap, delims *tags.Delimiters, options *renderOptions, numberedStyles []string) (string, error) {
+	if !options.trackChanges {
+		return tags.ReplaceTagsInXml(xmlString, data, funcMap, delims, numberedStyles...)
+	}
+
+	xmlString, err := tags.ReplaceTagsInXmlWithChanges(xmlString, data, funcMap, delims, numberedStyles...)
+	if err != nil {
+		return "", err
+	}
+
+	return revisions.MarkChanges(xmlString, options.changeAuthor, options.changeDate)
+}
+
 // Save the document to a writer.
 // This could be a new file.
 //
@@ -151,6 +284,18 @@ func (d *DocxTmpl) SaveToFile(filename string) error {
 	return nil
 }
 
+// Whether values of a type are left as they are by processTemplateData, as they can't hold text which
+// needs escaping, e.g. numbers, bools and dates.
+func isPlainValueType(valueType reflect.Type) bool {
+	switch valueType.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return valueType == reflect.TypeFor[time.Time]()
+}
+
 func (d *DocxTmpl) processTemplateData(data any) (map[string]any, error) {
 	convertedData, err := templatedata.DataToMap(data)
 	if err != nil {
@@ -158,79 +303,128 @@ func (d *DocxTmpl) processTemplateData(data any) (map[string]any, error) {
 	}
 
 	var processTagValues func(data *map[string]any) error
+	var processTagValue func(value any) (any, error)
 	processTagValues = func(data *map[string]any) error {
 		for key, value := range *data {
-			if stringVal, ok := value.(string); ok {
-				// Check for files
-				if isImage, err := templatedata.IsImageFilePath(stringVal); err != nil {
-					return err
-				} else {
-					if isImage {
-						image, err := images.CreateInlineImage(stringVal)
-						if err != nil {
-							return err
-						}
-						imageXml, err := d.docx.AddInlineImage(image)
-						if err != nil {
-							return err
-						}
-						(*data)[key] = imageXml
-					} else {
-						xmlEscapedText, err := xmlutils.EscapeXmlString(stringVal)
-						if err != nil {
-							return err
-						}
-						(*data)[key] = xmlEscapedText
-					}
-				}
-			} else if nestedMap, ok := value.(map[string]any); ok {
-				if err := processTagValues(&nestedMap); err != nil {
-					return err
-				}
-				(*data)[key] = nestedMap
-			} else if sliceValue, ok := value.([]map[string]any); ok {
-				for i := range sliceValue {
-					if err := processTagValues(&sliceValue[i]); err != nil {
-						return err
-					}
-				}
-			} else if inlineImage, ok := value.(*images.InlineImage); ok {
-				imageXml, err := d.docx.AddInlineImage(inlineImage)
+			processedValue, err := processTagValue(value)
+			if err != nil {
+				return err
+			}
+			(*data)[key] = processedValue
+		}
+
+		return nil
+	}
+	processTagValue = func(value any) (any, error) {
+		if stringVal, ok := value.(string); ok {
+			// Check for files
+			if isImage, err := templatedata.IsImageFilePath(stringVal); err != nil {
+				return nil, err
+			} else if isImage {
+				image, err := images.CreateInlineImage(stringVal)
 				if err != nil {
-					return err
+					return nil, err
 				}
-				(*data)[key] = imageXml
+				return d.docx.AddInlineImage(image)
 			} else {
-				reflectVal := reflect.ValueOf(value)
+				return xmlutils.EscapeXmlString(stringVal)
+			}
+		} else if nestedMap, ok := value.(map[string]any); ok {
+			if err := processTagValues(&nestedMap); err != nil {
+				return nil, err
+			}
+			return nestedMap, nil
+		} else if sliceValue, ok := value.([]map[string]any); ok {
+			for i := range sliceValue {
+				if err := processTagValues(&sliceValue[i]); err != nil {
+					return nil, err
+				}
+			}
+			return sliceValue, nil
+		} else if inlineImage, ok := value.(*images.InlineImage); ok {
+			return d.docx.AddInlineImage(inlineImage)
+		} else if list, ok := value.(*List); ok && list != nil {
+			return processTagValue(*list)
+		} else if list, ok := value.(List); ok {
+			items, err := processTagValue(list.Items)
+			if err != nil {
+				return nil, err
+			}
+			return d.addList(items, list.Numbered)
+		} else if _, ok := value.(time.Time); ok {
+			// Leave dates as they are so they can be formatted by the date functions
+			return value, nil
+		}
 
-				if reflectVal.Kind() == reflect.Struct {
-					nestedMap, err := templatedata.DataToMap(value)
+		reflectVal := reflect.ValueOf(value)
+		switch reflectVal.Kind() {
+		case reflect.String:
+			// Named string types such as type Status string
+			return processTagValue(reflectVal.String())
+		case reflect.Pointer:
+			if reflectVal.IsNil() {
+				return value, nil
+			}
+			return processTagValue(reflectVal.Elem().Interface())
+		case reflect.Struct:
+			nestedMap, err := templatedata.DataToMap(value)
+			if err != nil {
+				return nil, err
+			}
+			if err := processTagValues(&nestedMap); err != nil {
+				return nil, err
+			}
+			return nestedMap, nil
+		case reflect.Slice, reflect.Array:
+			if templatedata.IsStructOrMapSlice(reflectVal.Type()) {
+				newMapSlice := make([]map[string]any, reflectVal.Len())
+				for i := 0; i < reflectVal.Len(); i++ {
+					mapValue, err := templatedata.DataToMap(reflectVal.Index(i).Interface())
 					if err != nil {
-						return err
+						return nil, err
 					}
-					if err := processTagValues(&nestedMap); err != nil {
-						return err
+					if err := processTagValues(&mapValue); err != nil {
+						return nil, err
 					}
-					(*data)[key] = nestedMap
-				} else if reflectVal.Kind() == reflect.Slice {
-					newMapSlice := make([]map[string]any, reflectVal.Len())
-					for i := 0; i < reflectVal.Len(); i++ {
-						sliceValue := reflectVal.Index(i).Interface()
-						mapValue, err := templatedata.DataToMap(sliceValue)
-						if err != nil {
-							return err
-						}
-						if err := processTagValues(&mapValue); err != nil {
-							return err
-						}
-						newMapSlice[i] = mapValue
-					}
-					(*data)[key] = newMapSlice
+					newMapSlice[i] = mapValue
+				}
+				return newMapSlice, nil
+			}
+			if isPlainValueType(reflectVal.Type().Elem()) {
+				return value, nil
+			}
+			// Other slices such as []string, []any and [][]string are processed item by item
+			newSlice := make([]any, reflectVal.Len())
+			for i := 0; i < reflectVal.Len(); i++ {
+				processedValue, err := processTagValue(reflectVal.Index(i).Interface())
+				if err != nil {
+					return nil, err
+				}
+				newSlice[i] = processedValue
+			}
+			return newSlice, nil
+		case reflect.Map:
+			if isPlainValueType(reflectVal.Type().Elem()) {
+				return value, nil
+			}
+			// Keys are left as they are so values can still be looked up with index
+			newMap := reflect.MakeMapWithSize(reflect.MapOf(reflectVal.Type().Key(), reflect.TypeFor[any]()), reflectVal.Len())
+			for iter := reflectVal.MapRange(); iter.Next(); {
+				processedValue, err := processTagValue(iter.Value().Interface())
+				if err != nil {
+					return nil, err
+				}
+				if processedValue == nil {
+					newMap.SetMapIndex(iter.Key(), reflect.Zero(reflect.TypeFor[any]()))
+				} else {
+					newMap.SetMapIndex(iter.Key(), reflect.ValueOf(processedValue))
 				}
 			}
+			return newMap.Interface(), nil
 		}
 
-		return nil
+		// Leave other values such as numbers and bools as they are
+		return value, nil
 	}
 
 	err = processTagValues(&convertedData)
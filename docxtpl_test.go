@@ -1,8 +1,10 @@
 package docxtpl
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -30,15 +32,9 @@ type testWrapper struct {
 func getWrappers() []testWrapper {
 	var wrappers = []testWrapper{
 		{
-			name: "Fumiama",
+			name: "Xml",
 			docxFromFilename: func(filename string) (docxwrappers.DocxWrapper, error) {
-				return docxwrappers.NewFumiamaDocxFromFilename(filename)
-			},
-		},
-		{
-			name: "Gomutex",
-			docxFromFilename: func(filename string) (docxwrappers.DocxWrapper, error) {
-				return docxwrappers.NewGomutexDocxFromFilename(filename)
+				return docxwrappers.NewXmlDocxFromFilename(filename)
 			},
 		},
 	}
@@ -361,6 +357,390 @@ func TestParseAndRender(t *testing.T) {
 	}
 }
 
+func TestRenderWithDelimiters(t *testing.T) {
+	data := map[string]any{
+		"ProjectNumber": "B-00001",
+		"Client":        "TW Software",
+		"Status":        "New",
+	}
+
+	for _, wrapper := range getWrappers() {
+		t.Run(wrapper.name+" tags with other delimiters should be left as they are", func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			docx, err := wrapper.docxFromFilename("test_templates/test_basic.docx")
+			require.Nil(err, "Parsing error")
+			docxtpl := newDocxTmpl(docx)
+
+			err = docxtpl.Render(data, WithDelimiters("[[", "]]"))
+			assert.Nil(err, "Rendering error")
+
+			documentXml, err := docx.GetDocumentXml()
+			require.Nil(err)
+			assert.Contains(documentXml, "ProjectNumber")
+			assert.NotContains(documentXml, "B-00001")
+		})
+
+		t.Run(wrapper.name+" invalid delimiters should return an error", func(t *testing.T) {
+			docx, err := wrapper.docxFromFilename("test_templates/test_basic.docx")
+			require.Nil(t, err, "Parsing error")
+
+			err = newDocxTmpl(docx).Render(data, WithDelimiters("%%", "%%"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRenderWithTrackedChanges(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t xml:space="preserve">Client: {{ .Client }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ if eq .Status "Closed" }}Closed on {{ .ClosedDate }}{{ end }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{
+		"Client": "TW Software",
+		"Status": "New",
+	}, WithTrackedChanges("Generator"))
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Regexp(`<w:r><w:t xml:space="preserve">Client: </w:t></w:r><w:ins w:id="1" w:author="Generator" w:date="[0-9T:-]+Z"><w:r><w:t xml:space="preserve">TW Software</w:t></w:r></w:ins>`, documentXml)
+	assert.Regexp(`<w:del w:id="2" w:author="Generator" w:date="[0-9T:-]+Z"><w:r><w:delText xml:space="preserve">Closed on </w:delText></w:r></w:del>`, documentXml)
+
+	err = doc.SaveToFile("test_templates/generated_test_tracked_changes.docx")
+	assert.Nil(err, "Error saving document")
+}
+
+func TestRenderContentControls(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml" xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml"><w:body>` +
+		`<w:p><w:r><w:t xml:space="preserve">Client: </w:t></w:r><w:sdt><w:sdtPr><w:alias w:val="Client"/><w:tag w:val="Client"/><w:showingPlcHdr/><w:text/></w:sdtPr><w:sdtContent><w:r><w:rPr><w:rStyle w:val="PlaceholderText"/><w:b/></w:rPr><w:t>Click here</w:t></w:r></w:sdtContent></w:sdt></w:p>` +
+		`<w:p><w:sdt><w:sdtPr><w:alias w:val="Signed on"/><w:date w:fullDate="2020-01-01T00:00:00Z"><w:dateFormat w:val="d MMMM yyyy"/></w:date></w:sdtPr><w:sdtContent><w:r><w:t>1 January 2020</w:t></w:r></w:sdtContent></w:sdt></w:p>` +
+		`<w:p><w:sdt><w:sdtPr><w:tag w:val="Status"/><w:dropDownList><w:listItem w:displayText="Open for review" w:value="open"/><w:listItem w:displayText="Closed" w:value="closed"/></w:dropDownList></w:sdtPr><w:sdtContent><w:r><w:t>Choose</w:t></w:r></w:sdtContent></w:sdt></w:p>` +
+		`<w:p><w:sdt><w:sdtPr><w:tag w:val="Approved"/><w14:checkbox><w14:checked w14:val="0"/><w14:checkedState w14:val="2612" w14:font="MS Gothic"/><w14:uncheckedState w14:val="2610" w14:font="MS Gothic"/></w14:checkbox></w:sdtPr><w:sdtContent><w:r><w:t>\u2610</w:t></w:r></w:sdtContent></w:sdt></w:p>` +
+		`<w:sdt><w:sdtPr><w:tag w:val="Notes"/><w:richText/></w:sdtPr><w:sdtContent><w:p><w:r><w:t>Kept without a value</w:t></w:r></w:p></w:sdtContent></w:sdt>` +
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Name</w:t></w:r></w:p></w:tc></w:tr>` +
+		`<w:sdt><w:sdtPr><w:tag w:val="People"/><w15:repeatingSection/></w:sdtPr><w:sdtContent><w:sdt><w:sdtPr><w15:repeatingSectionItem/></w:sdtPr><w:sdtContent>` +
+		`<w:tr><w:tc><w:sdt><w:sdtPr><w:tag w:val="Name"/></w:sdtPr><w:sdtContent><w:p><w:r><w:t>Name</w:t></w:r></w:p></w:sdtContent></w:sdt></w:tc></w:tr>` +
+		`</w:sdtContent></w:sdt></w:sdtContent></w:sdt></w:tbl>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{
+		"Client":    "TW Software",
+		"Signed on": "2024-03-01",
+		"Status":    "open",
+		"Approved":  true,
+		"People": []map[string]any{
+			{"Name": "Tom"},
+			{"Name": "Jane"},
+		},
+	})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:sdtPr><w:alias w:val="Client"/><w:tag w:val="Client"/><w:text/></w:sdtPr><w:sdtContent><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">TW Software</w:t></w:r></w:sdtContent>`)
+	assert.Contains(documentXml, `<w:date w:fullDate="2024-03-01T00:00:00Z">`)
+	assert.Contains(documentXml, `<w:t xml:space="preserve">1 March 2024</w:t>`)
+	assert.Contains(documentXml, `<w:t xml:space="preserve">Open for review</w:t>`)
+	assert.Contains(documentXml, `<w14:checked w14:val="1"/>`)
+	assert.Contains(documentXml, "<w:t>\u2612</w:t>")
+	assert.Contains(documentXml, "<w:t>Kept without a value</w:t>")
+	assert.Contains(documentXml, `<w:t xml:space="preserve">Tom</w:t>`)
+	assert.Contains(documentXml, `<w:t xml:space="preserve">Jane</w:t>`)
+	assert.Equal(2, strings.Count(documentXml, "<w15:repeatingSectionItem/>"))
+}
+
+func TestSetCustomXml(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.SetCustomXml("urn:example:invoice", "invoice", map[string]any{"client": "TW Software"})
+	require.Nil(err)
+
+	// Bind content controls to the part, as Word would when editing the template
+	docx := doc.docx.(*docxwrappers.XmlDocx)
+	itemProps, err := docx.GetPartXml("customXml/itemProps2.xml")
+	require.Nil(err)
+	itemId := regexp.MustCompile(`ds:itemID="([^"]+)"`).FindStringSubmatch(itemProps)[1]
+	binding := func(xpath string) string {
+		return `<w:dataBinding w:prefixMappings="xmlns:ns0='urn:example:invoice'" w:xpath="` + xpath + `" w:storeItemID="` + itemId + `"/>`
+	}
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:sdt><w:sdtPr>` + binding("/ns0:invoice[1]/ns0:client[1]") + `<w:text/></w:sdtPr><w:sdtContent><w:r><w:t>TW Software</w:t></w:r></w:sdtContent></w:sdt></w:p>` +
+		`<w:p><w:sdt><w:sdtPr>` + binding("/ns0:invoice[1]/ns0:date[1]") + `<w:date><w:dateFormat w:val="dd/MM/yyyy"/></w:date></w:sdtPr><w:sdtContent><w:r><w:t>Date</w:t></w:r></w:sdtContent></w:sdt></w:p>` +
+		`<w:p><w:r><w:t>{{ .Reference }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"Reference": "B-00001"})
+	require.Nil(err, "Rendering error")
+
+	data := struct {
+		Client string    `xml:"client"`
+		Date   time.Time `xml:"date"`
+	}{"Jane's Shop", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	err = doc.SetCustomXml("urn:example:invoice", "invoice", data)
+	require.Nil(err)
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:t xml:space="preserve">Jane's Shop</w:t>`)
+	assert.Contains(documentXml, `<w:t xml:space="preserve">01/03/2024</w:t>`)
+	assert.Contains(documentXml, `<w:t>B-00001</w:t>`)
+	assert.Contains(documentXml, binding("/ns0:invoice[1]/ns0:client[1]"))
+
+	itemXml, err := docx.GetPartXml("customXml/item2.xml")
+	require.Nil(err)
+	assert.Contains(itemXml, `<invoice xmlns="urn:example:invoice"><client>Jane's Shop</client><date>2024-03-01T00:00:00</date></invoice>`)
+}
+
+func TestConvertMergeFields(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:fldSimple w:instr=" MERGEFIELD Title "><w:r><w:t>«Title»</w:t></w:r></w:fldSimple>` +
+		`<w:r><w:t xml:space="preserve"> </w:t></w:r><w:fldSimple w:instr=" MERGEFIELD LastName \* Upper "><w:r><w:t>«LastName»</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> IF </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> MERGEFIELD Paid </w:instrText></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>` +
+		`<w:r><w:instrText xml:space="preserve"> = "Yes" "Thank you for your payment" "Payment is due" </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>Payment is due</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p></w:tc></w:tr></w:tbl>` +
+		`<w:p><w:fldSimple w:instr=" NEXT "/><w:fldSimple w:instr=" MERGEFIELD Balance \# &quot;£#,##0.00&quot; "/></w:p>` +
+		`<w:sectPr/></w:body></w:document>`)
+	require.Nil(err)
+
+	issues, err := doc.ConvertMergeFields()
+	require.Nil(err)
+	assert.Len(issues, 2)
+	assert.Equal("NEXT", issues[0].Field)
+	assert.Equal(`MERGEFIELD Balance \# "£#,##0.00"`, issues[1].Field)
+
+	err = doc.Render(map[string]any{
+		"Title":    "Mr",
+		"LastName": "Watkins",
+		"Paid":     "Yes",
+	})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:t xml:space="preserve">Mr</w:t>`)
+	assert.Contains(documentXml, `<w:t xml:space="preserve">WATKINS</w:t>`)
+	assert.Contains(documentXml, `<w:tr><w:tc><w:p><w:r><w:t xml:space="preserve">Thank you for your payment</w:t></w:r></w:p></w:tc></w:tr>`)
+	assert.NotContains(documentXml, "NEXT")
+	assert.Contains(documentXml, `MERGEFIELD Balance`)
+}
+
+func TestParseWithOptions(t *testing.T) {
+	// A template edited with tracked changes on and commented on
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(t, err, "Parsing error")
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p>` +
+		`<w:r><w:t xml:space="preserve">Client: </w:t></w:r><w:commentRangeStart w:id="0"/>` +
+		`<w:ins w:id="1" w:author="Jane"><w:r><w:t>{{ .Cli</w:t></w:r></w:ins><w:r><w:t>ent }}</w:t></w:r>` +
+		`<w:del w:id="2" w:author="Jane"><w:r><w:delText>{{ .Status }}</w:delText></w:r></w:del>` +
+		`<w:commentRangeEnd w:id="0"/><w:r><w:commentReference w:id="0"/></w:r>` +
+		`</w:p></w:body></w:document>`)
+	require.Nil(t, err)
+	buf := &bytes.Buffer{}
+	require.Nil(t, doc.Save(buf))
+
+	data := map[string]any{
+		"Client": "TW Software",
+		"Status": "New",
+	}
+
+	tests := []struct {
+		name             string
+		options          []ParseOption
+		expectedContains []string
+		expectedMissing  []string
+	}{
+		{
+			name:             "Accept revisions",
+			options:          []ParseOption{WithAcceptedRevisions()},
+			expectedContains: []string{"<w:t>TW Software</w:t>", "<w:commentReference"},
+			expectedMissing:  []string{"<w:ins", "<w:del", "New"},
+		},
+		{
+			name:             "Reject revisions",
+			options:          []ParseOption{WithRejectedRevisions()},
+			expectedContains: []string{"<w:t>New</w:t>", "<w:t>ent }}</w:t>"},
+			expectedMissing:  []string{"<w:ins", "<w:del", "TW Software"},
+		},
+		{
+			name:             "Remove comments",
+			options:          []ParseOption{WithAcceptedRevisions(), WithoutComments()},
+			expectedContains: []string{"<w:t>TW Software</w:t>"},
+			expectedMissing:  []string{"<w:comment"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			doc, err := Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()), tt.options...)
+			require.Nil(err, "Parsing error")
+
+			err = doc.Render(data)
+			require.Nil(err, "Rendering error")
+
+			documentXml, err := doc.docx.GetDocumentXml()
+			require.Nil(err)
+			for _, s := range tt.expectedContains {
+				assert.Contains(documentXml, s)
+			}
+			for _, s := range tt.expectedMissing {
+				assert.NotContains(documentXml, s)
+			}
+		})
+	}
+}
+
+func TestRenderNestedContent(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	// A nested table, a text box and a content control, each with a tag split across runs
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:tbl><w:tr><w:tc><w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{ .Cl</w:t></w:r><w:r><w:t>ient }}</w:t></w:r></w:p></w:tc></w:tr></w:tbl><w:p/></w:tc></w:tr></w:tbl>` +
+		`<w:p><w:r><w:pict><v:shape xmlns:v="urn:schemas-microsoft-com:vml"><v:textbox><w:txbxContent><w:p><w:r><w:t>{{ .Sta</w:t></w:r><w:r><w:t>tus }}</w:t></w:r></w:p></w:txbxContent></v:textbox></v:shape></w:pict></w:r></w:p>` +
+		`<w:sdt><w:sdtContent><w:p><w:r><w:t>{{ .Project</w:t></w:r><w:r><w:t>Number }}</w:t></w:r></w:p></w:sdtContent></w:sdt>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{
+		"ProjectNumber": "B-00001",
+		"Client":        "TW Software",
+		"Status":        "New",
+	})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, "<w:t>TW Software</w:t>")
+	assert.Contains(documentXml, "<w:t>New</w:t>")
+	assert.Contains(documentXml, "<w:t>B-00001</w:t>")
+}
+
+func TestRenderNotes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>{{ .Client }}{{ footnote .Source }} and more text{{ endnote "See appendix" }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{
+		"Client": "TW Software",
+		"Source": "Companies & Co register",
+	})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:r><w:rPr><w:b/></w:rPr><w:t>TW Software</w:t></w:r>`+
+		`<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/><w:b/></w:rPr><w:footnoteReference w:id="1"/></w:r>`+
+		`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"> and more text</w:t></w:r>`+
+		`<w:r><w:rPr><w:rStyle w:val="EndnoteReference"/><w:b/></w:rPr><w:endnoteReference w:id="1"/></w:r>`)
+
+	xmlDocx := doc.docx.(*docxwrappers.XmlDocx)
+	footnotesXml, err := xmlDocx.GetPartXml("word/footnotes.xml")
+	require.Nil(err)
+	assert.Contains(footnotesXml, "Companies &amp; Co register")
+	endnotesXml, err := xmlDocx.GetPartXml("word/endnotes.xml")
+	require.Nil(err)
+	assert.Contains(endnotesXml, "See appendix")
+
+	// Tags in the footnotes themselves are rendered too
+	err = xmlDocx.ReplacePartXml("word/footnotes.xml", `<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+		`<w:footnote w:id="1"><w:p><w:r><w:t>{{ .Cli</w:t></w:r><w:r><w:t>ent }}</w:t></w:r></w:p></w:footnote></w:footnotes>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"Client": "TW Software"})
+	require.Nil(err, "Rendering error")
+
+	footnotesXml, err = xmlDocx.GetPartXml("word/footnotes.xml")
+	require.Nil(err)
+	assert.Contains(footnotesXml, "<w:t>TW Software</w:t>")
+}
+
+func TestRenderComments(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ range .Clauses }}{{ if .Flag }}{{ comment "Compliance" .Flag }}{{ end }}{{ .Text }}{{ if .Flag }}{{ endcomment }}{{ end }} {{ end }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{
+		"Clauses": []map[string]any{
+			{"Text": "Payment is due in 30 days.", "Flag": ""},
+			{"Text": "Liability is unlimited.", "Flag": "Review the liability cap"},
+		},
+	})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:r><w:t xml:space="preserve">Payment is due in 30 days. </w:t></w:r>`+
+		`<w:commentRangeStart w:id="0"/><w:r><w:t xml:space="preserve">Liability is unlimited.</w:t></w:r><w:commentRangeEnd w:id="0"/>`+
+		`<w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:commentReference w:id="0"/></w:r>`)
+
+	commentsXml, err := doc.docx.(*docxwrappers.XmlDocx).GetPartXml("word/comments.xml")
+	require.Nil(err)
+	assert.Contains(commentsXml, `w:author="Compliance"`)
+	assert.Contains(commentsXml, "Review the liability cap")
+}
+
+func TestRenderUnendedComment(t *testing.T) {
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(t, err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ comment "Compliance" "Check" }}{{ .Client }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(t, err)
+
+	err = doc.Render(map[string]any{"Client": "TW Software"})
+	assert.Error(t, err)
+}
+
 func TestProcessTemplateData(t *testing.T) {
 	docxWrappers := getWrappers()
 	tests := []struct {
@@ -465,6 +845,38 @@ func TestProcessTemplateData(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Struct with a date",
+			dataFn: func() any {
+				return struct {
+					ProjectNumber string
+					CreatedAt     time.Time
+				}{
+					ProjectNumber: "A-0001",
+					CreatedAt:     time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+				}
+			},
+			expectedData: map[string]any{
+				"ProjectNumber": "A-0001",
+				"CreatedAt":     time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Struct with slices of values",
+			dataFn: func() any {
+				return struct {
+					Tags    []string
+					Amounts []float64
+				}{
+					Tags:    []string{"R&D", "Sales"},
+					Amounts: []float64{10.5, 20},
+				}
+			},
+			expectedData: map[string]any{
+				"Tags":    []any{"R&amp;D", "Sales"},
+				"Amounts": []float64{10.5, 20},
+			},
+		},
 		{
 			name: "Struct with nested map",
 			dataFn: func() any {
@@ -563,6 +975,28 @@ func TestProcessTemplateData(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Map with nested struct pointer slice",
+			dataFn: func() any {
+				type person struct {
+					Name string
+				}
+				return map[string]any{
+					"People": []*person{
+						{
+							Name: "Tom & Evie",
+						},
+					},
+				}
+			},
+			expectedData: map[string]any{
+				"People": []map[string]any{
+					{
+						"Name": "Tom &amp; Evie",
+					},
+				},
+			},
+		},
 		{
 			name: "Map with nested map",
 			dataFn: func() any {
@@ -628,6 +1062,40 @@ func TestProcessTemplateData(t *testing.T) {
 	}
 }
 
+func TestRenderEscapesNestedValues(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ range .Rows }}{{ index . 0 }};{{ end }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ .Labels.Client }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ range .Groups.Tags }}{{ . }};{{ end }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ range .People }}{{ .Name }}{{ end }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ index .Totals 0 }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{
+		"Rows":   [][]string{{"a<b"}, {"c & d"}},
+		"Labels": map[string]string{"Client": "Smith & Sons <Ltd>"},
+		"Groups": map[string][]string{"Tags": {"x<y"}},
+		"People": []map[string]string{{"Name": "Tom & Evie"}},
+		"Totals": []float64{1.5},
+	})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:t>a&lt;b;c &amp; d;</w:t>`)
+	assert.Contains(documentXml, `<w:t>Smith &amp; Sons &lt;Ltd&gt;</w:t>`)
+	assert.Contains(documentXml, `<w:t>x&lt;y;</w:t>`)
+	assert.Contains(documentXml, `<w:t>Tom &amp; Evie</w:t>`)
+	assert.Contains(documentXml, `<w:t>1.5</w:t>`)
+}
+
 func BenchmarkParseAndRender(b *testing.B) {
 	docxWrappers := getWrappers()
 
@@ -677,3 +1145,339 @@ func BenchmarkParseAndRender(b *testing.B) {
 		}
 	}
 }
+
+func TestRenderDocumentProperties(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	// Tags already in the template's properties are filled in too
+	docx := doc.docx.(*docxwrappers.XmlDocx)
+	coreXml, err := docx.GetPartXml("docProps/core.xml")
+	require.Nil(err)
+	err = docx.ReplacePartXml("docProps/core.xml", strings.Replace(coreXml, "<dc:subject/>", "<dc:subject>Quote for {{ .Client }}</dc:subject>", 1))
+	require.Nil(err)
+
+	data := map[string]any{"ProjectNumber": "B-00001", "Client": "Jane's Shop & Co", "Status": "New"}
+	err = doc.Render(data, WithDocumentProperties(DocumentProperties{
+		Title:    "Project {{ .ProjectNumber }}",
+		Modified: time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		Custom:   map[string]any{"Project": "{{ .ProjectNumber }}", "Version": 2},
+	}))
+	require.Nil(err, "Rendering error")
+
+	coreXml, err = docx.GetPartXml("docProps/core.xml")
+	require.Nil(err)
+	assert.Contains(coreXml, `<dc:title>Project B-00001</dc:title>`)
+	assert.Contains(coreXml, `<dc:subject>Quote for Jane's Shop &amp; Co</dc:subject>`)
+	assert.Contains(coreXml, `<dcterms:modified xsi:type="dcterms:W3CDTF">2024-03-01T09:30:00Z</dcterms:modified>`)
+
+	customXml, err := docx.GetPartXml("docProps/custom.xml")
+	require.Nil(err)
+	assert.Contains(customXml, `name="Project"><vt:lpwstr>B-00001</vt:lpwstr>`)
+	assert.Contains(customXml, `name="Version"><vt:i4>2</vt:i4>`)
+}
+
+func TestSetDocumentVariables(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:fldSimple w:instr=" DOCVARIABLE ClientName "><w:r><w:t>Client</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:fldSimple w:instr=" DOCVARIABLE Issued "><w:r><w:t>Date</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:fldSimple w:instr=" DOCPROPERTY Title "><w:r><w:t>Title</w:t></w:r></w:fldSimple></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	data := struct {
+		ClientName string
+		Issued     time.Time
+		Reference  int
+	}{"TW Software", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 1042}
+	err = doc.SetDocumentVariables(data)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"ProjectNumber": "B-00001"}, WithDocumentProperties(DocumentProperties{Title: "Project {{ .ProjectNumber }}"}))
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:t>TW Software</w:t>`)
+	assert.Contains(documentXml, `<w:t>2024-03-01</w:t>`)
+	assert.Contains(documentXml, `<w:t>Project B-00001</w:t>`)
+
+	settingsXml, err := doc.docx.(*docxwrappers.XmlDocx).GetPartXml("word/settings.xml")
+	require.Nil(err)
+	assert.Contains(settingsXml, `<w:docVar w:name="Reference" w:val="1042"/>`)
+
+	err = doc.SetDocumentVariables(map[string]any{"Items": []string{"Widgets"}})
+	assert.EqualError(err, `document variable "Items": []string values can't be stored`)
+}
+
+func TestTableOfContents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ toc 1 2 }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ range .Sections }}</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>{{ . }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t xml:space="preserve">Figure </w:t></w:r><w:fldSimple w:instr=" SEQ Figure "><w:r><w:t>9</w:t></w:r></w:fldSimple></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"Sections": []string{"Introduction", "Costs & Timescales"}}, WithUpdateFieldsOnOpen())
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:instrText xml:space="preserve"> TOC \o "1-2" \h \z \u </w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>Introduction</w:t></w:r></w:p>`)
+	assert.Contains(documentXml, `<w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr><w:r><w:t>Costs &amp; Timescales</w:t></w:r></w:p>`)
+	assert.Contains(documentXml, `<w:fldSimple w:instr=" SEQ Figure "><w:r><w:t>1</w:t></w:r></w:fldSimple>`)
+
+	settingsXml, err := doc.docx.(*docxwrappers.XmlDocx).GetPartXml("word/settings.xml")
+	require.Nil(err)
+	assert.Contains(settingsXml, `<w:updateFields w:val="true"/>`)
+}
+
+func TestBookmarks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ range .Sections }}{{ link (print "Section" .Id) .Title }} {{ end }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t xml:space="preserve">See {{ ref "Section2" }} for prices.</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ range .Sections }}</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>{{ bookmark (print "Section" .Id) }}{{ .Title }}{{ endbookmark }}</w:t></w:r></w:p>` +
+		`<w:p><w:bookmarkStart w:id="0" w:name="_Hlk1"/><w:r><w:t>Details</w:t></w:r><w:bookmarkEnd w:id="0"/></w:p>` +
+		`<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"Sections": []map[string]any{
+		{"Id": 1, "Title": "Scope"},
+		{"Id": 2, "Title": "Costs & Timescales"},
+	}})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:hyperlink w:anchor="Section2" w:history="1"><w:r><w:rPr><w:rStyle w:val="Hyperlink"/></w:rPr><w:t xml:space="preserve">Costs &amp; Timescales</w:t></w:r></w:hyperlink>`)
+	assert.Contains(documentXml, `<w:bookmarkStart w:id="1" w:name="Section1"/><w:r><w:t xml:space="preserve">Scope</w:t></w:r><w:bookmarkEnd w:id="1"/>`)
+	assert.Contains(documentXml, `<w:bookmarkStart w:id="2" w:name="Section2"/><w:r><w:t xml:space="preserve">Costs &amp; Timescales</w:t></w:r><w:bookmarkEnd w:id="2"/>`)
+	assert.Contains(documentXml, `<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>Costs &amp; Timescales</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>`)
+	assert.Equal(1, strings.Count(documentXml, `w:name="_Hlk1"`))
+	assert.Equal(1, strings.Count(documentXml, `<w:bookmarkEnd w:id="0"/>`))
+}
+
+func TestStyles(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ toc }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ range .Sections }}{{ style "Heading 2" .Title }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t xml:space="preserve">Owner: {{ charStyle "Heading1Char" .Owner }}{{ end }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"Sections": []map[string]any{
+		{"Title": "Scope", "Owner": "Tom"},
+		{"Title": "Costs & Timescales", "Owner": "Sam"},
+	}})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t xml:space="preserve">Costs &amp; Timescales</w:t></w:r></w:p>`)
+	assert.Contains(documentXml, `<w:r><w:t xml:space="preserve">Owner: </w:t></w:r><w:r><w:rPr><w:rStyle w:val="Heading1Char"/></w:rPr><w:t xml:space="preserve">Sam</w:t></w:r>`)
+	assert.Contains(documentXml, `<w:p><w:pPr><w:pStyle w:val="TOC2"/></w:pPr><w:r><w:t>Costs &amp; Timescales</w:t></w:r></w:p>`)
+
+	doc, err = ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ style "Subtitle" .Title }}</w:t></w:r></w:p></w:body></w:document>`)
+	require.Nil(err)
+	err = doc.Render(map[string]any{"Title": "Scope"})
+	assert.ErrorContains(err, `style "Subtitle" is not defined in the template`)
+}
+
+func TestLists(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ list .Features }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ .Steps }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ numberedList .Features }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{
+		"Features": []any{"Fast", []string{"Cached", "Concurrent"}, "Safe & sound"},
+		"Steps":    List{Items: []string{"Measure", "Cut"}, Numbered: true},
+	})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	listParagraph := func(level int, numId int, text string) string {
+		return fmt.Sprintf(`<w:p><w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="%d"/><w:numId w:val="%d"/></w:numPr></w:pPr>`+
+			`<w:r><w:t xml:space="preserve">%s</w:t></w:r></w:p>`, level, numId, text)
+	}
+	// The list in the data is added before the document is rendered, so is numbered first
+	assert.Contains(documentXml, `<w:body>`+listParagraph(0, 2, "Fast")+listParagraph(1, 2, "Cached")+listParagraph(1, 2, "Concurrent")+
+		listParagraph(0, 2, "Safe &amp; sound")+listParagraph(0, 1, "Measure")+listParagraph(0, 1, "Cut")+listParagraph(0, 3, "Fast"))
+
+	numberingXml, err := doc.docx.(*docxwrappers.XmlDocx).GetPartXml("word/numbering.xml")
+	require.Nil(err)
+	assert.Contains(numberingXml, `<w:num w:numId="3"><w:abstractNumId w:val="0"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride></w:num>`)
+}
+
+func TestListsNumberedByStyleRestartInLoops(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	// Add a numbered list definition and a List Number style which uses it
+	xmlDocx := doc.docx.(*docxwrappers.XmlDocx)
+	_, err = xmlDocx.AddList([]string{"Item"}, true)
+	require.Nil(err)
+	stylesXml, err := xmlDocx.GetPartXml("word/styles.xml")
+	require.Nil(err)
+	stylesXml = strings.Replace(stylesXml, "</w:styles>", `<w:style w:type="paragraph" w:styleId="ListNumber"><w:name w:val="List Number"/>`+
+		`<w:basedOn w:val="Normal"/><w:pPr><w:numPr><w:numId w:val="1"/></w:numPr></w:pPr></w:style></w:styles>`, 1)
+	err = xmlDocx.ReplacePartXml("word/styles.xml", stylesXml)
+	require.Nil(err)
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ range .Customers }}</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>{{ .Name }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ range .Orders }}</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:pStyle w:val="ListNumber"/></w:pPr><w:r><w:t>{{ . }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"Customers": []map[string]any{
+		{"Name": "Acme", "Orders": []string{"A-1", "A-2"}},
+		{"Name": "Globex", "Orders": []string{"G-1"}},
+	}})
+	require.Nil(err, "Rendering error")
+
+	// Each customer's orders are given numbering of their own which starts from 1
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Equal(2, strings.Count(documentXml, `<w:pPr><w:pStyle w:val="ListNumber"/><w:numPr><w:numId w:val="2"/></w:numPr></w:pPr>`))
+	assert.Equal(1, strings.Count(documentXml, `<w:pPr><w:pStyle w:val="ListNumber"/><w:numPr><w:numId w:val="3"/></w:numPr></w:pPr>`))
+
+	numberingXml, err := xmlDocx.GetPartXml("word/numbering.xml")
+	require.Nil(err)
+	assert.Contains(numberingXml, `<w:num w:numId="3"><w:abstractNumId w:val="0"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride>`)
+}
+
+func TestListsInStructData(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ .Steps }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(struct{ Steps List }{List{Items: []string{"A & B"}, Numbered: true}})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">A &amp; B</w:t></w:r>`)
+	assert.NotContains(documentXml, "Numbered")
+}
+
+func TestListsWithSprigFunctions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+	doc.RegisterSprigFunctions()
+
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ list .Features }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ .Features | first }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"Features": []string{"A", "B"}})
+	require.Nil(err, "Rendering error")
+
+	// The Word list takes precedence over the Sprig list function, while other Sprig functions still work
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Contains(documentXml, `<w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">A</w:t></w:r>`)
+	assert.Contains(documentXml, `<w:t>A</w:t>`)
+	assert.NotContains(documentXml, "[A B]")
+}
+
+func TestListsRestartInLoops(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.Nil(err, "Parsing error")
+
+	// Add a numbered list definition for the template to use
+	xmlDocx := doc.docx.(*docxwrappers.XmlDocx)
+	_, err = xmlDocx.AddList([]string{"Item"}, true)
+	require.Nil(err)
+
+	item := `<w:p><w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>{{ . }}</w:t></w:r></w:p>`
+	err = doc.docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>{{ range .Customers }}</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>{{ .Name }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ range .Orders }}</w:t></w:r></w:p>` + item + `<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.Nil(err)
+
+	err = doc.Render(map[string]any{"Customers": []map[string]any{
+		{"Name": "Acme", "Orders": []string{"A-1", "A-2"}},
+		{"Name": "Globex", "Orders": []string{"G-1"}},
+	}})
+	require.Nil(err, "Rendering error")
+
+	documentXml, err := doc.docx.GetDocumentXml()
+	require.Nil(err)
+	assert.Equal(2, strings.Count(documentXml, `<w:numId w:val="2"/>`))
+	assert.Equal(1, strings.Count(documentXml, `<w:numId w:val="3"/>`))
+	assert.NotContains(documentXml, `<w:numId w:val="1"/>`)
+	assert.False(strings.ContainsAny(documentXml, "\ue004\ue005\ue006"), "Markers should be removed")
+
+	numberingXml, err := xmlDocx.GetPartXml("word/numbering.xml")
+	require.Nil(err)
+	assert.Contains(numberingXml, `<w:num w:numId="3"><w:abstractNumId w:val="0"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride>`)
+}
@@ -5,7 +5,9 @@ import (
 	"maps"
 	"text/template"
 
+	"github.com/tomwatkins1994/go-docx-template/internal/docxwrappers"
 	"github.com/tomwatkins1994/go-docx-template/internal/functions"
+	"golang.org/x/text/language"
 )
 
 // Register a function which can then be used within your template
@@ -30,9 +32,80 @@ func (d *DocxTmpl) RegisterFunction(name string, fn any) error {
 	return nil
 }
 
+// Register the Sprig compatible function library (default, join, dict, add etc.) for use within your template.
+// See https://masterminds.github.io/sprig/ for the functions available.
+// Any functions registered with RegisterFunction using the same names will be overridden. Functions which
+// add content to the document, such as list, take precedence over Sprig functions with the same names.
+//
+//	doc.RegisterSprigFunctions()
+func (d *DocxTmpl) RegisterSprigFunctions() {
+	d.sprigFuncMap = functions.SprigFuncMap
+	for name := range functions.SprigFuncMap {
+		delete(d.funcMap, name)
+	}
+}
+
 // Get a pointer to the documents function map. This will include built-in functions.
 func (d *DocxTmpl) GetRegisteredFunctions() *template.FuncMap {
-	copiedFuncMap := make(template.FuncMap)
-	maps.Copy(copiedFuncMap, d.funcMap)
+	copiedFuncMap := d.getFuncMap(newRenderOptions())
 	return &copiedFuncMap
 }
+
+// Get the functions available when rendering. Registered functions take precedence over Sprig functions,
+// which take precedence over built-in ones.
+func (d *DocxTmpl) getFuncMap(options *renderOptions) template.FuncMap {
+	funcMap := functions.NewFuncMap(options.locale)
+	maps.Copy(funcMap, d.sprigFuncMap)
+	maps.Copy(funcMap, d.funcMap)
+	return funcMap
+}
+
+// Get the functions available when rendering the main document. These also include functions which add
+// content elsewhere, fields or paragraphs, such as footnote, comment, bookmark, style, list and toc, if the
+// document supports them. These take precedence over Sprig functions with the same names.
+func (d *DocxTmpl) getDocumentFuncMap(options *renderOptions) template.FuncMap {
+	funcMap := functions.NewFuncMap(options.locale)
+	maps.Copy(funcMap, d.sprigFuncMap)
+	if notes, ok := d.docx.(docxwrappers.NotesDocxWrapper); ok {
+		funcMap["footnote"] = notes.AddFootnote
+		funcMap["endnote"] = notes.AddEndnote
+	}
+	if comments, ok := d.docx.(docxwrappers.CommentsDocxWrapper); ok {
+		funcMap["comment"] = comments.AddComment
+		funcMap["endcomment"] = comments.EndComment
+	}
+	if bookmarks, ok := d.docx.(docxwrappers.BookmarksDocxWrapper); ok {
+		funcMap["bookmark"] = bookmarks.AddBookmark
+		funcMap["endbookmark"] = bookmarks.EndBookmark
+		funcMap["link"] = bookmarks.AddBookmarkLink
+		funcMap["ref"] = bookmarks.AddBookmarkReference
+	}
+	if styles, ok := d.docx.(docxwrappers.StylesDocxWrapper); ok {
+		funcMap["style"] = styles.AddStyledParagraph
+		funcMap["charStyle"] = styles.AddStyledRun
+	}
+	if lists, ok := d.docx.(docxwrappers.ListsDocxWrapper); ok {
+		funcMap["list"] = func(items any) (string, error) {
+			return lists.AddList(items, false)
+		}
+		funcMap["numberedList"] = func(items any) (string, error) {
+			return lists.AddList(items, true)
+		}
+	}
+	if fields, ok := d.docx.(docxwrappers.FieldsDocxWrapper); ok {
+		funcMap["toc"] = fields.AddTableOfContents
+	}
+	maps.Copy(funcMap, d.funcMap)
+	return funcMap
+}
+
+// Converts numbers into words for the spell, ordinal, spellOrdinal and spellCurrency functions.
+type NumberSpeller = functions.NumberSpeller
+
+// Register a NumberSpeller to use when rendering with a locale. Spellers are looked up by
+// the full locale (e.g. "en-US") and then by the base language (e.g. "fr"), falling back to English.
+//
+//	docxtpl.RegisterNumberSpeller(language.French, frenchSpeller{})
+func RegisterNumberSpeller(locale language.Tag, speller NumberSpeller) {
+	functions.RegisterNumberSpeller(locale, speller)
+}
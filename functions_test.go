@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
 )
 
 func TestRegisterFunctions(t *testing.T) {
@@ -56,3 +58,59 @@ func TestRegisterFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterSprigFunctions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := ParseFromFilename("test_templates/test_basic.docx")
+	require.NoError(err)
+
+	funcMap := doc.GetRegisteredFunctions()
+	_, foundInFunctionMap := (*funcMap)["coalesce"]
+	assert.False(foundInFunctionMap)
+
+	doc.RegisterSprigFunctions()
+
+	funcMap = doc.GetRegisteredFunctions()
+	for _, fnName := range []string{"default", "coalesce", "join", "dict", "add", "sum"} {
+		_, foundInFunctionMap := (*funcMap)[fnName]
+		assert.True(foundInFunctionMap, fnName)
+	}
+}
+
+func TestGetFuncMap(t *testing.T) {
+	t.Run("Built-in functions should use the render locale", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		doc, err := ParseFromFilename("test_templates/test_basic.docx")
+		require.NoError(err)
+
+		funcMap := doc.getFuncMap(newRenderOptions(WithLocale(language.German)))
+		formatCurrency, ok := funcMap["formatCurrency"].(func(any, string) (string, error))
+		require.True(ok)
+
+		result, err := formatCurrency(1234.56, "EUR")
+		assert.NoError(err)
+		assert.Equal("1.234,56\u00a0€", result)
+	})
+
+	t.Run("Registered functions should override built-in functions", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		doc, err := ParseFromFilename("test_templates/test_basic.docx")
+		require.NoError(err)
+
+		err = doc.RegisterFunction("title", func(text string) string {
+			return "Overridden"
+		})
+		require.NoError(err)
+
+		funcMap := doc.getFuncMap(newRenderOptions())
+		title, ok := funcMap["title"].(func(string) string)
+		require.True(ok)
+		assert.Equal("Overridden", title("tom"))
+	})
+}
@@ -7,15 +7,12 @@ toolchain go1.25.5
 require (
 	github.com/bep/imagemeta v0.8.1
 	github.com/dlclark/regexp2 v1.11.4
-	github.com/fumiama/go-docx v0.0.0-20240924153044-f7d29bb5c371
 	github.com/fumiama/imgsz v0.0.2
 	golang.org/x/image v0.21.0
 )
 
 require golang.org/x/text v0.19.0
 
-require github.com/gomutex/godocx v0.1.6-0.20250811222946-aefd2d814cd1
-
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
@@ -0,0 +1,100 @@
+package contentcontrols
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/functions"
+)
+
+// The functions called by the tags which AddTags adds.
+var FuncMap = template.FuncMap{
+	"contentControlHasValue": hasValue,
+	"contentControlValue":    getValue,
+	"contentControlChecked":  isChecked,
+	"contentControlDate":     formatDate,
+	"contentControlFullDate": formatFullDate,
+	"contentControlListItem": getListItemText,
+}
+
+// Get a value from the data by its key. Keys come from the document so are XML escaped.
+func lookup(data any, key string) (any, bool) {
+	key = html.UnescapeString(key)
+	if m, ok := data.(map[string]any); ok {
+		value, ok := m[key]
+		return value, ok
+	}
+
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String {
+		if v := value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key())); v.IsValid() {
+			return v.Interface(), true
+		}
+	}
+	return nil, false
+}
+
+func hasValue(data any, key string) bool {
+	value, ok := lookup(data, key)
+	return ok && value != nil
+}
+
+func getValue(data any, key string) any {
+	value, _ := lookup(data, key)
+	return value
+}
+
+// Whether the value of a check box is true. Strings such as "false", "no" and "0" are false.
+func isChecked(data any, key string) bool {
+	value, _ := lookup(data, key)
+	return isTrue(value)
+}
+
+func isTrue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "", "false", "no", "off", "0":
+			return false
+		}
+		return true
+	}
+	return !reflect.ValueOf(value).IsZero()
+}
+
+// Format a date with a format from a date picker, e.g. "dd/MM/yyyy".
+func formatDate(format string, value any) (string, error) {
+	t, err := functions.ToTime(value)
+	if err != nil {
+		return "", err
+	}
+	return functions.FormatWordDate(t, format), nil
+}
+
+// Format a date as date pickers store it.
+func formatFullDate(value any) (string, error) {
+	t, err := functions.ToTime(value)
+	if err != nil {
+		return "", err
+	}
+	return t.Format("2006-01-02") + "T00:00:00Z", nil
+}
+
+// Get the display text of a drop-down list item from its value or display text. Values which
+// aren't in the list are shown as they are.
+func getListItemText(value any, items ...string) string {
+	text := fmt.Sprint(value)
+	for i := 0; i+1 < len(items); i += 2 {
+		if items[i] == text || items[i+1] == text {
+			return items[i+1]
+		}
+	}
+	return text
+}
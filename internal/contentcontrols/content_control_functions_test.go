@@ -0,0 +1,49 @@
+package contentcontrols
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	assert := assert.New(t)
+
+	data := map[string]any{"Client": "TW Software", "Terms & Conditions": "Agreed", "Empty": nil}
+	assert.True(hasValue(data, "Client"))
+	assert.True(hasValue(data, "Terms &amp; Conditions"))
+	assert.False(hasValue(data, "Empty"))
+	assert.False(hasValue(data, "Missing"))
+	assert.False(hasValue("text", "Client"))
+	assert.Equal("TW Software", getValue(data, "Client"))
+	assert.Equal("Agreed", getValue(map[string]string{"Status": "Agreed"}, "Status"))
+}
+
+func TestIsChecked(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected bool
+	}{
+		{true, true},
+		{false, false},
+		{"Yes", true},
+		{"false", false},
+		{"0", false},
+		{1, true},
+		{0, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, isChecked(map[string]any{"Approved": tt.value}, "Approved"), "%v", tt.value)
+	}
+}
+
+func TestGetListItemText(t *testing.T) {
+	assert := assert.New(t)
+
+	items := []string{"open", "Open for review", "closed", "Closed"}
+	assert.Equal("Open for review", getListItemText("open", items...))
+	assert.Equal("Closed", getListItemText("Closed", items...))
+	assert.Equal("pending", getListItemText("pending", items...))
+}
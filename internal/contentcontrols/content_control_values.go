@@ -0,0 +1,74 @@
+package contentcontrols
+
+import (
+	"fmt"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/functions"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// Set the value shown by a content control, e.g. from the custom XML it is bound to. The value is
+// text as it is stored in XML, so dates are ISO 8601 and check boxes are "true" or "1" when ticked.
+// Controls keep their content when the value is empty.
+func SetValue(sdt *xmltree.Element, value string) {
+	properties := sdt.Find("w:sdtPr")
+	content := sdt.Find("w:sdtContent")
+	if properties == nil || content == nil || value == "" {
+		return
+	}
+	for _, name := range unsupportedTypes {
+		if properties.Find(name) != nil {
+			return
+		}
+	}
+
+	text := value
+	switch {
+	case properties.Find("w14:checkbox") != nil:
+		setCheckbox(properties.Find("w14:checkbox"), content, isTrue(value))
+		return
+	case properties.Find("w:date") != nil:
+		date := properties.Find("w:date")
+		if t, err := functions.ToTime(value); err == nil {
+			text = functions.FormatWordDate(t, getDateFormat(date))
+			fullDate, _ := formatFullDate(t)
+			date.SetAttr("w:fullDate", fullDate)
+		}
+	case getList(properties) != nil:
+		text = getListItemText(value, getListItems(getList(properties))...)
+	}
+
+	valueContent := newValueContent(content.Children, &xmltree.Text{Data: text})
+	if valueContent == nil {
+		return
+	}
+	content.Children = []xmltree.Node{valueContent}
+
+	children := make([]xmltree.Node, 0, len(properties.Children))
+	for _, child := range properties.Children {
+		if element, ok := child.(*xmltree.Element); !ok || !element.Is("w:showingPlcHdr") {
+			children = append(children, child)
+		}
+	}
+	properties.Children = children
+}
+
+// Tick or untick a check box, updating both its state and the symbol shown.
+func setCheckbox(checkbox *xmltree.Element, content *xmltree.Element, checked bool) {
+	state, symbol := "0", getCheckboxSymbol(checkbox.Find("w14:uncheckedState"), "2610")
+	if checked {
+		state, symbol = "1", getCheckboxSymbol(checkbox.Find("w14:checkedState"), "2612")
+	}
+
+	if checkedElement := checkbox.Find("w14:checked"); checkedElement != nil {
+		checkedElement.SetAttr("w14:val", state)
+	} else {
+		checkbox.Children = append([]xmltree.Node{xmltree.NewElement("w14:checked", "w14:val", state)}, checkbox.Children...)
+	}
+
+	if text := xmltree.FindFirst(content.Children, "w:t"); text != nil {
+		text.SetText(string(symbol))
+	} else if sym := xmltree.FindFirst(content.Children, "w:sym"); sym != nil {
+		sym.SetAttr("w:char", fmt.Sprintf("%04X", symbol))
+	}
+}
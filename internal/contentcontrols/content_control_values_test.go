@@ -0,0 +1,60 @@
+package contentcontrols
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputXml          string
+		value             string
+		expectedOutputXml string
+	}{
+		{
+			name:              "Plain text",
+			inputXml:          `<w:sdt><w:sdtPr><w:showingPlcHdr/><w:text/></w:sdtPr><w:sdtContent><w:r><w:rPr><w:rStyle w:val="PlaceholderText"/><w:b/></w:rPr><w:t>Click here</w:t></w:r></w:sdtContent></w:sdt>`,
+			value:             "TW Software",
+			expectedOutputXml: `<w:sdt><w:sdtPr><w:text/></w:sdtPr><w:sdtContent><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">TW Software</w:t></w:r></w:sdtContent></w:sdt>`,
+		},
+		{
+			name:              "Date",
+			inputXml:          `<w:sdt><w:sdtPr><w:date w:fullDate="2020-01-01T00:00:00Z"><w:dateFormat w:val="d MMMM yyyy"/></w:date></w:sdtPr><w:sdtContent><w:r><w:t>1 January 2020</w:t></w:r></w:sdtContent></w:sdt>`,
+			value:             "2024-03-01T00:00:00",
+			expectedOutputXml: `<w:sdt><w:sdtPr><w:date w:fullDate="2024-03-01T00:00:00Z"><w:dateFormat w:val="d MMMM yyyy"/></w:date></w:sdtPr><w:sdtContent><w:r><w:t xml:space="preserve">1 March 2024</w:t></w:r></w:sdtContent></w:sdt>`,
+		},
+		{
+			name:              "Drop-down list",
+			inputXml:          `<w:sdt><w:sdtPr><w:dropDownList><w:listItem w:displayText="Closed" w:value="closed"/></w:dropDownList></w:sdtPr><w:sdtContent><w:r><w:t>Choose</w:t></w:r></w:sdtContent></w:sdt>`,
+			value:             "closed",
+			expectedOutputXml: `<w:sdt><w:sdtPr><w:dropDownList><w:listItem w:displayText="Closed" w:value="closed"/></w:dropDownList></w:sdtPr><w:sdtContent><w:r><w:t xml:space="preserve">Closed</w:t></w:r></w:sdtContent></w:sdt>`,
+		},
+		{
+			name:              "Check box",
+			inputXml:          `<w:sdt><w:sdtPr><w14:checkbox><w14:checked w14:val="0"/></w14:checkbox></w:sdtPr><w:sdtContent><w:r><w:t>` + "\u2610" + `</w:t></w:r></w:sdtContent></w:sdt>`,
+			value:             "true",
+			expectedOutputXml: `<w:sdt><w:sdtPr><w14:checkbox><w14:checked w14:val="1"/></w14:checkbox></w:sdtPr><w:sdtContent><w:r><w:t>` + "\u2612" + `</w:t></w:r></w:sdtContent></w:sdt>`,
+		},
+		{
+			name:              "Empty values leave the content as it is",
+			inputXml:          `<w:sdt><w:sdtPr><w:showingPlcHdr/><w:text/></w:sdtPr><w:sdtContent><w:r><w:t>Click here</w:t></w:r></w:sdtContent></w:sdt>`,
+			value:             "",
+			expectedOutputXml: `<w:sdt><w:sdtPr><w:showingPlcHdr/><w:text/></w:sdtPr><w:sdtContent><w:r><w:t>Click here</w:t></w:r></w:sdtContent></w:sdt>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := xmltree.ParseString(tt.inputXml)
+			require.NoError(t, err)
+
+			SetValue(xmltree.Root(nodes), tt.value)
+
+			assert.Equal(t, tt.expectedOutputXml, xmltree.Marshal(nodes...))
+		})
+	}
+}
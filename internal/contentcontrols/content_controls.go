@@ -0,0 +1,335 @@
+package contentcontrols
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// Content controls which hold something other than text, such as pictures or building blocks,
+// are left as they are.
+var unsupportedTypes = []string{
+	"w:picture",
+	"w:docPartObj",
+	"w:docPartList",
+	"w:group",
+	"w:citation",
+	"w:bibliography",
+	"w:equation",
+	"w15:repeatingSectionItem",
+}
+
+type converter struct {
+	left  string
+	right string
+}
+
+// Turn content controls into tags so they are filled in from the data along with the rest of the
+// document. Each control is filled from the value whose key matches its tag, or its title if it
+// has no tag, and keeps its content when there is no value. Repeating sections are looped over.
+//
+// The tags call the functions in FuncMap, which must be available when the tags are replaced.
+func AddTags(nodes []xmltree.Node, left string, right string) {
+	c := &converter{left, right}
+	c.convertNodes(nodes)
+}
+
+// A text node holding a tag, e.g. c.tag("end") => {{- end -}}
+//
+// The trim markers stop tags inside table rows being taken for table row loops. Content controls
+// never have whitespace next to them which needs to be kept.
+func (c *converter) tag(action string) *xmltree.Text {
+	return &xmltree.Text{Data: c.left + "- " + action + " -" + c.right}
+}
+
+func (c *converter) convertNodes(nodes []xmltree.Node) {
+	for _, node := range nodes {
+		element, ok := node.(*xmltree.Element)
+		if !ok {
+			continue
+		}
+		if element.Is("w:sdt") {
+			c.convertControl(element)
+			continue
+		}
+		c.convertNodes(element.Children)
+	}
+}
+
+// Get the key of the value a content control is filled from.
+func getKey(properties *xmltree.Element) string {
+	for _, name := range []string{"w:tag", "w:alias"} {
+		if element := properties.Find(name); element != nil {
+			if value, _ := element.AttrValue("w:val"); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func (c *converter) convertControl(sdt *xmltree.Element) {
+	properties := sdt.Find("w:sdtPr")
+	content := sdt.Find("w:sdtContent")
+	if content == nil {
+		return
+	}
+	key := ""
+	if properties != nil {
+		key = getKey(properties)
+	}
+
+	if key != "" && properties.Find("w15:repeatingSection") != nil {
+		c.convertRepeatingSection(key, content)
+		return
+	}
+
+	c.convertNodes(content.Children)
+	if key == "" {
+		return
+	}
+	for _, name := range unsupportedTypes {
+		if properties.Find(name) != nil {
+			return
+		}
+	}
+
+	quotedKey := strconv.Quote(key)
+	value := "contentControlValue . " + quotedKey
+	switch {
+	case properties.Find("w14:checkbox") != nil:
+		c.convertCheckbox(quotedKey, properties.Find("w14:checkbox"), content)
+		return
+	case properties.Find("w:date") != nil:
+		date := properties.Find("w:date")
+		c.setAttrTag(date, "w:fullDate", quotedKey, "contentControlFullDate ("+value+")")
+		value = "contentControlDate " + strconv.Quote(getDateFormat(date)) + " (" + value + ")"
+	case getList(properties) != nil:
+		args := []string{"contentControlListItem", "(" + value + ")"}
+		for _, item := range getListItems(getList(properties)) {
+			args = append(args, strconv.Quote(item))
+		}
+		value = strings.Join(args, " ")
+	}
+
+	c.convertText(quotedKey, properties, content, value)
+}
+
+// Get the format a date picker shows dates in.
+func getDateFormat(date *xmltree.Element) string {
+	if dateFormat := date.Find("w:dateFormat"); dateFormat != nil {
+		if format, ok := dateFormat.AttrValue("w:val"); ok {
+			return format
+		}
+	}
+	return "M/d/yyyy"
+}
+
+// Get the properties of a drop-down list or combo box, or nil if the control is neither.
+func getList(properties *xmltree.Element) *xmltree.Element {
+	if list := properties.Find("w:dropDownList"); list != nil {
+		return list
+	}
+	return properties.Find("w:comboBox")
+}
+
+// Get the value and display text of each item in a list, one after the other.
+func getListItems(list *xmltree.Element) []string {
+	items := []string{}
+	for _, item := range list.FindAll("w:listItem") {
+		value, _ := item.AttrValue("w:value")
+		displayText, ok := item.AttrValue("w:displayText")
+		if !ok {
+			displayText = value
+		}
+		items = append(items, value, displayText)
+	}
+	return items
+}
+
+// Replace the content of a control with the value if there is one, formatted like the existing content.
+func (c *converter) convertText(quotedKey string, properties *xmltree.Element, content *xmltree.Element, value string) {
+	valueContent := newValueContent(content.Children, c.tag(value))
+	if valueContent == nil {
+		return
+	}
+
+	// Placeholder text is shown in grey until the control has a value
+	properties.Children = c.wrapNodes(properties.Children, "w:showingPlcHdr", "if not (contentControlHasValue . "+quotedKey+")")
+
+	children := []xmltree.Node{c.tag("if contentControlHasValue . " + quotedKey), valueContent, c.tag("else")}
+	children = append(children, content.Children...)
+	content.Children = append(children, c.tag("end"))
+}
+
+// Wrap the child elements with a name in a condition.
+func (c *converter) wrapNodes(nodes []xmltree.Node, name string, condition string) []xmltree.Node {
+	wrapped := make([]xmltree.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if element, ok := node.(*xmltree.Element); ok && element.Is(name) {
+			wrapped = append(wrapped, c.tag(condition), element, c.tag("end"))
+			continue
+		}
+		wrapped = append(wrapped, node)
+	}
+	return wrapped
+}
+
+// Text which is the result of an expression when there is a value and the original text otherwise,
+// for use in attributes.
+func (c *converter) ifValue(quotedKey string, text string, original string) string {
+	return c.tag("if contentControlHasValue . "+quotedKey).Data + text + c.tag("else").Data + original + c.tag("end").Data
+}
+
+// Set an existing attribute to the result of an expression when there is a value.
+func (c *converter) setAttrTag(element *xmltree.Element, name string, quotedKey string, expression string) {
+	if original, ok := element.AttrValue(name); ok {
+		element.SetAttr(name, c.ifValue(quotedKey, c.tag(expression).Data, original))
+	}
+}
+
+// Create the content which shows the value of a control. Its level depends on whether the control is
+// inside a paragraph, holds whole paragraphs or holds a table cell. Controls around table rows can't
+// hold a value so nil is returned.
+func newValueContent(content []xmltree.Node, value xmltree.Node) xmltree.Node {
+	var first *xmltree.Element
+	for _, node := range content {
+		if element, ok := node.(*xmltree.Element); ok {
+			first = element
+			break
+		}
+	}
+
+	switch {
+	case first == nil:
+		return newValueRun(nil, value)
+	case first.Is("w:p"):
+		return newValueParagraph(first, value)
+	case first.Is("w:tc"):
+		cell := xmltree.NewElement("w:tc")
+		if cellProperties := first.Find("w:tcPr"); cellProperties != nil {
+			cell.Children = append(cell.Children, cellProperties.Clone())
+		}
+		cell.Children = append(cell.Children, newValueParagraph(first.Find("w:p"), value))
+		return cell
+	case first.Is("w:tr") || first.Is("w:tbl"):
+		return nil
+	}
+
+	return newValueRun(xmltree.FindFirst(content, "w:r"), value)
+}
+
+func newValueParagraph(paragraph *xmltree.Element, value xmltree.Node) *xmltree.Element {
+	newParagraph := xmltree.NewElement("w:p")
+	var run *xmltree.Element
+	if paragraph != nil {
+		if paragraphProperties := paragraph.Find("w:pPr"); paragraphProperties != nil {
+			newParagraph.Children = append(newParagraph.Children, paragraphProperties.Clone())
+		}
+		run = xmltree.FindFirst(paragraph.Children, "w:r")
+	}
+	newParagraph.Children = append(newParagraph.Children, newValueRun(run, value))
+	return newParagraph
+}
+
+// Create a run holding the value with the formatting of an existing run, apart from the placeholder style.
+func newValueRun(run *xmltree.Element, value xmltree.Node) *xmltree.Element {
+	newRun := xmltree.NewElement("w:r")
+	if run != nil {
+		if runProperties := run.Find("w:rPr"); runProperties != nil {
+			runProperties = runProperties.Clone()
+			children := make([]xmltree.Node, 0, len(runProperties.Children))
+			for _, child := range runProperties.Children {
+				if element, ok := child.(*xmltree.Element); ok && element.Is("w:rStyle") {
+					if style, _ := element.AttrValue("w:val"); style == "PlaceholderText" {
+						continue
+					}
+				}
+				children = append(children, child)
+			}
+			runProperties.Children = children
+			newRun.Children = append(newRun.Children, runProperties)
+		}
+	}
+
+	text := xmltree.NewElement("w:t", "xml:space", "preserve")
+	text.Children = []xmltree.Node{value}
+	newRun.Children = append(newRun.Children, text)
+	return newRun
+}
+
+// Tick or untick a check box depending on whether the value is true.
+func (c *converter) convertCheckbox(quotedKey string, checkbox *xmltree.Element, content *xmltree.Element) {
+	checkedSymbol := getCheckboxSymbol(checkbox.Find("w14:checkedState"), "2612")
+	uncheckedSymbol := getCheckboxSymbol(checkbox.Find("w14:uncheckedState"), "2610")
+	ifChecked := func(checked string, unchecked string) string {
+		return c.tag("if contentControlChecked . "+quotedKey).Data + checked + c.tag("else").Data + unchecked + c.tag("end").Data
+	}
+
+	if checked := checkbox.Find("w14:checked"); checked != nil {
+		original, _ := checked.AttrValue("w14:val")
+		checked.SetAttr("w14:val", c.ifValue(quotedKey, ifChecked("1", "0"), original))
+	}
+
+	if text := xmltree.FindFirst(content.Children, "w:t"); text != nil {
+		text.Children = []xmltree.Node{&xmltree.Text{Data: c.ifValue(quotedKey, ifChecked(string(checkedSymbol), string(uncheckedSymbol)), text.Text())}}
+	} else if sym := xmltree.FindFirst(content.Children, "w:sym"); sym != nil {
+		original, _ := sym.AttrValue("w:char")
+		sym.SetAttr("w:char", c.ifValue(quotedKey, ifChecked(fmt.Sprintf("%04X", checkedSymbol), fmt.Sprintf("%04X", uncheckedSymbol)), original))
+	}
+}
+
+// Get the character shown for a check box state, which is stored as a hexadecimal character code.
+func getCheckboxSymbol(state *xmltree.Element, defaultValue string) rune {
+	value := defaultValue
+	if state != nil {
+		if v, ok := state.AttrValue("w14:val"); ok {
+			value = v
+		}
+	}
+	code, err := strconv.ParseInt(value, 16, 32)
+	if err != nil {
+		code, _ = strconv.ParseInt(defaultValue, 16, 32)
+	}
+	return rune(code)
+}
+
+// Loop over the value of a repeating section, repeating its first item for each element.
+func (c *converter) convertRepeatingSection(key string, content *xmltree.Element) {
+	var item *xmltree.Element
+	for _, child := range content.Elements() {
+		if child.Is("w:sdt") {
+			if properties := child.Find("w:sdtPr"); properties != nil && properties.Find("w15:repeatingSectionItem") != nil {
+				item = child
+				break
+			}
+		}
+	}
+	if item == nil {
+		c.convertNodes(content.Children)
+		return
+	}
+
+	original := make([]xmltree.Node, 0, len(content.Children))
+	for _, child := range content.Children {
+		if element, ok := child.(*xmltree.Element); ok {
+			original = append(original, element.Clone())
+		} else {
+			original = append(original, child)
+		}
+	}
+	c.convertNodes([]xmltree.Node{item})
+
+	quotedKey := strconv.Quote(key)
+	children := []xmltree.Node{
+		c.tag("if contentControlHasValue . " + quotedKey),
+		c.tag("range contentControlValue . " + quotedKey),
+		item,
+		c.tag("end"),
+		c.tag("else"),
+	}
+	children = append(children, original...)
+	content.Children = append(children, c.tag("end"))
+}
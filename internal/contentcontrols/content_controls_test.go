@@ -0,0 +1,49 @@
+package contentcontrols
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestAddTags(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:              "Plain text",
+			inputXml:          `<w:sdt><w:sdtPr><w:tag w:val="Client"/><w:showingPlcHdr/><w:text/></w:sdtPr><w:sdtContent><w:r><w:rPr><w:rStyle w:val="PlaceholderText"/></w:rPr><w:t>Click here</w:t></w:r></w:sdtContent></w:sdt>`,
+			expectedOutputXml: `<w:sdt><w:sdtPr><w:tag w:val="Client"/>{{- if not (contentControlHasValue . "Client") -}}<w:showingPlcHdr/>{{- end -}}<w:text/></w:sdtPr><w:sdtContent>{{- if contentControlHasValue . "Client" -}}<w:r><w:rPr/><w:t xml:space="preserve">{{- contentControlValue . "Client" -}}</w:t></w:r>{{- else -}}<w:r><w:rPr><w:rStyle w:val="PlaceholderText"/></w:rPr><w:t>Click here</w:t></w:r>{{- end -}}</w:sdtContent></w:sdt>`,
+		},
+		{
+			name:              "Controls around paragraphs are filled with a paragraph",
+			inputXml:          `<w:sdt><w:sdtPr><w:alias w:val="Notes"/></w:sdtPr><w:sdtContent><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>Notes</w:t></w:r></w:p></w:sdtContent></w:sdt>`,
+			expectedOutputXml: `<w:sdt><w:sdtPr><w:alias w:val="Notes"/></w:sdtPr><w:sdtContent>{{- if contentControlHasValue . "Notes" -}}<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t xml:space="preserve">{{- contentControlValue . "Notes" -}}</w:t></w:r></w:p>{{- else -}}<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>Notes</w:t></w:r></w:p>{{- end -}}</w:sdtContent></w:sdt>`,
+		},
+		{
+			name:              "Controls without a tag or title are left as they are",
+			inputXml:          `<w:sdt><w:sdtPr><w:text/></w:sdtPr><w:sdtContent><w:r><w:t>Text</w:t></w:r></w:sdtContent></w:sdt>`,
+			expectedOutputXml: `<w:sdt><w:sdtPr><w:text/></w:sdtPr><w:sdtContent><w:r><w:t>Text</w:t></w:r></w:sdtContent></w:sdt>`,
+		},
+		{
+			name:              "Picture controls are left as they are",
+			inputXml:          `<w:sdt><w:sdtPr><w:tag w:val="Logo"/><w:picture/></w:sdtPr><w:sdtContent><w:r><w:drawing/></w:r></w:sdtContent></w:sdt>`,
+			expectedOutputXml: `<w:sdt><w:sdtPr><w:tag w:val="Logo"/><w:picture/></w:sdtPr><w:sdtContent><w:r><w:drawing/></w:r></w:sdtContent></w:sdt>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := xmltree.ParseString(tt.inputXml)
+			require.NoError(t, err)
+
+			AddTags(nodes, "{{", "}}")
+
+			assert.Equal(t, tt.expectedOutputXml, xmltree.Marshal(nodes...))
+		})
+	}
+}
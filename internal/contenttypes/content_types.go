@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"slices"
+	"strings"
 )
 
 type ContentTypes struct {
@@ -39,8 +40,7 @@ func GetContentTypes(reader io.ReaderAt, size int64) (*ContentTypes, error) {
 			}
 			defer zf.Close()
 
-			dataBuf := make([]byte, f.UncompressedSize64)
-			_, err = zf.Read(dataBuf)
+			dataBuf, err := io.ReadAll(zf)
 			if err != nil {
 				return nil, err
 			}
@@ -61,14 +61,30 @@ func GetContentTypes(reader io.ReaderAt, size int64) (*ContentTypes, error) {
 var PNG_CONTENT_TYPE = ContentType{Extension: "png", ContentType: "image/png"}
 var JPG_CONTENT_TYPE = ContentType{Extension: "jpg", ContentType: "image/jpg"}
 var JPEG_CONTENT_TYPE = ContentType{Extension: "jpeg", ContentType: "image/jpeg"}
+var XML_CONTENT_TYPE = ContentType{Extension: "xml", ContentType: "application/xml"}
 
+// Add a default content type for an extension. Extensions which already have a content type are
+// left as they are, as a package can't have more than one default for an extension.
 func (ct *ContentTypes) AddContentType(contentType *ContentType) {
-	if slices.Contains(ct.Defaults, *contentType) {
+	if slices.ContainsFunc(ct.Defaults, func(existing ContentType) bool {
+		return strings.EqualFold(existing.Extension, contentType.Extension)
+	}) {
 		return
 	}
 	ct.Defaults = append(ct.Defaults, *contentType)
 }
 
+// Set the content type of a part, e.g. /word/footnotes.xml.
+func (ct *ContentTypes) AddOverride(partName string, contentType string) {
+	for i := range ct.Overrides {
+		if ct.Overrides[i].PartName == partName {
+			ct.Overrides[i].ContentType = contentType
+			return
+		}
+	}
+	ct.Overrides = append(ct.Overrides, Override{PartName: partName, ContentType: contentType})
+}
+
 func (ct *ContentTypes) MarshalXml() (string, error) {
 	output, err := xml.MarshalIndent(ct, "", "  ")
 	if err != nil {
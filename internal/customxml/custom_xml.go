@@ -0,0 +1,130 @@
+package customxml
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+var elementNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9._-]*$`)
+
+// Convert data into the XML of a custom XML part, with a root element in a namespace. Maps and structs
+// become elements named after their keys or fields, slices become repeated elements and other values
+// become text. Struct fields can be renamed or skipped with xml tags, e.g. `xml:"name"` or `xml:"-"`.
+//
+//	Marshal("urn:example:invoice", "invoice", map[string]any{"client": "TW Software"})
+//	=> <invoice xmlns="urn:example:invoice"><client>TW Software</client></invoice>
+func Marshal(namespace string, rootName string, data any) (string, error) {
+	if !elementNameRegex.MatchString(rootName) {
+		return "", fmt.Errorf("%q is not a valid XML element name", rootName)
+	}
+
+	value := indirect(reflect.ValueOf(data))
+	if value.Kind() != reflect.Map && value.Kind() != reflect.Struct {
+		return "", fmt.Errorf("custom XML data must be a map or struct, not %T", data)
+	}
+
+	root := xmltree.NewElement(rootName)
+	if namespace != "" {
+		root.SetAttr("xmlns", namespace)
+	}
+	if err := addChildren(root, value); err != nil {
+		return "", err
+	}
+
+	return `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + xmltree.Marshal(root), nil
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// Add an element for each key of a map or field of a struct.
+func addChildren(element *xmltree.Element, value reflect.Value) error {
+	if value.Kind() == reflect.Map {
+		if value.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("custom XML maps must have string keys, not %s", value.Type().Key())
+		}
+		keys := value.MapKeys()
+		slices.SortFunc(keys, func(a reflect.Value, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		for _, key := range keys {
+			if err := addElements(element, key.String(), value.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("xml"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		if err := addElements(element, name, value.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add the elements for a value, one for each item if it is a slice.
+func addElements(parent *xmltree.Element, name string, value reflect.Value) error {
+	if !elementNameRegex.MatchString(name) {
+		return fmt.Errorf("%q is not a valid XML element name", name)
+	}
+
+	value = indirect(value)
+	if (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && value.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < value.Len(); i++ {
+			if err := addElements(parent, name, value.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	element := xmltree.NewElement(name)
+	parent.Children = append(parent.Children, element)
+	if !value.IsValid() {
+		return nil
+	}
+
+	switch v := value.Interface().(type) {
+	case time.Time:
+		element.SetText(v.Format("2006-01-02T15:04:05"))
+		return nil
+	case []byte:
+		element.SetText(string(v))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Map, reflect.Struct:
+		return addChildren(element, value)
+	case reflect.Bool:
+		element.SetText(strconv.FormatBool(value.Bool()))
+	default:
+		element.SetText(fmt.Sprint(value.Interface()))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package customxml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	type Item struct {
+		Description string `xml:"description"`
+		Quantity    int    `xml:"quantity"`
+		Internal    string `xml:"-"`
+	}
+
+	tests := []struct {
+		name              string
+		namespace         string
+		data              any
+		expectedOutputXml string
+	}{
+		{
+			name:              "Map",
+			namespace:         "urn:example:invoice",
+			data:              map[string]any{"client": "TW Software & Co", "paid": true, "notes": nil},
+			expectedOutputXml: `<invoice xmlns="urn:example:invoice"><client>TW Software &amp; Co</client><notes/><paid>true</paid></invoice>`,
+		},
+		{
+			name:      "Struct with slices",
+			namespace: "",
+			data: struct {
+				Date  time.Time
+				Items []Item `xml:"item"`
+			}{
+				Date:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				Items: []Item{{"Widgets", 2, "x"}, {"Gadgets", 1, "y"}},
+			},
+			expectedOutputXml: `<invoice><Date>2024-03-01T00:00:00</Date><item><description>Widgets</description><quantity>2</quantity></item><item><description>Gadgets</description><quantity>1</quantity></item></invoice>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputXml, err := Marshal(tt.namespace, "invoice", tt.data)
+			assert.NoError(t, err)
+			assert.Equal(t, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+tt.expectedOutputXml, outputXml)
+		})
+	}
+
+	t.Run("Should error for invalid names and data", func(t *testing.T) {
+		_, err := Marshal("", "1invoice", map[string]any{})
+		assert.EqualError(t, err, `"1invoice" is not a valid XML element name`)
+		_, err = Marshal("", "invoice", map[string]any{"client name": "TW Software"})
+		assert.EqualError(t, err, `"client name" is not a valid XML element name`)
+		_, err = Marshal("", "invoice", "TW Software")
+		assert.EqualError(t, err, "custom XML data must be a map or struct, not string")
+	})
+}
@@ -0,0 +1,129 @@
+package customxml
+
+import (
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+var prefixMappingRegex = regexp.MustCompile(`xmlns:([A-Za-z_][\w.-]*)\s*=\s*(?:'([^']*)'|"([^"]*)")`)
+
+var stepRegex = regexp.MustCompile(`^(@?)((?:[A-Za-z_][\w.-]*:)?(?:[A-Za-z_][\w.-]*|\*)|text\(\))(?:\[(\d+)\])?$`)
+
+// Parse the prefix mappings of a data binding, e.g. "xmlns:ns0='urn:example:invoice'".
+func ParsePrefixMappings(prefixMappings string) map[string]string {
+	namespaces := map[string]string{}
+	for _, match := range prefixMappingRegex.FindAllStringSubmatch(prefixMappings, -1) {
+		namespaces[match[1]] = match[2] + match[3]
+	}
+	return namespaces
+}
+
+// Get the text an XPath from a data binding points to, e.g. "/ns0:invoice[1]/ns0:client[1]". Only
+// absolute paths of elements and attributes with positions are supported, as Word creates.
+func Evaluate(nodes []xmltree.Node, xpath string, namespaces map[string]string) (string, bool) {
+	if !strings.HasPrefix(xpath, "/") || strings.HasPrefix(xpath, "//") {
+		return "", false
+	}
+
+	candidates := []*xmltree.Element{}
+	if root := xmltree.Root(nodes); root != nil {
+		candidates = append(candidates, root)
+	}
+	scope := map[string]string{}
+	var current *xmltree.Element
+
+	steps := strings.Split(xpath[1:], "/")
+	for i, step := range steps {
+		match := stepRegex.FindStringSubmatch(step)
+		if match == nil {
+			return "", false
+		}
+		isAttribute, name := match[1] == "@", match[2]
+		last := i == len(steps)-1
+
+		if name == "text()" {
+			if !last || current == nil {
+				return "", false
+			}
+			return current.Text(), true
+		}
+		if isAttribute {
+			if !last || current == nil {
+				return "", false
+			}
+			return getAttr(current, name, scope, namespaces)
+		}
+
+		position := 1
+		if match[3] != "" {
+			position, _ = strconv.Atoi(match[3])
+		}
+		current = nil
+		for _, candidate := range candidates {
+			candidateScope := withDeclarations(scope, candidate)
+			if matchesName(candidate, name, candidateScope, namespaces) {
+				position--
+				if position == 0 {
+					current, scope = candidate, candidateScope
+					break
+				}
+			}
+		}
+		if current == nil {
+			return "", false
+		}
+		candidates = current.Elements()
+	}
+
+	return current.Text(), true
+}
+
+// Add the namespaces declared on an element to those in scope from its ancestors.
+func withDeclarations(scope map[string]string, element *xmltree.Element) map[string]string {
+	newScope := maps.Clone(scope)
+	for _, attr := range element.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			newScope[""] = attr.Value
+		} else if attr.Name.Space == "xmlns" {
+			newScope[attr.Name.Local] = attr.Value
+		}
+	}
+	return newScope
+}
+
+// Whether an element matches a name from an XPath. The prefixes in the XPath come from the binding's
+// prefix mappings, which needn't match the prefixes used in the custom XML.
+func matchesName(element *xmltree.Element, name string, scope map[string]string, namespaces map[string]string) bool {
+	prefix, local, ok := strings.Cut(name, ":")
+	if !ok {
+		prefix, local = "", name
+	}
+	if local != "*" && local != element.Name.Local {
+		return false
+	}
+	if local == "*" && prefix == "" {
+		return true
+	}
+	return namespaces[prefix] == scope[element.Name.Space]
+}
+
+func getAttr(element *xmltree.Element, name string, scope map[string]string, namespaces map[string]string) (string, bool) {
+	prefix, local, ok := strings.Cut(name, ":")
+	if !ok {
+		prefix, local = "", name
+	}
+	for _, attr := range element.Attr {
+		if attr.Name.Local != local || attr.Name.Space == "xmlns" {
+			continue
+		}
+		// Attributes without a prefix aren't in a namespace, even when there is a default namespace
+		if (prefix == "" && attr.Name.Space == "") || (prefix != "" && attr.Name.Space != "" && namespaces[prefix] == scope[attr.Name.Space]) {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,44 @@
+package customxml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestEvaluate(t *testing.T) {
+	nodes, err := xmltree.ParseString(`<?xml version="1.0"?><inv:invoice xmlns:inv="urn:example:invoice" number="B-1">` +
+		`<inv:client>TW Software</inv:client><inv:item><inv:description>Widgets</inv:description></inv:item><inv:item><inv:description>Gadgets</inv:description></inv:item>` +
+		`<other xmlns="urn:example:other">Other</other></inv:invoice>`)
+	require.NoError(t, err)
+
+	namespaces := ParsePrefixMappings(`xmlns:ns0='urn:example:invoice' xmlns:ns1="urn:example:other"`)
+	assert.Equal(t, map[string]string{"ns0": "urn:example:invoice", "ns1": "urn:example:other"}, namespaces)
+
+	tests := []struct {
+		xpath         string
+		expectedValue string
+		expectedOk    bool
+	}{
+		{"/ns0:invoice[1]/ns0:client[1]", "TW Software", true},
+		{"/ns0:invoice/ns0:client", "TW Software", true},
+		{"/ns0:invoice[1]/ns0:item[2]/ns0:description[1]", "Gadgets", true},
+		{"/ns0:invoice[1]/ns0:item[1]/ns0:description[1]/text()", "Widgets", true},
+		{"/ns0:invoice[1]/@number", "B-1", true},
+		{"/ns0:invoice[1]/ns1:other[1]", "Other", true},
+		{"/ns0:invoice[1]/*[1]", "TW Software", true},
+		{"/ns0:invoice[1]/ns0:item[3]", "", false},
+		{"/ns0:invoice[1]/client[1]", "", false},
+		{"/ns1:invoice[1]", "", false},
+		{"//ns0:client", "", false},
+		{"/ns0:invoice[1]/ns0:item[ns0:description='Widgets']", "", false},
+	}
+
+	for _, tt := range tests {
+		value, ok := Evaluate(nodes, tt.xpath, namespaces)
+		assert.Equal(t, tt.expectedOk, ok, tt.xpath)
+		assert.Equal(t, tt.expectedValue, value, tt.xpath)
+	}
+}
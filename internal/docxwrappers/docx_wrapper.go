@@ -4,12 +4,87 @@ import (
 	"io"
 
 	"github.com/tomwatkins1994/go-docx-template/internal/images"
+	"github.com/tomwatkins1994/go-docx-template/internal/properties"
+	"github.com/tomwatkins1994/go-docx-template/internal/tags"
 )
 
 type DocxWrapper interface {
 	GetDocumentXml() (string, error)
 	ReplaceDocumentXml(xmlString string) error
-	MergeTags()
+	MergeTags(delims *tags.Delimiters)
 	AddInlineImage(img *images.InlineImage) (xmlString string, err error)
 	Save(w io.Writer) error
 }
+
+// Implemented by wrappers which can render tags in parts other than the main document,
+// such as footnotes, endnotes and comments.
+type PartsDocxWrapper interface {
+	GetTemplatePartNames() []string
+	GetPartXml(partName string) (string, error)
+	ReplacePartXml(partName string, xmlString string) error
+}
+
+// Implemented by wrappers which can add footnotes and endnotes.
+type NotesDocxWrapper interface {
+	AddFootnote(text string) (xmlString string, err error)
+	AddEndnote(text string) (xmlString string, err error)
+}
+
+// Implemented by wrappers which can add comments.
+type CommentsDocxWrapper interface {
+	AddComment(author string, text string) (xmlString string, err error)
+	EndComment() (xmlString string, err error)
+}
+
+// Implemented by wrappers which can add bookmarks along with links and cross-references to them.
+type BookmarksDocxWrapper interface {
+	AddBookmark(name string) (xmlString string, err error)
+	EndBookmark() (xmlString string, err error)
+	AddBookmarkLink(name string, text string) (xmlString string, err error)
+	AddBookmarkReference(name string) (xmlString string, err error)
+}
+
+// Implemented by wrappers which can add paragraphs and runs in the styles of the template.
+type StylesDocxWrapper interface {
+	AddStyledParagraph(style string, text string) (xmlString string, err error)
+	AddStyledRun(style string, text string) (xmlString string, err error)
+}
+
+// Implemented by wrappers which can add bulleted and numbered lists.
+type ListsDocxWrapper interface {
+	AddList(items any, numbered bool) (xmlString string, err error)
+	GetNumberedStyles() []string
+}
+
+// Implemented by wrappers which can clean up a template before it is rendered.
+type RevisionsDocxWrapper interface {
+	AcceptRevisions() error
+	RejectRevisions() error
+	RemoveComments() error
+}
+
+// Implemented by wrappers which can fill in content controls from the data.
+type ContentControlsDocxWrapper interface {
+	AddContentControlTags(delims *tags.Delimiters)
+}
+
+// Implemented by wrappers which can write custom XML parts for content controls to be bound to.
+type CustomXmlDocxWrapper interface {
+	SetCustomXml(xmlString string) error
+}
+
+// Implemented by wrappers which can set the properties of the document, such as its title and author.
+type PropertiesDocxWrapper interface {
+	PartsDocxWrapper
+	GetPropertiesPartNames() []string
+	SetProperties(properties *properties.Properties) error
+}
+
+// Implemented by wrappers which can add fields and update their results, such as document variables
+// and tables of contents.
+type FieldsDocxWrapper interface {
+	SetDocumentVariables(variables map[string]string) error
+	AddTableOfContents(levels ...int) (xmlString string, err error)
+	SetUpdateFieldsOnOpen() error
+	UpdateFieldResults() error
+}
@@ -0,0 +1,218 @@
+package docxwrappers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+const hyperlinkStyle = "Hyperlink"
+
+// Add a bookmark and return the XML for the start of the content it covers, which is ended by EndBookmark.
+// Names must start with a letter, contain only letters, numbers and underscores, be at most 40 characters
+// long and not already be used in the document.
+func (d *XmlDocx) AddBookmark(name string) (xmlString string, err error) {
+	if err := validateBookmarkName(name); err != nil {
+		return "", err
+	}
+	for _, bookmark := range xmltree.FindDescendants(d.document, "w:bookmarkStart") {
+		if existing, _ := bookmark.AttrValue("w:name"); strings.EqualFold(existing, name) {
+			return "", fmt.Errorf("bookmark %q already exists", name)
+		}
+	}
+	for _, existing := range d.addedBookmarks {
+		if strings.EqualFold(existing, name) {
+			return "", fmt.Errorf("bookmark %q already exists", name)
+		}
+	}
+
+	d.maxBookmarkId++
+	d.addedBookmarks = append(d.addedBookmarks, name)
+	d.openBookmarks = append(d.openBookmarks, d.maxBookmarkId)
+
+	// The range marker is moved out of the run when the document XML is replaced
+	return fmt.Sprintf(`</w:t><w:bookmarkStart w:id="%d" w:name="%s"/><w:t xml:space="preserve">`, d.maxBookmarkId, name), nil
+}
+
+// End the bookmark most recently added by AddBookmark and return the XML for the end of its range.
+func (d *XmlDocx) EndBookmark() (xmlString string, err error) {
+	if len(d.openBookmarks) == 0 {
+		return "", errors.New("no bookmark to end")
+	}
+	id := d.openBookmarks[len(d.openBookmarks)-1]
+	d.openBookmarks = d.openBookmarks[:len(d.openBookmarks)-1]
+
+	return fmt.Sprintf(`</w:t><w:bookmarkEnd w:id="%d"/><w:t xml:space="preserve">`, id), nil
+}
+
+// Return the XML for a link to a bookmark in the document. The text should already be XML escaped.
+func (d *XmlDocx) AddBookmarkLink(name string, text string) (xmlString string, err error) {
+	if err := validateBookmarkName(name); err != nil {
+		return "", err
+	}
+	d.ensureStyle(hyperlinkStyle, "character", "Hyperlink",
+		`<w:basedOn w:val="DefaultParagraphFont"/><w:uiPriority w:val="99"/><w:unhideWhenUsed/><w:rPr><w:color w:val="0563C1" w:themeColor="hyperlink"/><w:u w:val="single"/></w:rPr>`)
+
+	// The hyperlink is moved out of the run when the document XML is replaced, taking its formatting
+	return fmt.Sprintf(
+		`</w:t><w:hyperlink w:anchor="%s" w:history="1"><w:r><w:t xml:space="preserve">%s</w:t></w:r></w:hyperlink><w:t xml:space="preserve">`,
+		name, text,
+	), nil
+}
+
+// Return the XML for a REF field showing the content of a bookmark, which links to it when clicked. Its
+// result is filled in once the document is rendered.
+func (d *XmlDocx) AddBookmarkReference(name string) (xmlString string, err error) {
+	if err := validateBookmarkName(name); err != nil {
+		return "", err
+	}
+
+	// The field characters are moved into runs of their own when the document XML is replaced
+	return fmt.Sprintf(
+		`</w:t><w:fldChar w:fldCharType="begin"/><w:instrText xml:space="preserve"> REF %s \h </w:instrText>`+
+			`<w:fldChar w:fldCharType="separate"/><w:fldChar w:fldCharType="end"/><w:t xml:space="preserve">`,
+		name,
+	), nil
+}
+
+func validateBookmarkName(name string) error {
+	first, _ := utf8.DecodeRuneInString(name)
+	if !unicode.IsLetter(first) || utf8.RuneCountInString(name) > 40 || strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) != -1 {
+		return fmt.Errorf("invalid bookmark name %q: names must start with a letter, only contain letters, numbers and underscores and be at most 40 characters", name)
+	}
+	return nil
+}
+
+// Get the highest bookmark ID used in XML.
+func getMaxBookmarkId(nodes []xmltree.Node) int {
+	maxId := 0
+	for _, bookmark := range xmltree.FindDescendants(nodes, "w:bookmarkStart") {
+		if id, ok := bookmark.AttrValue("w:id"); ok {
+			if n, err := strconv.Atoi(id); err == nil {
+				maxId = max(maxId, n)
+			}
+		}
+	}
+	return maxId
+}
+
+// Give bookmarks repeated by loops IDs of their own and remove repeats of names which are already used,
+// as Word requires both to be unique.
+func (d *XmlDocx) fixRepeatedBookmarks(nodes []xmltree.Node) {
+	d.maxBookmarkId = max(d.maxBookmarkId, getMaxBookmarkId(nodes))
+
+	ids := map[string]bool{}
+	names := map[string]bool{}
+	removed := map[*xmltree.Element]bool{}
+	// The starts which haven't been ended yet, by their original IDs
+	open := map[string]*xmltree.Element{}
+
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		element, ok := node.(*xmltree.Element)
+		if !ok {
+			return false
+		}
+		id, _ := element.AttrValue("w:id")
+		switch element.Tag() {
+		case "w:bookmarkStart":
+			name, _ := element.AttrValue("w:name")
+			open[id] = element
+			switch {
+			case names[strings.ToLower(name)]:
+				removed[element] = true
+			case ids[id]:
+				d.maxBookmarkId++
+				element.SetAttr("w:id", strconv.Itoa(d.maxBookmarkId))
+			}
+			ids[id] = true
+			names[strings.ToLower(name)] = true
+			return false
+		case "w:bookmarkEnd":
+			if start, ok := open[id]; ok {
+				delete(open, id)
+				if removed[start] {
+					removed[element] = true
+				} else {
+					newId, _ := start.AttrValue("w:id")
+					element.SetAttr("w:id", newId)
+				}
+			}
+			return false
+		}
+		return true
+	})
+
+	if len(removed) == 0 {
+		return
+	}
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		element, ok := node.(*xmltree.Element)
+		if !ok {
+			return false
+		}
+		children := make([]xmltree.Node, 0, len(element.Children))
+		for _, child := range element.Children {
+			if childElement, ok := child.(*xmltree.Element); !ok || !removed[childElement] {
+				children = append(children, child)
+			}
+		}
+		element.Children = children
+		return true
+	})
+}
+
+// Get the text of each bookmark in the main document by its name in lower case, for REF fields to show.
+// Text in different paragraphs is separated by a space.
+func (d *XmlDocx) getBookmarkTexts() map[string]string {
+	texts := map[string]*strings.Builder{}
+	open := map[string]*strings.Builder{}
+
+	var visit func(nodes []xmltree.Node)
+	visit = func(nodes []xmltree.Node) {
+		for _, node := range nodes {
+			element, ok := node.(*xmltree.Element)
+			if !ok {
+				continue
+			}
+			id, _ := element.AttrValue("w:id")
+			switch element.Tag() {
+			case "w:bookmarkStart":
+				name, _ := element.AttrValue("w:name")
+				if _, ok := texts[strings.ToLower(name)]; !ok {
+					texts[strings.ToLower(name)] = &strings.Builder{}
+					open[id] = texts[strings.ToLower(name)]
+				}
+			case "w:bookmarkEnd":
+				delete(open, id)
+			case "w:t":
+				for _, text := range open {
+					text.WriteString(element.Text())
+				}
+			case "w:del", "w:moveFrom":
+			case "w:p":
+				for _, text := range open {
+					if text.Len() > 0 && !strings.HasSuffix(text.String(), " ") {
+						text.WriteString(" ")
+					}
+				}
+				visit(element.Children)
+			default:
+				visit(element.Children)
+			}
+		}
+	}
+	visit(d.document)
+
+	values := make(map[string]string, len(texts))
+	for name, text := range texts {
+		values[name] = strings.TrimSpace(text.String())
+	}
+	return values
+}
@@ -0,0 +1,113 @@
+package docxwrappers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestXmlAddBookmark(t *testing.T) {
+	t.Run("Should add bookmarks with unique IDs and names", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+
+		startXml, err := docx.AddBookmark("Section1")
+		require.NoError(err)
+		assert.Equal(`</w:t><w:bookmarkStart w:id="1" w:name="Section1"/><w:t xml:space="preserve">`, startXml)
+		endXml, err := docx.EndBookmark()
+		require.NoError(err)
+		assert.Equal(`</w:t><w:bookmarkEnd w:id="1"/><w:t xml:space="preserve">`, endXml)
+
+		startXml, err = docx.AddBookmark("Section2")
+		require.NoError(err)
+		assert.Contains(startXml, `w:id="2"`)
+
+		_, err = docx.AddBookmark("section1")
+		assert.EqualError(err, `bookmark "section1" already exists`)
+
+		_, err = docx.EndBookmark()
+		require.NoError(err)
+		_, err = docx.EndBookmark()
+		assert.EqualError(err, "no bookmark to end")
+	})
+
+	t.Run("Should reject invalid names", func(t *testing.T) {
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(t, err)
+
+		for _, name := range []string{"", "1st", "Section 1", "Section\"1", "A1234567890123456789012345678901234567890"} {
+			_, err = docx.AddBookmark(name)
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("Should return an error if a bookmark isn't ended", func(t *testing.T) {
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(t, err)
+
+		_, err = docx.AddBookmark("Section1")
+		require.NoError(t, err)
+
+		err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body/></w:document>`)
+		assert.Error(t, err)
+	})
+}
+
+func TestXmlAddBookmarkLink(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	linkXml, err := docx.AddBookmarkLink("Section1", "Terms &amp; conditions")
+	require.NoError(err)
+	assert.Equal(`</w:t><w:hyperlink w:anchor="Section1" w:history="1"><w:r><w:t xml:space="preserve">Terms &amp; conditions</w:t></w:r></w:hyperlink><w:t xml:space="preserve">`, linkXml)
+
+	stylesXml, err := docx.GetPartXml("word/styles.xml")
+	require.NoError(err)
+	assert.Contains(stylesXml, `w:styleId="Hyperlink"`)
+
+	referenceXml, err := docx.AddBookmarkReference("Section1")
+	require.NoError(err)
+	assert.Contains(referenceXml, `<w:instrText xml:space="preserve"> REF Section1 \h </w:instrText>`)
+}
+
+func TestFixRepeatedBookmarks(t *testing.T) {
+	assert := assert.New(t)
+
+	nodes, err := xmltree.ParseString(`<w:body>` +
+		`<w:p><w:bookmarkStart w:id="1" w:name="Section1"/><w:r><w:t>One</w:t></w:r><w:bookmarkEnd w:id="1"/></w:p>` +
+		`<w:p><w:bookmarkStart w:id="1" w:name="Section2"/><w:r><w:t>Two</w:t></w:r><w:bookmarkEnd w:id="1"/></w:p>` +
+		`<w:p><w:bookmarkStart w:id="3" w:name="section1"/><w:r><w:t>Three</w:t></w:r><w:bookmarkEnd w:id="3"/></w:p>` +
+		`</w:body>`)
+	require.NoError(t, err)
+
+	docx := &XmlDocx{}
+	docx.fixRepeatedBookmarks(nodes)
+
+	assert.Equal(`<w:body>`+
+		`<w:p><w:bookmarkStart w:id="1" w:name="Section1"/><w:r><w:t>One</w:t></w:r><w:bookmarkEnd w:id="1"/></w:p>`+
+		`<w:p><w:bookmarkStart w:id="4" w:name="Section2"/><w:r><w:t>Two</w:t></w:r><w:bookmarkEnd w:id="4"/></w:p>`+
+		`<w:p><w:r><w:t>Three</w:t></w:r></w:p>`+
+		`</w:body>`, xmltree.Marshal(nodes...))
+	assert.Equal(4, docx.maxBookmarkId)
+}
+
+func TestXmlGetBookmarkTexts(t *testing.T) {
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(t, err)
+
+	err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:bookmarkStart w:id="1" w:name="Scope"/><w:r><w:t xml:space="preserve">Scope </w:t></w:r><w:del><w:r><w:delText>old</w:delText></w:r></w:del>` +
+		`<w:r><w:t>of work</w:t></w:r></w:p><w:p><w:r><w:t>Continued</w:t></w:r><w:bookmarkEnd w:id="1"/><w:r><w:t>After</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"scope": "Scope of work Continued"}, docx.getBookmarkTexts())
+}
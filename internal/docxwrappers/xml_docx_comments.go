@@ -0,0 +1,162 @@
+package docxwrappers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+const (
+	commentsContentType   = "application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml"
+	commentReferenceStyle = "CommentReference"
+	commentTextStyle      = "CommentText"
+)
+
+// Add a comment and return the XML for the start of the text it covers, which is ended by EndComment.
+// The author and text should already be XML escaped.
+func (d *XmlDocx) AddComment(author string, text string) (xmlString string, err error) {
+	comments, err := d.getOrAddXmlPart(relationships.COMMENTS_TYPE, "comments.xml", commentsContentType, fmt.Sprintf(
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+`<w:comments xmlns:w="%s" xmlns:r="%s"></w:comments>`,
+		WORDPROCESSING_NAMESPACE, RELATIONSHIPS_NAMESPACE,
+	))
+	if err != nil {
+		return "", err
+	}
+
+	id := -1
+	for _, comment := range comments.FindAll("w:comment") {
+		if value, ok := comment.AttrValue("w:id"); ok {
+			if n, err := strconv.Atoi(value); err == nil {
+				id = max(id, n)
+			}
+		}
+	}
+	id++
+
+	d.ensureStyle(commentReferenceStyle, "character", "annotation reference",
+		`<w:basedOn w:val="DefaultParagraphFont"/><w:uiPriority w:val="99"/><w:semiHidden/><w:unhideWhenUsed/><w:rPr><w:sz w:val="16"/><w:szCs w:val="16"/></w:rPr>`)
+	d.ensureStyle(commentTextStyle, "paragraph", "annotation text",
+		`<w:basedOn w:val="Normal"/><w:uiPriority w:val="99"/><w:unhideWhenUsed/><w:pPr><w:spacing w:line="240" w:lineRule="auto"/></w:pPr><w:rPr><w:sz w:val="20"/><w:szCs w:val="20"/></w:rPr>`)
+
+	nodes, err := xmltree.ParseString(fmt.Sprintf(
+		`<w:comment w:id="%d" w:author="%s" w:date="%s"><w:p><w:pPr><w:pStyle w:val="%s"/></w:pPr><w:r><w:rPr><w:rStyle w:val="%s"/></w:rPr><w:annotationRef/></w:r><w:r><w:t xml:space="preserve">%s</w:t></w:r></w:p></w:comment>`,
+		id, author, time.Now().UTC().Format("2006-01-02T15:04:05Z"), commentTextStyle, commentReferenceStyle, text,
+	))
+	if err != nil {
+		return "", fmt.Errorf("invalid comment: %w", err)
+	}
+	comment := xmltree.Root(nodes)
+	authorName, _ := comment.AttrValue("w:author")
+	comment.SetAttr("w:initials", getInitials(authorName))
+	comments.Children = append(comments.Children, comment)
+	d.openComments = append(d.openComments, id)
+
+	// The range marker is moved out of the run when the document XML is replaced
+	return fmt.Sprintf(`</w:t><w:commentRangeStart w:id="%d"/><w:t xml:space="preserve">`, id), nil
+}
+
+// End the comment most recently added by AddComment and return the XML for the end of its
+// range and its reference.
+func (d *XmlDocx) EndComment() (xmlString string, err error) {
+	if len(d.openComments) == 0 {
+		return "", errors.New("no comment to end")
+	}
+	id := d.openComments[len(d.openComments)-1]
+	d.openComments = d.openComments[:len(d.openComments)-1]
+
+	return fmt.Sprintf(`</w:t><w:commentRangeEnd w:id="%[1]d"/><w:commentReference w:id="%[1]d"/><w:t xml:space="preserve">`, id), nil
+}
+
+// Remove every comment from the document, along with the markers for the text they cover.
+func (d *XmlDocx) RemoveComments() error {
+	removeCommentMarkers(d.document)
+	for _, partName := range d.GetTemplatePartNames() {
+		nodes, err := d.getXmlPart(partName)
+		if err != nil {
+			return err
+		}
+		removeCommentMarkers(nodes)
+	}
+
+	// The parts are emptied rather than removed so their relationships stay valid
+	for _, relType := range []string{
+		relationships.COMMENTS_TYPE,
+		relationships.COMMENTS_EXTENDED_TYPE,
+		relationships.COMMENTS_IDS_TYPE,
+		relationships.COMMENTS_EXTENSIBLE_TYPE,
+	} {
+		partName, ok := d.getRelatedPartName(relType)
+		if !ok {
+			continue
+		}
+		nodes, err := d.getXmlPart(partName)
+		if err != nil {
+			return err
+		}
+		if root := xmltree.Root(nodes); root != nil {
+			root.Children = nil
+		}
+	}
+	d.openComments = nil
+
+	return nil
+}
+
+func isCommentMarker(node xmltree.Node) bool {
+	element, ok := node.(*xmltree.Element)
+	return ok && (element.Is("w:commentRangeStart") || element.Is("w:commentRangeEnd") || element.Is("w:commentReference"))
+}
+
+// Remove comment ranges and references, along with runs which only held a reference.
+func removeCommentMarkers(nodes []xmltree.Node) {
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		element, ok := node.(*xmltree.Element)
+		if !ok {
+			return false
+		}
+
+		children := make([]xmltree.Node, 0, len(element.Children))
+		for _, child := range element.Children {
+			if isCommentMarker(child) {
+				continue
+			}
+			if run, ok := child.(*xmltree.Element); ok && run.Is("w:r") && isCommentReferenceRun(run) {
+				continue
+			}
+			children = append(children, child)
+		}
+		element.Children = children
+
+		return true
+	})
+}
+
+func isCommentReferenceRun(run *xmltree.Element) bool {
+	hasReference := false
+	for _, child := range run.Elements() {
+		switch {
+		case child.Is("w:commentReference"):
+			hasReference = true
+		case !child.Is("w:rPr"):
+			return false
+		}
+	}
+	return hasReference
+}
+
+// Get the initials of a name, e.g. "Jane Smith" => "JS".
+func getInitials(name string) string {
+	var initials strings.Builder
+	for _, word := range strings.Fields(name) {
+		r, _ := utf8.DecodeRuneInString(word)
+		initials.WriteRune(unicode.ToUpper(r))
+	}
+	return initials.String()
+}
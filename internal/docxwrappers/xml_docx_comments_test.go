@@ -0,0 +1,112 @@
+package docxwrappers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/contenttypes"
+)
+
+func TestXmlAddComment(t *testing.T) {
+	t.Run("Should add a comments part to documents without one", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+
+		startXml, err := docx.AddComment("Compliance Team", "Check &amp; approve")
+		require.NoError(err)
+		assert.Equal(`</w:t><w:commentRangeStart w:id="0"/><w:t xml:space="preserve">`, startXml)
+
+		endXml, err := docx.EndComment()
+		require.NoError(err)
+		assert.Equal(`</w:t><w:commentRangeEnd w:id="0"/><w:commentReference w:id="0"/><w:t xml:space="preserve">`, endXml)
+
+		assert.Equal([]string{"word/comments.xml"}, docx.GetTemplatePartNames())
+		assert.Contains(docx.contentTypes.Overrides, contenttypes.Override{
+			PartName:    "/word/comments.xml",
+			ContentType: commentsContentType,
+		})
+
+		commentsXml, err := docx.GetPartXml("word/comments.xml")
+		require.NoError(err)
+		assert.Regexp(`<w:comment w:id="0" w:author="Compliance Team" w:date="\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ" w:initials="CT">`, commentsXml)
+		assert.Contains(commentsXml, `<w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:annotationRef/></w:r><w:r><w:t xml:space="preserve">Check &amp; approve</w:t></w:r>`)
+
+		stylesXml, err := docx.GetPartXml("word/styles.xml")
+		require.NoError(err)
+		assert.Contains(stylesXml, `w:styleId="CommentReference"`)
+		assert.Contains(stylesXml, `w:styleId="CommentText"`)
+	})
+
+	t.Run("Should end the most recent comment first", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+
+		_, err = docx.AddComment("Legal", "Outer")
+		require.NoError(err)
+		_, err = docx.AddComment("Legal", "Inner")
+		require.NoError(err)
+
+		endXml, err := docx.EndComment()
+		require.NoError(err)
+		assert.Contains(endXml, `<w:commentRangeEnd w:id="1"/>`)
+		endXml, err = docx.EndComment()
+		require.NoError(err)
+		assert.Contains(endXml, `<w:commentRangeEnd w:id="0"/>`)
+
+		_, err = docx.EndComment()
+		assert.Error(err)
+	})
+
+	t.Run("Should return an error if a comment isn't ended", func(t *testing.T) {
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(t, err)
+
+		_, err = docx.AddComment("Legal", "Check")
+		require.NoError(t, err)
+
+		err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body/></w:document>`)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetInitials(t *testing.T) {
+	assert.Equal(t, "JS", getInitials("Jane Smith"))
+	assert.Equal(t, "\u00c9D", getInitials("\u00e9mile durand"))
+	assert.Equal(t, "", getInitials(""))
+}
+
+func TestXmlRemoveComments(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	_, err = docx.AddComment("Legal", "Check")
+	require.NoError(err)
+	_, err = docx.EndComment()
+	require.NoError(err)
+	err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p>` +
+		`<w:commentRangeStart w:id="0"/><w:r><w:t>Text</w:t></w:r><w:commentRangeEnd w:id="0"/>` +
+		`<w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:commentReference w:id="0"/></w:r>` +
+		`</w:p></w:body></w:document>`)
+	require.NoError(err)
+
+	err = docx.RemoveComments()
+	require.NoError(err)
+
+	documentXml, err := docx.GetDocumentXml()
+	require.NoError(err)
+	assert.Equal(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Text</w:t></w:r></w:p></w:body></w:document>`, documentXml)
+
+	commentsXml, err := docx.GetPartXml("word/comments.xml")
+	require.NoError(err)
+	assert.NotContains(commentsXml, "<w:comment ")
+}
@@ -0,0 +1,203 @@
+package docxwrappers
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/contentcontrols"
+	"github.com/tomwatkins1994/go-docx-template/internal/contenttypes"
+	"github.com/tomwatkins1994/go-docx-template/internal/customxml"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+const (
+	CUSTOM_XML_NAMESPACE           = "http://schemas.openxmlformats.org/officeDocument/2006/customXml"
+	customXmlPropertiesContentType = "application/vnd.openxmlformats-officedocument.customXmlProperties+xml"
+)
+
+// Write the XML of a custom XML part and update the content controls bound to it. A part whose root
+// element has the same name and namespace is replaced, keeping its ID so existing bindings still work.
+func (d *XmlDocx) SetCustomXml(xmlString string) error {
+	nodes, err := xmltree.ParseString(xmlString)
+	if err != nil {
+		return err
+	}
+	root := xmltree.Root(nodes)
+	if root == nil {
+		return errors.New("custom XML has no root element")
+	}
+	namespace := getNamespace(root)
+
+	partName, itemId, err := d.findCustomXmlPart(root.Name.Local, namespace)
+	if err != nil {
+		return err
+	}
+	if partName == "" {
+		partName, itemId, err = d.addCustomXmlPart(namespace)
+		if err != nil {
+			return err
+		}
+	}
+	d.setXmlPart(partName, nodes)
+
+	updateDataBindings(d.document, itemId, nodes)
+	for _, templatePartName := range d.GetTemplatePartNames() {
+		templatePart, err := d.getXmlPart(templatePartName)
+		if err != nil {
+			return err
+		}
+		updateDataBindings(templatePart, itemId, nodes)
+	}
+
+	return nil
+}
+
+// Get the namespace of an element from the declarations on it.
+func getNamespace(element *xmltree.Element) string {
+	name := "xmlns"
+	if element.Name.Space != "" {
+		name = "xmlns:" + element.Name.Space
+	}
+	namespace, _ := element.AttrValue(name)
+	return namespace
+}
+
+// Find the custom XML part with a root element, returning its name and ID, or an empty name if there isn't one.
+func (d *XmlDocx) findCustomXmlPart(rootName string, namespace string) (partName string, itemId string, err error) {
+	for _, relationship := range d.documentRelationships.Relationships {
+		if relationship.Type != relationships.CUSTOM_XML_TYPE || relationship.TargetMode == "External" {
+			continue
+		}
+		partName := relationships.ResolveTarget(d.documentPartName, relationship.Target)
+		if _, ok := d.parts[partName]; !ok {
+			continue
+		}
+		nodes, err := d.getXmlPart(partName)
+		if err != nil {
+			return "", "", err
+		}
+		root := xmltree.Root(nodes)
+		if root == nil || root.Name.Local != rootName || getNamespace(root) != namespace {
+			continue
+		}
+
+		itemId, err := d.getCustomXmlItemId(partName)
+		if err != nil {
+			return "", "", err
+		}
+		if itemId == "" {
+			continue
+		}
+		return partName, itemId, nil
+	}
+
+	return "", "", nil
+}
+
+// Get the ID which data bindings use to refer to a custom XML part, from its properties part.
+func (d *XmlDocx) getCustomXmlItemId(partName string) (string, error) {
+	rels, err := d.getRelationships(partName)
+	if err != nil {
+		return "", err
+	}
+	propsRelationship := rels.FindByType(relationships.CUSTOM_XML_PROPS_TYPE)
+	if propsRelationship == nil {
+		return "", nil
+	}
+	propsPartName := relationships.ResolveTarget(partName, propsRelationship.Target)
+	if _, ok := d.parts[propsPartName]; !ok {
+		return "", nil
+	}
+
+	nodes, err := d.getXmlPart(propsPartName)
+	if err != nil {
+		return "", err
+	}
+	if root := xmltree.Root(nodes); root != nil {
+		for _, attr := range root.Attr {
+			if attr.Name.Local == "itemID" {
+				return attr.Value, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// Add an empty custom XML part along with its properties part, returning its name and new ID.
+func (d *XmlDocx) addCustomXmlPart(namespace string) (partName string, itemId string, err error) {
+	itemId, err = newGuid()
+	if err != nil {
+		return "", "", err
+	}
+
+	partName = d.newPartName("customXml", "item", ".xml")
+	propsPartName := d.newPartName("customXml", "itemProps", ".xml")
+
+	schemaRefs := xmltree.NewElement("ds:schemaRefs")
+	if namespace != "" {
+		schemaRefs.Children = append(schemaRefs.Children, xmltree.NewElement("ds:schemaRef", "ds:uri", namespace))
+	}
+	props := xmltree.NewElement("ds:datastoreItem", "ds:itemID", itemId, "xmlns:ds", CUSTOM_XML_NAMESPACE)
+	props.Children = append(props.Children, schemaRefs)
+	d.setXmlPart(propsPartName, []xmltree.Node{&xmltree.Raw{Data: `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`}, props})
+	d.contentTypes.AddOverride("/"+propsPartName, customXmlPropertiesContentType)
+	d.contentTypes.AddContentType(&contenttypes.XML_CONTENT_TYPE)
+
+	// Custom XML parts are linked to their properties, rather than the document holding both
+	itemRelationships := relationships.New()
+	itemRelationships.Add(relationships.CUSTOM_XML_PROPS_TYPE, path.Base(propsPartName))
+	relsXml, err := itemRelationships.MarshalXml()
+	if err != nil {
+		return "", "", err
+	}
+	d.setPart(relationships.PartName(partName), []byte(relsXml))
+
+	target := partName
+	if dir := path.Dir(d.documentPartName); dir != "." {
+		target = strings.Repeat("../", strings.Count(dir, "/")+1) + partName
+	}
+	d.documentRelationships.Add(relationships.CUSTOM_XML_TYPE, target)
+
+	return partName, itemId, nil
+}
+
+// Create an ID in the form Word uses for custom XML parts, e.g. {3F2504E0-4F89-41D3-9A0C-0305E82C3301}.
+func newGuid() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("{%X-%X-%X-%X-%X}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// Show the values from custom XML in the content controls bound to it, so the document shows the data
+// before Word refreshes the bindings itself.
+func updateDataBindings(nodes []xmltree.Node, itemId string, customXmlNodes []xmltree.Node) {
+	for _, sdt := range xmltree.FindDescendants(nodes, "w:sdt") {
+		properties := sdt.Find("w:sdtPr")
+		if properties == nil {
+			continue
+		}
+		binding := properties.Find("w:dataBinding")
+		if binding == nil {
+			continue
+		}
+		if storeItemId, _ := binding.AttrValue("w:storeItemID"); !strings.EqualFold(storeItemId, itemId) {
+			continue
+		}
+
+		xpath, _ := binding.AttrValue("w:xpath")
+		prefixMappings, _ := binding.AttrValue("w:prefixMappings")
+		if value, ok := customxml.Evaluate(customXmlNodes, xpath, customxml.ParsePrefixMappings(prefixMappings)); ok {
+			contentcontrols.SetValue(sdt, value)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package docxwrappers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/contenttypes"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+)
+
+func TestXmlSetCustomXml(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	err = docx.SetCustomXml(`<invoice xmlns="urn:example:invoice"><client>TW Software</client></invoice>`)
+	require.NoError(err)
+
+	partName, itemId, err := docx.findCustomXmlPart("invoice", "urn:example:invoice")
+	require.NoError(err)
+	// The template already has a custom XML part for its bibliography
+	assert.Equal("customXml/item2.xml", partName)
+	assert.Regexp(`^\{[0-9A-F]{8}-[0-9A-F]{4}-4[0-9A-F]{3}-[89AB][0-9A-F]{3}-[0-9A-F]{12}\}$`, itemId)
+	assert.Contains(docx.documentRelationships.Relationships, relationships.Relationship{
+		Id:     "rId7",
+		Type:   relationships.CUSTOM_XML_TYPE,
+		Target: "../customXml/item2.xml",
+	})
+	assert.Contains(docx.contentTypes.Overrides, contenttypes.Override{
+		PartName:    "/customXml/itemProps2.xml",
+		ContentType: customXmlPropertiesContentType,
+	})
+	propsXml, err := docx.GetPartXml("customXml/itemProps2.xml")
+	require.NoError(err)
+	assert.Contains(propsXml, `<ds:schemaRefs><ds:schemaRef ds:uri="urn:example:invoice"/></ds:schemaRefs>`)
+
+	// Bind a content control to the part and update it
+	err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p>` +
+		`<w:sdt><w:sdtPr><w:dataBinding w:prefixMappings="xmlns:ns0='urn:example:invoice'" w:xpath="/ns0:invoice[1]/ns0:client[1]" w:storeItemID="` + itemId + `"/><w:showingPlcHdr/><w:text/></w:sdtPr>` +
+		`<w:sdtContent><w:r><w:t>Client</w:t></w:r></w:sdtContent></w:sdt>` +
+		`<w:sdt><w:sdtPr><w:dataBinding w:xpath="/invoice[1]/client[1]" w:storeItemID="{00000000-0000-0000-0000-000000000000}"/><w:text/></w:sdtPr>` +
+		`<w:sdtContent><w:r><w:t>Other part</w:t></w:r></w:sdtContent></w:sdt>` +
+		`</w:p></w:body></w:document>`)
+	require.NoError(err)
+
+	err = docx.SetCustomXml(`<invoice xmlns="urn:example:invoice"><client>Jane's Shop</client></invoice>`)
+	require.NoError(err)
+
+	documentXml, err := docx.GetDocumentXml()
+	require.NoError(err)
+	assert.Contains(documentXml, `<w:text/></w:sdtPr><w:sdtContent><w:r><w:t xml:space="preserve">Jane's Shop</w:t></w:r></w:sdtContent>`)
+	assert.Contains(documentXml, `<w:t>Other part</w:t>`)
+
+	// The part is replaced rather than another being added, and it is found again once saved
+	buf := &bytes.Buffer{}
+	require.NoError(docx.Save(buf))
+	reopened, err := NewXmlDocx(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(err)
+
+	_, ok := reopened.parts["customXml/item3.xml"]
+	assert.False(ok)
+	partName, reopenedItemId, err := reopened.findCustomXmlPart("invoice", "urn:example:invoice")
+	require.NoError(err)
+	assert.Equal("customXml/item2.xml", partName)
+	assert.Equal(itemId, reopenedItemId)
+	itemXml, err := reopened.GetPartXml("customXml/item2.xml")
+	require.NoError(err)
+	assert.Equal(`<invoice xmlns="urn:example:invoice"><client>Jane's Shop</client></invoice>`, itemXml)
+}
@@ -0,0 +1,197 @@
+package docxwrappers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/fields"
+	"github.com/tomwatkins1994/go-docx-template/internal/properties"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+const settingsContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"
+
+const settingsXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+	`<w:settings xmlns:w="` + WORDPROCESSING_NAMESPACE + `"/>`
+
+// The elements of the document settings in the order the schema requires them.
+var settingsOrder = []string{
+	"w:writeProtection", "w:view", "w:zoom", "w:removePersonalInformation", "w:removeDateAndTime",
+	"w:doNotDisplayPageBoundaries", "w:displayBackgroundShape", "w:printPostScriptOverText",
+	"w:printFractionalCharacterWidth", "w:printFormsData", "w:embedTrueTypeFonts", "w:embedSystemFonts",
+	"w:saveSubsetFonts", "w:saveFormsData", "w:mirrorMargins", "w:alignBordersAndEdges",
+	"w:bordersDoNotSurroundHeader", "w:bordersDoNotSurroundFooter", "w:gutterAtTop", "w:hideSpellingErrors",
+	"w:hideGrammaticalErrors", "w:activeWritingStyle", "w:proofState", "w:formsDesign", "w:attachedTemplate",
+	"w:linkStyles", "w:stylePaneFormatFilter", "w:stylePaneSortMethod", "w:documentType", "w:mailMerge",
+	"w:revisionView", "w:trackRevisions", "w:doNotTrackMoves", "w:doNotTrackFormatting", "w:documentProtection",
+	"w:autoFormatOverride", "w:styleLockTheme", "w:styleLockQFSet", "w:defaultTabStop", "w:autoHyphenation",
+	"w:consecutiveHyphenLimit", "w:hyphenationZone", "w:doNotHyphenateCaps", "w:showEnvelope", "w:summaryLength",
+	"w:clickAndTypeStyle", "w:defaultTableStyle", "w:evenAndOddHeaders", "w:bookFoldRevPrinting",
+	"w:bookFoldPrinting", "w:bookFoldPrintingSheets", "w:drawingGridHorizontalSpacing",
+	"w:drawingGridVerticalSpacing", "w:displayHorizontalDrawingGridEvery", "w:displayVerticalDrawingGridEvery",
+	"w:doNotUseMarginsForDrawingGridOrigin", "w:drawingGridHorizontalOrigin", "w:drawingGridVerticalOrigin",
+	"w:doNotShadeFormData", "w:noPunctuationKerning", "w:characterSpacingControl", "w:printTwoOnOne",
+	"w:strictFirstAndLastChars", "w:noLineBreaksAfter", "w:noLineBreaksBefore", "w:savePreviewPicture",
+	"w:doNotValidateAgainstSchema", "w:saveInvalidXml", "w:ignoreMixedContent", "w:alwaysShowPlaceholderText",
+	"w:doNotDemarcateInvalidXml", "w:saveXmlDataOnly", "w:useXSLTWhenSaving", "w:saveThroughXslt",
+	"w:showXMLTags", "w:alwaysMergeEmptyNamespace", "w:updateFields", "w:hdrShapeDefaults", "w:footnotePr",
+	"w:endnotePr", "w:compat", "w:docVars", "w:rsids", "m:mathPr", "w:attachedSchema", "w:themeFontLang",
+	"w:clrSchemeMapping", "w:doNotIncludeSubdocsInStats", "w:doNotAutoCompressPictures", "w:forceUpgrade",
+	"w:captions", "w:readModeInkLockDown", "w:smartTagType", "sl:schemaLibrary", "w:shapeDefaults",
+	"w:doNotEmbedSmartTags", "w:decimalSymbol", "w:listSeparator",
+}
+
+// Get the root element of the document settings, adding the part if the document doesn't have it.
+func (d *XmlDocx) getSettings() (*xmltree.Element, error) {
+	return d.getOrAddXmlPart(relationships.SETTINGS_TYPE, "settings.xml", settingsContentType, settingsXml)
+}
+
+// Get a setting, adding it in the position the schema requires if it isn't set.
+func getOrAddSetting(settings *xmltree.Element, name string) *xmltree.Element {
+	if element := settings.Find(name); element != nil {
+		return element
+	}
+
+	element := xmltree.NewElement(name)
+	position := slices.Index(settingsOrder, name)
+	index := slices.IndexFunc(settings.Children, func(node xmltree.Node) bool {
+		child, ok := node.(*xmltree.Element)
+		return ok && slices.Index(settingsOrder, child.Tag()) > position
+	})
+	if index == -1 {
+		index = len(settings.Children)
+	}
+	settings.Children = slices.Insert(settings.Children, index, xmltree.Node(element))
+
+	return element
+}
+
+// Set document variables, which DOCVARIABLE fields show, replacing any with the same names. The results
+// of the fields are updated so the values are shown without the fields being updated in Word.
+func (d *XmlDocx) SetDocumentVariables(variables map[string]string) error {
+	settings, err := d.getSettings()
+	if err != nil {
+		return err
+	}
+	docVars := getOrAddSetting(settings, "w:docVars")
+
+	// Variables are added in order of their names so documents are always the same
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		docVar := findByName(docVars.FindAll("w:docVar"), name)
+		if docVar == nil {
+			docVar = xmltree.NewElement("w:docVar", "w:name", name)
+			docVars.Children = append(docVars.Children, docVar)
+		}
+		docVar.SetAttr("w:val", variables[name])
+	}
+
+	return d.UpdateFieldResults()
+}
+
+// Find an element by its w:name attribute. Names are matched ignoring case as they are in Word.
+func findByName(elements []*xmltree.Element, name string) *xmltree.Element {
+	for _, element := range elements {
+		if elementName, _ := element.AttrValue("w:name"); strings.EqualFold(elementName, name) {
+			return element
+		}
+	}
+	return nil
+}
+
+// Update the results of fields in the document, headers and footers so they are correct before Word
+// updates them. DOCVARIABLE and DOCPROPERTY fields show the values of the document variables and
+// properties, REF fields show the text of bookmarks, SEQ fields are numbered and tables of contents
+// list the headings of the document. Fields showing values which aren't set are left as they are.
+func (d *XmlDocx) UpdateFieldResults() error {
+	variables := map[string]string{}
+	if partName, ok := d.getRelatedPartName(relationships.SETTINGS_TYPE); ok {
+		nodes, err := d.getXmlPart(partName)
+		if err != nil {
+			return err
+		}
+		for _, docVar := range xmltree.FindDescendants(nodes, "w:docVar") {
+			name, _ := docVar.AttrValue("w:name")
+			value, _ := docVar.AttrValue("w:val")
+			variables[strings.ToLower(name)] = value
+		}
+	}
+
+	propertyValues := map[string]string{}
+	for _, partName := range d.GetPropertiesPartNames() {
+		nodes, err := d.getXmlPart(partName)
+		if err != nil {
+			return err
+		}
+		if root := xmltree.Root(nodes); root != nil {
+			for name, value := range properties.GetFieldValues(root) {
+				propertyValues[strings.ToLower(name)] = value
+			}
+		}
+	}
+
+	bookmarkTexts := d.getBookmarkTexts()
+
+	updateResults := func(nodes []xmltree.Node, isDocument bool) {
+		// Sequences are numbered through each part separately. Sequences with fields which can't be numbered
+		// are left as they are.
+		sequences := map[string]int{}
+		unsupportedSequences := map[string]bool{}
+		for _, field := range fields.Find(nodes) {
+			if arguments := field.Arguments(); len(arguments) >= 2 && arguments[0] == "SEQ" {
+				if sequence, ok := parseSequenceField(arguments); !ok {
+					unsupportedSequences[sequence.name] = true
+				}
+			}
+		}
+		for _, field := range fields.Find(nodes) {
+			arguments := field.Arguments()
+			if len(arguments) == 0 {
+				continue
+			}
+			// Headings are only listed from the main document
+			if arguments[0] == "TOC" && isDocument {
+				d.updateTableOfContents(field, arguments)
+				continue
+			}
+			if len(arguments) < 2 {
+				continue
+			}
+
+			var value string
+			var ok bool
+			switch arguments[0] {
+			case "DOCVARIABLE":
+				value, ok = variables[strings.ToLower(arguments[1])]
+			case "DOCPROPERTY":
+				value, ok = propertyValues[strings.ToLower(arguments[1])]
+			case "REF":
+				value, ok = bookmarkTexts[strings.ToLower(arguments[1])]
+			case "SEQ":
+				if sequence, _ := parseSequenceField(arguments); !unsupportedSequences[sequence.name] {
+					value, ok = nextSequenceNumber(sequences, sequence), true
+				}
+			}
+			if ok {
+				field.SetResult(value)
+			}
+		}
+	}
+
+	updateResults(d.document, true)
+	for _, partName := range append(d.getRelatedPartNames(relationships.HEADER_TYPE), d.getRelatedPartNames(relationships.FOOTER_TYPE)...) {
+		nodes, err := d.getXmlPart(partName)
+		if err != nil {
+			return err
+		}
+		updateResults(nodes, false)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package docxwrappers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXmlSetDocumentVariables(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:fldSimple w:instr=" DOCVARIABLE ClientName \* MERGEFORMAT "><w:r><w:t>Client</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> DOCPROPERTY Author </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>Author</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+		`<w:p><w:fldSimple w:instr="DOCVARIABLE Missing"><w:r><w:t>Unchanged</w:t></w:r></w:fldSimple></w:p>` +
+		`</w:body></w:document>`)
+	require.NoError(err)
+
+	err = docx.SetDocumentVariables(map[string]string{"ClientName": "TW Software", "Reference": "B-00001"})
+	require.NoError(err)
+	err = docx.SetDocumentVariables(map[string]string{"clientname": "Jane's Shop"})
+	require.NoError(err)
+
+	// Variables go before the rsids, as the schema requires
+	settingsXml, err := docx.GetPartXml("word/settings.xml")
+	require.NoError(err)
+	docVars := `<w:docVars><w:docVar w:name="ClientName" w:val="Jane's Shop"/><w:docVar w:name="Reference" w:val="B-00001"/></w:docVars>`
+	assert.Contains(settingsXml, docVars+"<w:rsids>")
+	assert.Equal(1, strings.Count(settingsXml, "<w:docVars>"))
+
+	documentXml, err := docx.GetDocumentXml()
+	require.NoError(err)
+	assert.Contains(documentXml, `<w:r><w:t>Jane's Shop</w:t></w:r></w:fldSimple>`)
+	assert.Contains(documentXml, `<w:r><w:t>Thomas Watkins</w:t></w:r>`)
+	assert.Contains(documentXml, `<w:t>Unchanged</w:t>`)
+}
@@ -0,0 +1,203 @@
+package docxwrappers
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/numbering"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmlutils"
+)
+
+const (
+	numberingContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"
+	listParagraphStyle   = "ListParagraph"
+
+	// The names of the list definitions added to the numbering part, so they are reused by later lists
+	bulletedListName = "Bulleted List"
+	numberedListName = "Numbered List"
+
+	// Word allows lists to be nested up to 9 levels deep
+	maxListLevels = 9
+)
+
+// The bullets and number formats used by each level of a list, repeating every three levels as in Word.
+var (
+	listBullets       = []string{"\u2022", "\u25e6", "\u25aa"}
+	listNumberFormats = []string{"decimal", "lowerLetter", "lowerRoman"}
+)
+
+// Add a bulleted or numbered list and return the XML for its paragraphs, one for each item. Items are
+// usually text, which should already be XML escaped, and a slice after an item holds the items nested
+// under it. Numbered lists start from 1 each time.
+func (d *XmlDocx) AddList(items any, numbered bool) (xmlString string, err error) {
+	value := reflect.ValueOf(items)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return "", fmt.Errorf("list items must be a slice rather than %T", items)
+	}
+	if value.Len() == 0 {
+		return "", nil
+	}
+
+	numId, err := d.addListNumbering(numbered)
+	if err != nil {
+		return "", err
+	}
+	d.ensureStyle(listParagraphStyle, "paragraph", "List Paragraph",
+		`<w:basedOn w:val="Normal"/><w:uiPriority w:val="34"/><w:qFormat/><w:pPr><w:ind w:left="720"/><w:contextualSpacing/></w:pPr>`)
+
+	// The paragraph the tag is in is split around the list when the document XML is replaced
+	var paragraphs strings.Builder
+	paragraphs.WriteString("</w:t>")
+	if err := writeListItems(&paragraphs, value, 0, numId); err != nil {
+		return "", err
+	}
+	paragraphs.WriteString(`<w:t xml:space="preserve">`)
+
+	return paragraphs.String(), nil
+}
+
+func writeListItems(paragraphs *strings.Builder, items reflect.Value, level int, numId int) error {
+	if level >= maxListLevels {
+		return fmt.Errorf("lists can't be nested more than %d levels deep", maxListLevels)
+	}
+
+	for i := range items.Len() {
+		item := items.Index(i)
+		for item.Kind() == reflect.Interface || item.Kind() == reflect.Pointer {
+			item = item.Elem()
+		}
+		if !item.IsValid() {
+			continue
+		}
+
+		var text string
+		switch {
+		case item.Kind() == reflect.String:
+			text = item.String()
+		case item.Kind() == reflect.Slice || item.Kind() == reflect.Array:
+			if err := writeListItems(paragraphs, item, level+1, numId); err != nil {
+				return err
+			}
+			continue
+		default:
+			escapedText, err := xmlutils.EscapeXmlString(fmt.Sprint(item.Interface()))
+			if err != nil {
+				return err
+			}
+			text = escapedText
+		}
+
+		fmt.Fprintf(paragraphs,
+			`<w:p><w:pPr><w:pStyle w:val="%s"/><w:numPr><w:ilvl w:val="%d"/><w:numId w:val="%d"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">%s</w:t></w:r></w:p>`,
+			listParagraphStyle, level, numId, text,
+		)
+	}
+
+	return nil
+}
+
+// Add a numbering instance for a new list and return its ID. The list definition it uses is added to the
+// numbering part the first time a list of its kind is added.
+func (d *XmlDocx) addListNumbering(numbered bool) (int, error) {
+	numbering, err := d.getOrAddXmlPart(relationships.NUMBERING_TYPE, "numbering.xml", numberingContentType, fmt.Sprintf(
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+`<w:numbering xmlns:w="%s"></w:numbering>`,
+		WORDPROCESSING_NAMESPACE,
+	))
+	if err != nil {
+		return 0, err
+	}
+
+	name := bulletedListName
+	if numbered {
+		name = numberedListName
+	}
+	abstractNumId, maxAbstractNumId := -1, -1
+	for _, abstractNum := range numbering.FindAll("w:abstractNum") {
+		value, _ := abstractNum.AttrValue("w:abstractNumId")
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		maxAbstractNumId = max(maxAbstractNumId, id)
+		if abstractNumName := abstractNum.Find("w:name"); abstractNumName != nil {
+			if value, _ := abstractNumName.AttrValue("w:val"); value == name {
+				abstractNumId = id
+			}
+		}
+	}
+	if abstractNumId == -1 {
+		abstractNumId = maxAbstractNumId + 1
+		abstractNum, err := newListDefinition(abstractNumId, name, numbered)
+		if err != nil {
+			return 0, err
+		}
+		// List definitions come before the numbering instances which use them
+		numbering.InsertBefore(abstractNum, "w:num", "w:numIdMacAtCleanup")
+	}
+
+	numId := 0
+	for _, num := range numbering.FindAll("w:num") {
+		value, _ := num.AttrValue("w:numId")
+		if id, err := strconv.Atoi(value); err == nil {
+			numId = max(numId, id)
+		}
+	}
+	numId++
+
+	num := xmltree.NewElement("w:num", "w:numId", strconv.Itoa(numId))
+	num.Children = append(num.Children, xmltree.NewElement("w:abstractNumId", "w:val", strconv.Itoa(abstractNumId)))
+	if numbered {
+		// Lists using the same definition carry on numbering from each other unless told to start again
+		override := xmltree.NewElement("w:lvlOverride", "w:ilvl", "0")
+		override.Children = append(override.Children, xmltree.NewElement("w:startOverride", "w:val", "1"))
+		num.Children = append(num.Children, override)
+	}
+	numbering.InsertBefore(num, "w:numIdMacAtCleanup")
+
+	return numId, nil
+}
+
+func newListDefinition(abstractNumId int, name string, numbered bool) (*xmltree.Element, error) {
+	var levels strings.Builder
+	for level := range maxListLevels {
+		numberFormat, text := "bullet", listBullets[level%len(listBullets)]
+		if numbered {
+			numberFormat, text = listNumberFormats[level%len(listNumberFormats)], "%"+strconv.Itoa(level+1)+"."
+		}
+		fmt.Fprintf(&levels,
+			`<w:lvl w:ilvl="%d"><w:start w:val="1"/><w:numFmt w:val="%s"/><w:lvlText w:val="%s"/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="%d" w:hanging="360"/></w:pPr></w:lvl>`,
+			level, numberFormat, text, (level+1)*720,
+		)
+	}
+
+	nodes, err := xmltree.ParseString(fmt.Sprintf(
+		`<w:abstractNum w:abstractNumId="%d"><w:multiLevelType w:val="hybridMultilevel"/><w:name w:val="%s"/>%s</w:abstractNum>`,
+		abstractNumId, name, levels.String(),
+	))
+	if err != nil {
+		return nil, err
+	}
+	return xmltree.Root(nodes), nil
+}
+
+// Get the IDs of the paragraph styles which number their paragraphs, e.g. ListNumber, in order.
+func (d *XmlDocx) GetNumberedStyles() []string {
+	return slices.Sorted(maps.Keys(d.getStyles().NumberedStyles()))
+}
+
+// Number the lists in each iteration of the loops marked in rendered XML from the start, and remove the markers.
+func (d *XmlDocx) restartRepeatedLists(nodes []xmltree.Node) {
+	var root *xmltree.Element
+	if partName, ok := d.getRelatedPartName(relationships.NUMBERING_TYPE); ok {
+		if numberingNodes, err := d.getXmlPart(partName); err == nil {
+			root = xmltree.Root(numberingNodes)
+		}
+	}
+	numbering.RestartRepeatedLists(nodes, root, d.getStyles().NumberedStyles())
+}
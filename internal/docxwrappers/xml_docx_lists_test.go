@@ -0,0 +1,90 @@
+package docxwrappers
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/contenttypes"
+)
+
+func TestXmlAddList(t *testing.T) {
+	listParagraph := func(level int, numId string, text string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="` + strconv.Itoa(level) + `"/><w:numId w:val="` + numId + `"/></w:numPr></w:pPr>` +
+			`<w:r><w:t xml:space="preserve">` + text + `</w:t></w:r></w:p>`
+	}
+
+	t.Run("Should add a numbering part and nest items", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+
+		listXml, err := docx.AddList([]any{"Measure", []string{"Twice", "Carefully"}, "Cut &amp; sand", 3}, false)
+		require.NoError(err)
+		assert.Equal(`</w:t>`+
+			listParagraph(0, "1", "Measure")+
+			listParagraph(1, "1", "Twice")+
+			listParagraph(1, "1", "Carefully")+
+			listParagraph(0, "1", "Cut &amp; sand")+
+			listParagraph(0, "1", "3")+
+			`<w:t xml:space="preserve">`, listXml)
+
+		assert.Contains(docx.contentTypes.Overrides, contenttypes.Override{
+			PartName:    "/word/numbering.xml",
+			ContentType: numberingContentType,
+		})
+		numberingXml, err := docx.GetPartXml("word/numbering.xml")
+		require.NoError(err)
+		assert.Contains(numberingXml, `<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="hybridMultilevel"/><w:name w:val="Bulleted List"/>`+
+			`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="`+"\u2022"+`"/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="720" w:hanging="360"/></w:pPr></w:lvl>`)
+		assert.Contains(numberingXml, `<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>`)
+
+		stylesXml, err := docx.GetPartXml("word/styles.xml")
+		require.NoError(err)
+		assert.Contains(stylesXml, `w:styleId="ListParagraph"`)
+	})
+
+	t.Run("Should reuse list definitions and restart numbered lists", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+
+		for range 2 {
+			_, err = docx.AddList([]string{"One", "Two"}, true)
+			require.NoError(err)
+		}
+		listXml, err := docx.AddList([]string{"Bullet"}, false)
+		require.NoError(err)
+		assert.Contains(listXml, `<w:numId w:val="3"/>`)
+
+		numberingXml, err := docx.GetPartXml("word/numbering.xml")
+		require.NoError(err)
+		assert.Equal(1, strings.Count(numberingXml, `<w:name w:val="Numbered List"/>`))
+		assert.Contains(numberingXml, `<w:lvl w:ilvl="1"><w:start w:val="1"/><w:numFmt w:val="lowerLetter"/><w:lvlText w:val="%2."/>`)
+		assert.Contains(numberingXml, `</w:abstractNum><w:abstractNum w:abstractNumId="1">`)
+		assert.Contains(numberingXml, `</w:abstractNum><w:num w:numId="1"><w:abstractNumId w:val="0"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride></w:num>`+
+			`<w:num w:numId="2"><w:abstractNumId w:val="0"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride></w:num>`+
+			`<w:num w:numId="3"><w:abstractNumId w:val="1"/></w:num>`)
+	})
+
+	t.Run("Should return an error for items which aren't a list", func(t *testing.T) {
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(t, err)
+
+		_, err = docx.AddList("One", false)
+		assert.EqualError(t, err, "list items must be a slice rather than string")
+
+		items := []any{"Deepest"}
+		for range 9 {
+			items = []any{items}
+		}
+		_, err = docx.AddList(items, false)
+		assert.EqualError(t, err, "lists can't be nested more than 9 levels deep")
+	})
+}
@@ -0,0 +1,156 @@
+package docxwrappers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// Footnotes and endnotes have the same structure so are handled together.
+type noteType struct {
+	name           string
+	relType        string
+	contentType    string
+	referenceStyle string
+	textStyle      string
+}
+
+var footnoteType = noteType{
+	name:           "footnote",
+	relType:        relationships.FOOTNOTES_TYPE,
+	contentType:    "application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml",
+	referenceStyle: "FootnoteReference",
+	textStyle:      "FootnoteText",
+}
+
+var endnoteType = noteType{
+	name:           "endnote",
+	relType:        relationships.ENDNOTES_TYPE,
+	contentType:    "application/vnd.openxmlformats-officedocument.wordprocessingml.endnotes+xml",
+	referenceStyle: "EndnoteReference",
+	textStyle:      "EndnoteText",
+}
+
+// The parts other than the main document which can contain tags.
+func (d *XmlDocx) GetTemplatePartNames() []string {
+	partNames := []string{}
+	for _, relType := range []string{relationships.FOOTNOTES_TYPE, relationships.ENDNOTES_TYPE, relationships.COMMENTS_TYPE} {
+		if partName, ok := d.getRelatedPartName(relType); ok {
+			partNames = append(partNames, partName)
+		}
+	}
+	return partNames
+}
+
+func (d *XmlDocx) GetPartXml(partName string) (string, error) {
+	nodes, err := d.getXmlPart(partName)
+	if err != nil {
+		return "", err
+	}
+
+	return xmltree.Marshal(nodes...), nil
+}
+
+func (d *XmlDocx) ReplacePartXml(partName string, xmlString string) error {
+	nodes, err := xmltree.ParseString(xmlString)
+	if err != nil {
+		return err
+	}
+	d.restartRepeatedLists(nodes)
+	splitStyledParagraphs(nodes)
+	d.setXmlPart(partName, nodes)
+
+	return nil
+}
+
+// Add a footnote and return the XML for its reference. The text should already be XML escaped.
+func (d *XmlDocx) AddFootnote(text string) (xmlString string, err error) {
+	return d.addNote(footnoteType, text)
+}
+
+// Add an endnote and return the XML for its reference. The text should already be XML escaped.
+func (d *XmlDocx) AddEndnote(text string) (xmlString string, err error) {
+	return d.addNote(endnoteType, text)
+}
+
+func (d *XmlDocx) addNote(t noteType, text string) (string, error) {
+	notes, err := d.getNotes(t)
+	if err != nil {
+		return "", err
+	}
+
+	// Separators use IDs of 0 and below so new notes start from 1
+	id := 0
+	for _, note := range notes.FindAll("w:" + t.name) {
+		if value, ok := note.AttrValue("w:id"); ok {
+			if n, err := strconv.Atoi(value); err == nil {
+				id = max(id, n)
+			}
+		}
+	}
+	id++
+
+	d.ensureStyle(t.referenceStyle, "character", t.name+" reference",
+		`<w:basedOn w:val="DefaultParagraphFont"/><w:uiPriority w:val="99"/><w:semiHidden/><w:unhideWhenUsed/><w:rPr><w:vertAlign w:val="superscript"/></w:rPr>`)
+	d.ensureStyle(t.textStyle, "paragraph", t.name+" text",
+		`<w:basedOn w:val="Normal"/><w:uiPriority w:val="99"/><w:semiHidden/><w:unhideWhenUsed/><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:rPr><w:sz w:val="20"/><w:szCs w:val="20"/></w:rPr>`)
+
+	noteXml := fmt.Sprintf(
+		`<w:%[1]s w:id="%[2]d"><w:p><w:pPr><w:pStyle w:val="%[3]s"/></w:pPr><w:r><w:rPr><w:rStyle w:val="%[4]s"/></w:rPr><w:%[1]sRef/></w:r><w:r><w:t xml:space="preserve"> %[5]s</w:t></w:r></w:p></w:%[1]s>`,
+		t.name, id, t.textStyle, t.referenceStyle, text,
+	)
+	note, err := xmltree.ParseString(noteXml)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s text: %w", t.name, err)
+	}
+	notes.Children = append(notes.Children, note...)
+
+	// Closing the text lets the reference be used in the middle of a sentence. The reference is
+	// moved into its own run when the document XML is replaced.
+	return fmt.Sprintf(`</w:t><w:%sReference w:id="%d"/><w:t xml:space="preserve">`, t.name, id), nil
+}
+
+// Get the root element of the footnotes or endnotes part, adding the part if the document doesn't have one.
+func (d *XmlDocx) getNotes(t noteType) (*xmltree.Element, error) {
+	return d.getOrAddXmlPart(t.relType, t.name+"s.xml", t.contentType, fmt.Sprintf(
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+
+			`<w:%[1]ss xmlns:w="%[2]s" xmlns:r="%[3]s">`+
+			`<w:%[1]s w:type="separator" w:id="-1"><w:p><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:r><w:separator/></w:r></w:p></w:%[1]s>`+
+			`<w:%[1]s w:type="continuationSeparator" w:id="0"><w:p><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:r><w:continuationSeparator/></w:r></w:p></w:%[1]s>`+
+			`</w:%[1]ss>`,
+		t.name, WORDPROCESSING_NAMESPACE, RELATIONSHIPS_NAMESPACE,
+	))
+}
+
+// Add a style to the document if it isn't already defined. Documents without styles are left as they are.
+func (d *XmlDocx) ensureStyle(styleId string, styleType string, name string, definition string) {
+	partName, ok := d.getRelatedPartName(relationships.STYLES_TYPE)
+	if !ok {
+		return
+	}
+	nodes, err := d.getXmlPart(partName)
+	if err != nil {
+		return
+	}
+	styles := xmltree.Root(nodes)
+	if styles == nil {
+		return
+	}
+
+	for _, style := range styles.FindAll("w:style") {
+		if id, _ := style.AttrValue("w:styleId"); id == styleId {
+			return
+		}
+	}
+
+	style, err := xmltree.ParseString(fmt.Sprintf(
+		`<w:style w:type="%s" w:styleId="%s"><w:name w:val="%s"/>%s</w:style>`,
+		styleType, styleId, name, definition,
+	))
+	if err != nil {
+		return
+	}
+	styles.Children = append(styles.Children, style...)
+}
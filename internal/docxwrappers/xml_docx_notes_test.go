@@ -0,0 +1,69 @@
+package docxwrappers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/contenttypes"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+)
+
+func TestXmlAddFootnote(t *testing.T) {
+	t.Run("Should add a footnotes part to documents without one", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+		assert.Empty(docx.GetTemplatePartNames())
+
+		referenceXml, err := docx.AddFootnote("See the contract")
+		require.NoError(err)
+		assert.Equal(`</w:t><w:footnoteReference w:id="1"/><w:t xml:space="preserve">`, referenceXml)
+
+		assert.Equal([]string{"word/footnotes.xml"}, docx.GetTemplatePartNames())
+		assert.Contains(docx.contentTypes.Overrides, contenttypes.Override{
+			PartName:    "/word/footnotes.xml",
+			ContentType: footnoteType.contentType,
+		})
+
+		footnotesXml, err := docx.GetPartXml("word/footnotes.xml")
+		require.NoError(err)
+		assert.Contains(footnotesXml, `<w:footnote w:type="separator" w:id="-1">`)
+		assert.Contains(footnotesXml, `<w:footnote w:id="1"><w:p><w:pPr><w:pStyle w:val="FootnoteText"/></w:pPr><w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteRef/></w:r><w:r><w:t xml:space="preserve"> See the contract</w:t></w:r></w:p></w:footnote>`)
+
+		stylesXml, err := docx.GetPartXml("word/styles.xml")
+		require.NoError(err)
+		assert.Contains(stylesXml, `w:styleId="FootnoteReference"`)
+		assert.Contains(stylesXml, `w:styleId="FootnoteText"`)
+	})
+
+	t.Run("Should give each note the next ID", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+
+		_, err = docx.AddFootnote("First")
+		require.NoError(err)
+		referenceXml, err := docx.AddFootnote("Second")
+		require.NoError(err)
+		assert.Contains(referenceXml, `<w:footnoteReference w:id="2"/>`)
+
+		referenceXml, err = docx.AddEndnote("First endnote")
+		require.NoError(err)
+		assert.Contains(referenceXml, `<w:endnoteReference w:id="1"/>`)
+		assert.Equal([]string{"word/footnotes.xml", "word/endnotes.xml"}, docx.GetTemplatePartNames())
+		assert.NotNil(docx.documentRelationships.FindByType(relationships.ENDNOTES_TYPE))
+	})
+
+	t.Run("Should return an error for text which isn't valid XML", func(t *testing.T) {
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(t, err)
+
+		_, err = docx.AddFootnote("<b>")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,134 @@
+package docxwrappers
+
+import (
+	"errors"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/properties"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// A part holding properties of the document, which is related to the package rather than the main document.
+type propertiesPart struct {
+	relType     string
+	partName    string
+	contentType string
+	xmlString   string
+}
+
+var (
+	corePropertiesPart = propertiesPart{
+		relType:     relationships.CORE_PROPERTIES_TYPE,
+		partName:    "docProps/core.xml",
+		contentType: "application/vnd.openxmlformats-package.core-properties+xml",
+		xmlString:   properties.CorePropertiesXml,
+	}
+	extendedPropertiesPart = propertiesPart{
+		relType:     relationships.EXTENDED_PROPERTIES_TYPE,
+		partName:    "docProps/app.xml",
+		contentType: "application/vnd.openxmlformats-officedocument.extended-properties+xml",
+		xmlString:   properties.ExtendedPropertiesXml,
+	}
+	customPropertiesPart = propertiesPart{
+		relType:     relationships.CUSTOM_PROPERTIES_TYPE,
+		partName:    "docProps/custom.xml",
+		contentType: "application/vnd.openxmlformats-officedocument.custom-properties+xml",
+		xmlString:   properties.CustomPropertiesXml,
+	}
+)
+
+// Get the names of the parts holding the properties of the document, e.g. docProps/core.xml.
+func (d *XmlDocx) GetPropertiesPartNames() []string {
+	partNames := []string{}
+	for _, part := range []propertiesPart{corePropertiesPart, extendedPropertiesPart, customPropertiesPart} {
+		if partName, ok := d.getPropertiesPartName(part.relType); ok {
+			partNames = append(partNames, partName)
+		}
+	}
+	return partNames
+}
+
+// Set the properties of the document, adding the parts which hold them if the document doesn't have them.
+func (d *XmlDocx) SetProperties(p *properties.Properties) error {
+	core, err := d.getOrAddPropertiesPart(corePropertiesPart)
+	if err != nil {
+		return err
+	}
+	properties.SetCoreProperties(core, p)
+
+	if p.HasExtendedProperties() {
+		extended, err := d.getOrAddPropertiesPart(extendedPropertiesPart)
+		if err != nil {
+			return err
+		}
+		properties.SetExtendedProperties(extended, p)
+	}
+
+	if len(p.Custom) > 0 {
+		custom, err := d.getOrAddPropertiesPart(customPropertiesPart)
+		if err != nil {
+			return err
+		}
+		if err := properties.SetCustomProperties(custom, p.Custom); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get the name of the properties part related to the package by a relationship type.
+func (d *XmlDocx) getPropertiesPartName(relType string) (string, bool) {
+	packageRelationships, err := d.getRelationships("")
+	if err != nil {
+		return "", false
+	}
+	relationship := packageRelationships.FindByType(relType)
+	if relationship == nil || relationship.TargetMode == "External" {
+		return "", false
+	}
+
+	partName := relationships.ResolveTarget("", relationship.Target)
+	if _, ok := d.parts[partName]; !ok {
+		return "", false
+	}
+
+	return partName, true
+}
+
+// Get the root element of a properties part, adding the part if the document doesn't have it.
+func (d *XmlDocx) getOrAddPropertiesPart(part propertiesPart) (*xmltree.Element, error) {
+	partName, ok := d.getPropertiesPartName(part.relType)
+	if !ok {
+		partName = part.partName
+		nodes, err := xmltree.ParseString(part.xmlString)
+		if err != nil {
+			return nil, err
+		}
+		d.setXmlPart(partName, nodes)
+		d.contentTypes.AddOverride("/"+partName, part.contentType)
+
+		// The package relationships aren't held in memory so are written back straight away
+		packageRelationships, err := d.getRelationships("")
+		if err != nil {
+			return nil, err
+		}
+		packageRelationships.Add(part.relType, partName)
+		relsXml, err := packageRelationships.MarshalXml()
+		if err != nil {
+			return nil, err
+		}
+		d.setPart(relationships.PartName(""), []byte(relsXml))
+	}
+
+	nodes, err := d.getXmlPart(partName)
+	if err != nil {
+		return nil, err
+	}
+	root := xmltree.Root(nodes)
+	if root == nil {
+		return nil, errors.New(partName + " has no root element")
+	}
+
+	return root, nil
+}
@@ -0,0 +1,57 @@
+package docxwrappers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/contenttypes"
+	"github.com/tomwatkins1994/go-docx-template/internal/properties"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+)
+
+func TestXmlSetProperties(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+	assert.Equal([]string{"docProps/core.xml", "docProps/app.xml"}, docx.GetPropertiesPartNames())
+
+	err = docx.SetProperties(&properties.Properties{
+		Title:   "Quote B-00001",
+		Company: "TW Software",
+		Custom:  map[string]any{"Client": "Jane's Shop"},
+	})
+	require.NoError(err)
+
+	coreXml, err := docx.GetPartXml("docProps/core.xml")
+	require.NoError(err)
+	assert.Contains(coreXml, `<dc:title>Quote B-00001</dc:title>`)
+	assert.Contains(coreXml, `<dc:creator>Thomas Watkins</dc:creator>`)
+	appXml, err := docx.GetPartXml("docProps/app.xml")
+	require.NoError(err)
+	assert.Contains(appXml, `<Company>TW Software</Company>`)
+
+	// The template doesn't have custom properties so the part is added
+	assert.Contains(docx.contentTypes.Overrides, contenttypes.Override{
+		PartName:    "/docProps/custom.xml",
+		ContentType: customPropertiesPart.contentType,
+	})
+	packageRelationships, err := docx.getRelationships("")
+	require.NoError(err)
+	relationship := packageRelationships.FindByType(relationships.CUSTOM_PROPERTIES_TYPE)
+	require.NotNil(relationship)
+	assert.Equal("docProps/custom.xml", relationship.Target)
+
+	buf := &bytes.Buffer{}
+	require.NoError(docx.Save(buf))
+	reopened, err := NewXmlDocx(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(err)
+
+	assert.Equal([]string{"docProps/core.xml", "docProps/app.xml", "docProps/custom.xml"}, reopened.GetPropertiesPartNames())
+	customXml, err := reopened.GetPartXml("docProps/custom.xml")
+	require.NoError(err)
+	assert.Contains(customXml, `<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="Client"><vt:lpwstr>Jane's Shop</vt:lpwstr></property>`)
+}
@@ -0,0 +1,172 @@
+package docxwrappers
+
+import "github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+
+// The styles given to references which functions such as footnote add in the middle of a run.
+var referenceStyles = map[string]string{
+	"w:footnoteReference": footnoteType.referenceStyle,
+	"w:endnoteReference":  endnoteType.referenceStyle,
+	"w:commentReference":  commentReferenceStyle,
+
+	// Field characters and codes added by functions such as toc aren't styled, but go in runs of their own
+	"w:fldChar":   "",
+	"w:instrText": "",
+}
+
+func getReferenceStyle(node xmltree.Node) (string, bool) {
+	element, ok := node.(*xmltree.Element)
+	if !ok {
+		return "", false
+	}
+	style, ok := referenceStyles[element.Tag()]
+	return style, ok
+}
+
+// Range markers such as the start and end of a comment or bookmark belong between runs rather than inside them.
+func isRangeMarker(node xmltree.Node) bool {
+	element, ok := node.(*xmltree.Element)
+	return ok && (element.Is("w:commentRangeStart") || element.Is("w:commentRangeEnd") ||
+		element.Is("w:bookmarkStart") || element.Is("w:bookmarkEnd"))
+}
+
+// Hyperlinks added by functions such as link hold runs of their own, so belong between runs too.
+func isHyperlink(node xmltree.Node) bool {
+	element, ok := node.(*xmltree.Element)
+	return ok && element.Is("w:hyperlink")
+}
+
+// Runs added by functions such as charStyle are nested in the run of the tag until they are split out.
+func isNestedRun(node xmltree.Node) bool {
+	element, ok := node.(*xmltree.Element)
+	return ok && element.Is("w:r")
+}
+
+// Move references which share a run with text into runs of their own, so the reference can be
+// styled while the text around it keeps the formatting of the run, as do runs nested in it. Range markers
+// and hyperlinks are moved out of the run altogether.
+func splitReferenceRuns(nodes []xmltree.Node) {
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		element, ok := node.(*xmltree.Element)
+		if !ok {
+			return false
+		}
+
+		children := make([]xmltree.Node, 0, len(element.Children))
+		for _, child := range element.Children {
+			if run, ok := child.(*xmltree.Element); ok && run.Is("w:r") && needsSplitting(run) {
+				children = append(children, splitReferenceRun(run)...)
+			} else {
+				children = append(children, child)
+			}
+		}
+		element.Children = children
+
+		return true
+	})
+}
+
+// Whether a run contains a range marker, hyperlink or nested run, or a reference along with other content or other references.
+// References are always alone in a run, apart from the run properties, when Word creates them.
+func needsSplitting(run *xmltree.Element) bool {
+	references := 0
+	hasOtherContent := false
+	for _, child := range run.Elements() {
+		if isRangeMarker(child) || isHyperlink(child) || isNestedRun(child) {
+			return true
+		}
+		if _, ok := getReferenceStyle(child); ok {
+			references++
+		} else if !child.Is("w:rPr") {
+			hasOtherContent = true
+		}
+	}
+	return references > 1 || references == 1 && hasOtherContent
+}
+
+func splitReferenceRun(run *xmltree.Element) []xmltree.Node {
+	runProperties := run.Find("w:rPr")
+	newRun := func(referenceStyle string) *xmltree.Element {
+		newRun := &xmltree.Element{Name: run.Name, Attr: append(run.Attr[:0:0], run.Attr...)}
+		var properties *xmltree.Element
+		if runProperties != nil {
+			properties = runProperties.Clone()
+		}
+		if referenceStyle != "" {
+			if properties == nil {
+				properties = xmltree.NewElement("w:rPr")
+			}
+			children := []xmltree.Node{xmltree.NewElement("w:rStyle", "w:val", referenceStyle)}
+			for _, child := range properties.Children {
+				if element, ok := child.(*xmltree.Element); !ok || !element.Is("w:rStyle") {
+					children = append(children, child)
+				}
+			}
+			properties.Children = children
+		}
+		if properties != nil {
+			newRun.Children = append(newRun.Children, properties)
+		}
+		return newRun
+	}
+
+	nodes := []xmltree.Node{}
+	var currentRun *xmltree.Element
+	for _, child := range run.Children {
+		element, isElement := child.(*xmltree.Element)
+		referenceStyle, isReference := getReferenceStyle(child)
+		switch {
+		case isElement && element.Is("w:rPr"):
+			continue
+		case isRangeMarker(child):
+			nodes = append(nodes, child)
+			currentRun = nil
+		case isHyperlink(child):
+			// The runs of the hyperlink take the formatting of the run it was in
+			for _, hyperlinkRun := range element.FindAll("w:r") {
+				styledRun := newRun(hyperlinkStyle)
+				for _, runChild := range hyperlinkRun.Children {
+					if runElement, ok := runChild.(*xmltree.Element); !ok || !runElement.Is("w:rPr") {
+						styledRun.Children = append(styledRun.Children, runChild)
+					}
+				}
+				hyperlinkRun.Children = styledRun.Children
+			}
+			nodes = append(nodes, child)
+			currentRun = nil
+		case isNestedRun(child):
+			// The nested run takes the formatting of the run it was in along with its own character style
+			styledRun := newRun("")
+			if properties := element.Find("w:rPr"); properties != nil {
+				if style := properties.Find("w:rStyle"); style != nil {
+					characterStyle, _ := style.AttrValue("w:val")
+					styledRun = newRun(characterStyle)
+				}
+			}
+			for _, runChild := range element.Children {
+				if runElement, ok := runChild.(*xmltree.Element); !ok || !runElement.Is("w:rPr") {
+					styledRun.Children = append(styledRun.Children, runChild)
+				}
+			}
+			nodes = append(nodes, styledRun)
+			currentRun = nil
+		case isReference:
+			referenceRun := newRun(referenceStyle)
+			referenceRun.Children = append(referenceRun.Children, element)
+			nodes = append(nodes, referenceRun)
+			currentRun = nil
+		case isElement && element.Is("w:t") && element.Text() == "":
+			continue
+		default:
+			if currentRun == nil {
+				currentRun = newRun("")
+				nodes = append(nodes, currentRun)
+			}
+			if isElement && element.Is("w:t") {
+				setXmlText(element, element.Text())
+			}
+			currentRun.Children = append(currentRun.Children, child)
+		}
+	}
+
+	return nodes
+}
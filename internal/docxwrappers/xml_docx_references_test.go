@@ -0,0 +1,59 @@
+package docxwrappers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestSplitReferenceRuns(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:              "Reference in the middle of text",
+			inputXml:          `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Agreed</w:t><w:footnoteReference w:id="1"/><w:t xml:space="preserve"> today</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Agreed</w:t></w:r><w:r><w:rPr><w:rStyle w:val="FootnoteReference"/><w:b/></w:rPr><w:footnoteReference w:id="1"/></w:r><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"> today</w:t></w:r></w:p>`,
+		},
+		{
+			name:              "Reference on its own",
+			inputXml:          `<w:p><w:r><w:t></w:t><w:endnoteReference w:id="1"/><w:t xml:space="preserve"></w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:rPr><w:rStyle w:val="EndnoteReference"/></w:rPr><w:endnoteReference w:id="1"/></w:r></w:p>`,
+		},
+		{
+			name:              "References created by Word are left as they are",
+			inputXml:          `<w:p><w:r><w:t>Agreed</w:t></w:r><w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteReference w:id="1"/></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>Agreed</w:t></w:r><w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteReference w:id="1"/></w:r></w:p>`,
+		},
+		{
+			name:              "Comment range markers are moved out of the run",
+			inputXml:          `<w:p><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">See </w:t><w:commentRangeStart w:id="0"/><w:t xml:space="preserve">clause 4</w:t><w:commentRangeEnd w:id="0"/><w:commentReference w:id="0"/><w:t xml:space="preserve">.</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">See </w:t></w:r><w:commentRangeStart w:id="0"/><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">clause 4</w:t></w:r><w:commentRangeEnd w:id="0"/><w:r><w:rPr><w:rStyle w:val="CommentReference"/><w:i/></w:rPr><w:commentReference w:id="0"/></w:r><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">.</w:t></w:r></w:p>`,
+		},
+		{
+			name:              "Hyperlinks are moved out of the run and take its formatting",
+			inputXml:          `<w:p><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">See </w:t><w:hyperlink w:anchor="Scope"><w:r><w:t>Scope</w:t></w:r></w:hyperlink><w:t xml:space="preserve">.</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">See </w:t></w:r><w:hyperlink w:anchor="Scope"><w:r><w:rPr><w:rStyle w:val="Hyperlink"/><w:i/></w:rPr><w:t>Scope</w:t></w:r></w:hyperlink><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">.</w:t></w:r></w:p>`,
+		},
+		{
+			name:              "Nested runs are moved out and keep their character style",
+			inputXml:          `<w:p><w:r><w:rPr><w:rStyle w:val="Emphasis"/><w:i/></w:rPr><w:t xml:space="preserve">Total </w:t><w:r><w:rPr><w:rStyle w:val="Strong"/></w:rPr><w:t>120</w:t></w:r><w:t xml:space="preserve"></w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:rPr><w:rStyle w:val="Emphasis"/><w:i/></w:rPr><w:t xml:space="preserve">Total </w:t></w:r><w:r><w:rPr><w:rStyle w:val="Strong"/><w:i/></w:rPr><w:t>120</w:t></w:r></w:p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := xmltree.ParseString(tt.inputXml)
+			require.NoError(t, err)
+
+			splitReferenceRuns(nodes)
+
+			assert.Equal(t, tt.expectedOutputXml, xmltree.Marshal(nodes...))
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package docxwrappers
+
+import (
+	"github.com/tomwatkins1994/go-docx-template/internal/revisions"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// Accept every tracked change in the document and the parts which can contain tags.
+func (d *XmlDocx) AcceptRevisions() error {
+	return d.resolveRevisions(revisions.AcceptAll)
+}
+
+// Reject every tracked change in the document and the parts which can contain tags.
+func (d *XmlDocx) RejectRevisions() error {
+	return d.resolveRevisions(revisions.RejectAll)
+}
+
+func (d *XmlDocx) resolveRevisions(resolve func(nodes []xmltree.Node) []xmltree.Node) error {
+	d.document = resolve(d.document)
+	for _, partName := range d.GetTemplatePartNames() {
+		nodes, err := d.getXmlPart(partName)
+		if err != nil {
+			return err
+		}
+		d.setXmlPart(partName, resolve(nodes))
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package docxwrappers
+
+import (
+	"fmt"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/styles"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// Return the XML for a paragraph of text in a paragraph style from the template, found by its ID or name,
+// e.g. "Heading2" or "Heading 2". The text should already be XML escaped.
+func (d *XmlDocx) AddStyledParagraph(style string, text string) (xmlString string, err error) {
+	styleId, err := d.findStyle(style, "paragraph")
+	if err != nil {
+		return "", err
+	}
+
+	// The paragraph the tag is in is split around this one when the document XML is replaced
+	return fmt.Sprintf(
+		`</w:t><w:p><w:pPr><w:pStyle w:val="%s"/></w:pPr><w:r><w:t xml:space="preserve">%s</w:t></w:r></w:p><w:t xml:space="preserve">`,
+		styleId, text,
+	), nil
+}
+
+// Return the XML for a run of text in a character style from the template, found by its ID or name,
+// e.g. "Strong". The text should already be XML escaped.
+func (d *XmlDocx) AddStyledRun(style string, text string) (xmlString string, err error) {
+	styleId, err := d.findStyle(style, "character")
+	if err != nil {
+		return "", err
+	}
+
+	// The run is moved out of the run of the tag when the document XML is replaced, taking its formatting
+	return fmt.Sprintf(
+		`</w:t><w:r><w:rPr><w:rStyle w:val="%s"/></w:rPr><w:t xml:space="preserve">%s</w:t></w:r><w:t xml:space="preserve">`,
+		styleId, text,
+	), nil
+}
+
+// Get the styles defined in the template.
+func (d *XmlDocx) getStyles() *styles.Styles {
+	if partName, ok := d.getRelatedPartName(relationships.STYLES_TYPE); ok {
+		if nodes, err := d.getXmlPart(partName); err == nil {
+			return styles.New(xmltree.Root(nodes))
+		}
+	}
+	return styles.New(nil)
+}
+
+// Get the ID of a style by its ID or name, checking it is of the type expected.
+func (d *XmlDocx) findStyle(idOrName string, styleType string) (string, error) {
+	styleId, foundType, ok := d.getStyles().Find(idOrName)
+	if !ok {
+		return "", fmt.Errorf("style %q is not defined in the template", idOrName)
+	}
+	if foundType != styleType {
+		return "", fmt.Errorf("style %q is a %s style rather than a %s style", idOrName, foundType, styleType)
+	}
+	return styleId, nil
+}
+
+// Split paragraphs around the paragraphs added by functions such as style, which are nested in the run
+// of the tag until then. The parts before and after keep the properties of the paragraph and are removed
+// if nothing but empty text is left in them.
+func splitStyledParagraphs(nodes []xmltree.Node) {
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		element, ok := node.(*xmltree.Element)
+		if !ok {
+			return false
+		}
+
+		children := make([]xmltree.Node, 0, len(element.Children))
+		for _, child := range element.Children {
+			if paragraph, ok := child.(*xmltree.Element); ok && paragraph.Is("w:p") && hasNestedParagraphs(paragraph) {
+				children = append(children, splitStyledParagraph(paragraph)...)
+			} else {
+				children = append(children, child)
+			}
+		}
+		element.Children = children
+
+		return true
+	})
+}
+
+func hasNestedParagraphs(paragraph *xmltree.Element) bool {
+	for _, run := range paragraph.FindAll("w:r") {
+		if run.Find("w:p") != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func splitStyledParagraph(paragraph *xmltree.Element) []xmltree.Node {
+	// Only the last part keeps the section properties, as they end the section
+	properties := paragraph.Find("w:pPr")
+	var partProperties *xmltree.Element
+	if properties != nil {
+		partProperties = properties.Clone()
+		children := []xmltree.Node{}
+		for _, child := range partProperties.Children {
+			if element, ok := child.(*xmltree.Element); !ok || !element.Is("w:sectPr") {
+				children = append(children, child)
+			}
+		}
+		partProperties.Children = children
+	}
+	newPart := func() *xmltree.Element {
+		part := &xmltree.Element{Name: paragraph.Name, Attr: append(paragraph.Attr[:0:0], paragraph.Attr...)}
+		if partProperties != nil {
+			part.Children = append(part.Children, partProperties.Clone())
+		}
+		return part
+	}
+
+	nodes := []xmltree.Node{}
+	currentPart := newPart()
+	for _, child := range paragraph.Children {
+		element, isElement := child.(*xmltree.Element)
+		switch {
+		case isElement && element.Is("w:pPr"):
+			continue
+		case isElement && element.Is("w:r") && element.Find("w:p") != nil:
+			runProperties := element.Find("w:rPr")
+			var currentRun *xmltree.Element
+			for _, runChild := range element.Children {
+				runElement, isRunElement := runChild.(*xmltree.Element)
+				switch {
+				case isRunElement && runElement.Is("w:rPr"):
+					continue
+				case isRunElement && runElement.Is("w:p"):
+					if hasParagraphContent(currentPart) {
+						nodes = append(nodes, currentPart)
+					}
+					nodes = append(nodes, runElement)
+					currentPart = newPart()
+					currentRun = nil
+				default:
+					if currentRun == nil {
+						currentRun = &xmltree.Element{Name: element.Name, Attr: append(element.Attr[:0:0], element.Attr...)}
+						if runProperties != nil {
+							currentRun.Children = append(currentRun.Children, runProperties.Clone())
+						}
+						currentPart.Children = append(currentPart.Children, currentRun)
+					}
+					currentRun.Children = append(currentRun.Children, runChild)
+				}
+			}
+		default:
+			currentPart.Children = append(currentPart.Children, child)
+		}
+	}
+
+	if properties != nil {
+		currentPart.Children[0] = properties
+	}
+	if hasParagraphContent(currentPart) || properties != nil && properties.Find("w:sectPr") != nil {
+		nodes = append(nodes, currentPart)
+	}
+
+	return nodes
+}
+
+// Whether a paragraph has anything other than its properties and empty text, such as that left around a tag.
+func hasParagraphContent(paragraph *xmltree.Element) bool {
+	for _, child := range paragraph.Elements() {
+		if child.Is("w:pPr") {
+			continue
+		}
+		if !child.Is("w:r") {
+			return true
+		}
+		for _, runChild := range child.Elements() {
+			if !runChild.Is("w:rPr") && !(runChild.Is("w:t") && runChild.Text() == "") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package docxwrappers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestXmlAddStyledParagraph(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	paragraphXml, err := docx.AddStyledParagraph("Heading 2", "Costs &amp; Timescales")
+	require.NoError(err)
+	assert.Equal(`</w:t><w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t xml:space="preserve">Costs &amp; Timescales</w:t></w:r></w:p><w:t xml:space="preserve">`, paragraphXml)
+
+	paragraphXml, err = docx.AddStyledParagraph("Heading1", "Scope")
+	require.NoError(err)
+	assert.Contains(paragraphXml, `<w:pStyle w:val="Heading1"/>`)
+
+	_, err = docx.AddStyledParagraph("Heading 3", "Scope")
+	assert.EqualError(err, `style "Heading 3" is not defined in the template`)
+	_, err = docx.AddStyledParagraph("Heading 1 Char", "Scope")
+	assert.EqualError(err, `style "Heading 1 Char" is a character style rather than a paragraph style`)
+}
+
+func TestXmlAddStyledRun(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	runXml, err := docx.AddStyledRun("heading 1 char", "Scope")
+	require.NoError(err)
+	assert.Equal(`</w:t><w:r><w:rPr><w:rStyle w:val="Heading1Char"/></w:rPr><w:t xml:space="preserve">Scope</w:t></w:r><w:t xml:space="preserve">`, runXml)
+
+	_, err = docx.AddStyledRun("Heading2", "Scope")
+	assert.EqualError(err, `style "Heading2" is a paragraph style rather than a character style`)
+}
+
+func TestSplitStyledParagraphs(t *testing.T) {
+	heading := `<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t xml:space="preserve">Scope</w:t></w:r></w:p>`
+
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:              "Paragraph on its own",
+			inputXml:          `<w:body><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t></w:t>` + heading + `<w:t xml:space="preserve"></w:t></w:r></w:p></w:body>`,
+			expectedOutputXml: `<w:body>` + heading + `</w:body>`,
+		},
+		{
+			name:     "Paragraph between text",
+			inputXml: `<w:body><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t>Before</w:t>` + heading + `<w:t xml:space="preserve">After</w:t></w:r></w:p></w:body>`,
+			expectedOutputXml: `<w:body><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t>Before</w:t></w:r></w:p>` + heading +
+				`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">After</w:t></w:r></w:p></w:body>`,
+		},
+		{
+			name:              "Section properties stay on the last paragraph",
+			inputXml:          `<w:body><w:p><w:pPr><w:sectPr/></w:pPr><w:r><w:t>Before</w:t>` + heading + `<w:t></w:t></w:r></w:p></w:body>`,
+			expectedOutputXml: `<w:body><w:p><w:pPr/><w:r><w:t>Before</w:t></w:r></w:p>` + heading + `<w:p><w:pPr><w:sectPr/></w:pPr><w:r><w:t/></w:r></w:p></w:body>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := xmltree.ParseString(tt.inputXml)
+			require.NoError(t, err)
+
+			splitStyledParagraphs(nodes)
+
+			assert.Equal(t, tt.expectedOutputXml, xmltree.Marshal(nodes...))
+		})
+	}
+}
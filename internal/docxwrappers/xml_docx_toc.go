@@ -0,0 +1,268 @@
+package docxwrappers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/fields"
+	"github.com/tomwatkins1994/go-docx-template/internal/styles"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// The text Word shows in a table of contents when the document has no headings.
+const noTableOfContentsEntries = "No table of contents entries found."
+
+// Add a table of contents field and return its XML. The levels are the headings it includes, e.g. 1 and 3
+// for Heading 1 to Heading 3, or a single level for headings up to it. It defaults to 1 to 3 as in Word.
+// The entries are filled in when the document is rendered, and the field is marked to be updated when
+// the document is opened so Word adds the page numbers.
+func (d *XmlDocx) AddTableOfContents(levels ...int) (xmlString string, err error) {
+	from, to := 1, 3
+	switch len(levels) {
+	case 0:
+	case 1:
+		to = levels[0]
+	case 2:
+		from, to = levels[0], levels[1]
+	default:
+		return "", errors.New("a table of contents takes the first and last heading levels")
+	}
+	if from < 1 || to > 9 || from > to {
+		return "", fmt.Errorf("invalid heading levels %d to %d", from, to)
+	}
+
+	// The field characters are moved into runs of their own when the document XML is replaced
+	return fmt.Sprintf(
+		`</w:t><w:fldChar w:fldCharType="begin" w:dirty="true"/><w:instrText xml:space="preserve"> TOC \o "%d-%d" \h \z \u </w:instrText>`+
+			`<w:fldChar w:fldCharType="separate"/><w:fldChar w:fldCharType="end"/><w:t xml:space="preserve">`,
+		from, to,
+	), nil
+}
+
+// Mark every field to be updated when the document is opened in Word, so page numbers and
+// cross-references are correct. Word asks the reader before updating them.
+func (d *XmlDocx) SetUpdateFieldsOnOpen() error {
+	settings, err := d.getSettings()
+	if err != nil {
+		return err
+	}
+	getOrAddSetting(settings, "w:updateFields").SetAttr("w:val", "true")
+
+	return nil
+}
+
+// A heading included in a table of contents.
+type tocEntry struct {
+	level int
+	text  string
+}
+
+// Fill in the entries of a table of contents from the headings in the document. Page numbers and links
+// are left out as they aren't known until Word lays out the document, so the field is marked to be
+// updated by Word when the document is opened.
+func (d *XmlDocx) updateTableOfContents(field *fields.Field, arguments []string) {
+	from, to := 1, 9
+	for i, argument := range arguments {
+		if strings.EqualFold(argument, `\o`) && i+1 < len(arguments) {
+			if first, last, ok := strings.Cut(arguments[i+1], "-"); ok {
+				firstLevel, firstErr := strconv.Atoi(first)
+				lastLevel, lastErr := strconv.Atoi(last)
+				if firstErr == nil && lastErr == nil {
+					from, to = firstLevel, lastLevel
+				}
+			}
+		}
+	}
+
+	paragraphs := []*xmltree.Element{}
+	for _, entry := range d.getHeadings() {
+		if entry.level < from || entry.level > to {
+			continue
+		}
+		styleId := "TOC" + strconv.Itoa(entry.level)
+		d.ensureStyle(styleId, "paragraph", "toc "+strconv.Itoa(entry.level), fmt.Sprintf(
+			`<w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:autoRedefine/><w:uiPriority w:val="39"/><w:unhideWhenUsed/>`+
+				`<w:pPr><w:spacing w:after="100"/><w:ind w:left="%d"/></w:pPr>`,
+			(entry.level-1)*220,
+		))
+
+		paragraph := xmltree.NewElement("w:p")
+		properties := xmltree.NewElement("w:pPr")
+		properties.Children = append(properties.Children, xmltree.NewElement("w:pStyle", "w:val", styleId))
+		paragraph.Children = append(paragraph.Children, properties, newTextRun(entry.text))
+		paragraphs = append(paragraphs, paragraph)
+	}
+	if len(paragraphs) == 0 {
+		paragraph := xmltree.NewElement("w:p")
+		paragraph.Children = append(paragraph.Children, newTextRun(noTableOfContentsEntries))
+		paragraphs = append(paragraphs, paragraph)
+	}
+
+	if field.SetResultParagraphs(d.document, paragraphs) {
+		field.SetDirty()
+	}
+}
+
+// Get the headings in the main document in order, from the outline levels of their paragraphs or styles.
+func (d *XmlDocx) getHeadings() []tocEntry {
+	documentStyles := d.getStyles()
+
+	headings := []tocEntry{}
+	for _, paragraph := range xmltree.FindDescendants(d.document, "w:p") {
+		properties := paragraph.Find("w:pPr")
+		if properties == nil {
+			continue
+		}
+		level, ok := styles.ParseOutlineLevel(properties)
+		if !ok {
+			if style := properties.Find("w:pStyle"); style != nil {
+				styleId, _ := style.AttrValue("w:val")
+				level, ok = documentStyles.OutlineLevel(styleId)
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		var text strings.Builder
+		for _, t := range getParagraphTextElements(paragraph) {
+			text.WriteString(t.Text())
+		}
+		if strings.TrimSpace(text.String()) != "" {
+			headings = append(headings, tocEntry{level, strings.TrimSpace(text.String())})
+		}
+	}
+
+	return headings
+}
+
+func newTextRun(text string) *xmltree.Element {
+	run := xmltree.NewElement("w:r")
+	t := xmltree.NewElement("w:t")
+	setXmlText(t, text)
+	run.Children = append(run.Children, t)
+	return run
+}
+
+// A SEQ field, which numbers items such as figures and tables, e.g. SEQ Figure \* ROMAN.
+type sequenceField struct {
+	name string
+	// The \c switch repeats the previous number
+	repeat bool
+	// The \h switch hides the number, though it is still counted
+	hidden bool
+	// The number set by the \r switch, or -1
+	reset int
+	// The number format from the \* switch, e.g. ROMAN
+	format string
+}
+
+// Parse the arguments of a SEQ field. Fields with switches which aren't supported, such as \s which
+// restarts numbering at each heading, return false as their numbers can't be worked out.
+func parseSequenceField(arguments []string) (sequenceField, bool) {
+	sequence := sequenceField{name: strings.ToLower(arguments[1]), reset: -1}
+	for i := 2; i < len(arguments); i++ {
+		switch strings.ToLower(arguments[i]) {
+		case `\c`:
+			sequence.repeat = true
+		case `\h`:
+			sequence.hidden = true
+		case `\n`:
+		case `\r`:
+			if i+1 >= len(arguments) {
+				return sequence, false
+			}
+			n, err := strconv.Atoi(arguments[i+1])
+			if err != nil {
+				return sequence, false
+			}
+			sequence.reset = n
+			i++
+		case `\*`:
+			if i+1 >= len(arguments) {
+				return sequence, false
+			}
+			switch format := arguments[i+1]; strings.ToLower(format) {
+			case "mergeformat", "charformat":
+			case "arabic", "roman", "alphabetic":
+				sequence.format = format
+			default:
+				return sequence, false
+			}
+			i++
+		default:
+			return sequence, false
+		}
+	}
+	return sequence, true
+}
+
+// Get the next number of a sequence, counting hidden numbers but leaving them out of the result.
+func nextSequenceNumber(counters map[string]int, sequence sequenceField) string {
+	next := counters[sequence.name] + 1
+	if sequence.repeat {
+		next = counters[sequence.name]
+	}
+	if sequence.reset >= 0 {
+		next = sequence.reset
+	}
+	counters[sequence.name] = next
+
+	if sequence.hidden {
+		return ""
+	}
+	return formatSequenceNumber(next, sequence.format)
+}
+
+// Format a number with a field number format. ROMAN and ALPHABETIC are in capitals and roman
+// and alphabetic in lower case, e.g. 28 is XXVIII or BB.
+func formatSequenceNumber(n int, format string) string {
+	var formatted string
+	switch strings.ToLower(format) {
+	case "roman":
+		formatted = romanNumeral(n)
+	case "alphabetic":
+		formatted = alphabeticNumber(n)
+	default:
+		return strconv.Itoa(n)
+	}
+	if format == strings.ToLower(format) {
+		return strings.ToLower(formatted)
+	}
+	return formatted
+}
+
+var romanNumerals = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"},
+	{50, "L"}, {40, "XL"}, {10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// Write a number in Roman numerals as Word does, e.g. 1994 is MCMXCIV. Numbers below 1 are written as
+// digits.
+func romanNumeral(n int) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	var buf strings.Builder
+	for _, numeral := range romanNumerals {
+		for n >= numeral.value {
+			buf.WriteString(numeral.numeral)
+			n -= numeral.value
+		}
+	}
+	return buf.String()
+}
+
+// Write a number as letters as Word does, e.g. 1 is A, 26 is Z and 27 is AA. Numbers below 1 are written
+// as digits.
+func alphabeticNumber(n int) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	return strings.Repeat(string(rune('A'+(n-1)%26)), (n-1)/26+1)
+}
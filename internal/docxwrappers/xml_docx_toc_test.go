@@ -0,0 +1,134 @@
+package docxwrappers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestXmlTableOfContents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	toc, err := docx.AddTableOfContents(2)
+	require.NoError(err)
+	heading := func(style string, text string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="` + style + `"/></w:pPr><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>` + toc + `</w:t></w:r></w:p>` +
+		heading("Heading1", "Introduction") +
+		heading("Heading2", "Scope") +
+		`<w:p><w:pPr><w:outlineLvl w:val="2"/></w:pPr><w:r><w:t>Too deep</w:t></w:r></w:p>` +
+		heading("Heading1", "Costs") +
+		`</w:body></w:document>`)
+	require.NoError(err)
+
+	err = docx.UpdateFieldResults()
+	require.NoError(err)
+
+	documentXml, err := docx.GetDocumentXml()
+	require.NoError(err)
+	assert.Contains(documentXml, `<w:body><w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr>`+
+		`<w:r><w:rPr><w:b/></w:rPr><w:fldChar w:fldCharType="begin" w:dirty="true"/></w:r>`+
+		`<w:r><w:rPr><w:b/></w:rPr><w:instrText xml:space="preserve"> TOC \o "1-2" \h \z \u </w:instrText></w:r>`+
+		`<w:r><w:rPr><w:b/></w:rPr><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>Introduction</w:t></w:r></w:p>`+
+		`<w:p><w:pPr><w:pStyle w:val="TOC2"/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>`+
+		`<w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr><w:r><w:t>Costs</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:rPr><w:b/></w:rPr><w:fldChar w:fldCharType="end"/></w:r></w:p>`)
+
+	stylesXml, err := docx.GetPartXml("word/styles.xml")
+	require.NoError(err)
+	assert.Contains(stylesXml, `<w:style w:type="paragraph" w:styleId="TOC2"><w:name w:val="toc 2"/>`)
+
+	// Updating again replaces the entries rather than adding to them
+	err = docx.UpdateFieldResults()
+	require.NoError(err)
+	updatedXml, err := docx.GetDocumentXml()
+	require.NoError(err)
+	assert.Equal(documentXml, updatedXml)
+
+	_, err = docx.AddTableOfContents(3, 1)
+	assert.EqualError(err, "invalid heading levels 3 to 1")
+}
+
+func TestXmlExistingTableOfContentsMarkedDirty(t *testing.T) {
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> TOC \o "1-3" \h </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:hyperlink w:anchor="_Toc1"><w:r><w:t>Introduction</w:t></w:r><w:r><w:t>1</w:t></w:r></w:hyperlink></w:p>` +
+		`<w:p><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Introduction</w:t></w:r></w:p>` +
+		`</w:body></w:document>`)
+	require.NoError(err)
+
+	err = docx.UpdateFieldResults()
+	require.NoError(err)
+
+	// The page numbers and links are dropped, so Word is asked to update the field
+	documentXml, err := docx.GetDocumentXml()
+	require.NoError(err)
+	assert.Contains(t, documentXml, `<w:body><w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr><w:r><w:fldChar w:fldCharType="begin" w:dirty="true"/></w:r>`)
+	assert.NotContains(t, documentXml, `w:hyperlink`)
+}
+
+func TestXmlSequenceFields(t *testing.T) {
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	seq := func(instruction string) string {
+		return `<w:p><w:fldSimple w:instr=" ` + instruction + ` "><w:r><w:t>1</w:t></w:r></w:fldSimple></w:p>`
+	}
+	err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		seq(`SEQ Figure \* ARABIC`) + seq(`SEQ Table`) + seq(`SEQ Figure`) + seq(`SEQ Figure \c`) + seq(`SEQ Figure \r 10`) + seq(`SEQ Figure \h`) + seq(`SEQ Figure`) +
+		`</w:body></w:document>`)
+	require.NoError(err)
+
+	err = docx.UpdateFieldResults()
+	require.NoError(err)
+
+	numbers := []string{}
+	for _, field := range xmltree.FindDescendants(docx.document, "w:fldSimple") {
+		numbers = append(numbers, field.Find("w:r").Find("w:t").Text())
+	}
+	assert.Equal(t, []string{"1", "1", "2", "2", "10", "", "12"}, numbers)
+
+	err = docx.ReplaceDocumentXml(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		seq(`SEQ Figure \* ROMAN`) + seq(`SEQ Figure \* roman`) + seq(`SEQ Figure \r 27 \* ALPHABETIC \* MERGEFORMAT`) + seq(`SEQ Figure \* alphabetic`) +
+		seq(`SEQ Table \s 1`) + seq(`SEQ Table`) + seq(`SEQ Chart \* Ordinal`) +
+		`</w:body></w:document>`)
+	require.NoError(err)
+
+	err = docx.UpdateFieldResults()
+	require.NoError(err)
+
+	// Sequences with switches which aren't supported keep their results
+	numbers = []string{}
+	for _, field := range xmltree.FindDescendants(docx.document, "w:fldSimple") {
+		numbers = append(numbers, field.Find("w:r").Find("w:t").Text())
+	}
+	assert.Equal(t, []string{"I", "ii", "AA", "bb", "1", "1", "1"}, numbers)
+}
+
+func TestXmlSetUpdateFieldsOnOpen(t *testing.T) {
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(t, err)
+
+	err = docx.SetUpdateFieldsOnOpen()
+	require.NoError(t, err)
+
+	settingsXml, err := docx.GetPartXml("word/settings.xml")
+	require.NoError(t, err)
+	assert.Contains(t, settingsXml, `<w:characterSpacingControl w:val="doNotCompress"/><w:updateFields w:val="true"/><w:compat>`)
+}
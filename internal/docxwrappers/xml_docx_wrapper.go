@@ -0,0 +1,456 @@
+package docxwrappers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/contentcontrols"
+	"github.com/tomwatkins1994/go-docx-template/internal/contenttypes"
+	"github.com/tomwatkins1994/go-docx-template/internal/images"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/tags"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// Works directly with the XML of the document so nothing is lost when it is parsed and saved,
+// including elements such as revisions, content controls and fields which other libraries drop.
+type XmlDocx struct {
+	partNames    []string
+	parts        map[string][]byte
+	contentTypes *contenttypes.ContentTypes
+
+	documentPartName      string
+	document              []xmltree.Node
+	documentRelationships *relationships.Relationships
+
+	// Parts other than the main document which have been parsed, e.g. word/footnotes.xml.
+	// These are written back out when the document is saved.
+	xmlParts map[string][]xmltree.Node
+
+	// Comments added by AddComment which haven't been ended yet, most recent last.
+	openComments []int
+
+	// Bookmarks added by AddBookmark which haven't been ended yet, most recent last, and the names
+	// of those added since the document XML was last replaced.
+	openBookmarks  []int
+	addedBookmarks []string
+
+	maxDrawingId  int
+	maxBookmarkId int
+}
+
+const (
+	WORDPROCESSING_NAMESPACE         = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	RELATIONSHIPS_NAMESPACE          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	WORDPROCESSING_DRAWING_NAMESPACE = "http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
+	DRAWING_NAMESPACE                = "http://schemas.openxmlformats.org/drawingml/2006/main"
+	PICTURE_NAMESPACE                = "http://schemas.openxmlformats.org/drawingml/2006/picture"
+)
+
+func NewXmlDocx(reader io.ReaderAt, size int64) (*XmlDocx, error) {
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &XmlDocx{parts: make(map[string][]byte), xmlParts: make(map[string][]xmltree.Node)}
+	for _, f := range zipReader.File {
+		zf, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(zf)
+		zf.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		d.partNames = append(d.partNames, f.Name)
+		d.parts[f.Name] = data
+	}
+
+	d.contentTypes, err = contenttypes.GetContentTypes(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the main document from the package relationships
+	packageRelationships, err := d.getRelationships("")
+	if err != nil {
+		return nil, err
+	}
+	officeDocument := packageRelationships.FindByType(relationships.OFFICE_DOCUMENT_TYPE)
+	if officeDocument == nil {
+		return nil, errors.New("no main document found")
+	}
+	d.documentPartName = relationships.ResolveTarget("", officeDocument.Target)
+
+	documentXml, ok := d.parts[d.documentPartName]
+	if !ok {
+		return nil, fmt.Errorf("main document %s not found", d.documentPartName)
+	}
+	d.document, err = xmltree.Parse(documentXml)
+	if err != nil {
+		return nil, err
+	}
+
+	d.documentRelationships, err = d.getRelationships(d.documentPartName)
+	if err != nil {
+		return nil, err
+	}
+
+	d.maxDrawingId = d.getMaxDrawingId()
+	d.maxBookmarkId = getMaxBookmarkId(d.document)
+
+	return d, nil
+}
+
+func NewXmlDocxFromFilename(filename string) (*XmlDocx, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	fileinfo, err := reader.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fileinfo.Size()
+
+	docxtpl, err := NewXmlDocx(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return docxtpl, nil
+}
+
+// Get the relationships of a part, or an empty set if it has none.
+func (d *XmlDocx) getRelationships(partName string) (*relationships.Relationships, error) {
+	relsXml, ok := d.parts[relationships.PartName(partName)]
+	if !ok {
+		return relationships.New(), nil
+	}
+
+	return relationships.Parse(relsXml)
+}
+
+func (d *XmlDocx) setPart(partName string, data []byte) {
+	if _, ok := d.parts[partName]; !ok {
+		d.partNames = append(d.partNames, partName)
+	}
+	d.parts[partName] = data
+}
+
+// Get the parsed XML of a part, parsing it the first time it is needed.
+func (d *XmlDocx) getXmlPart(partName string) ([]xmltree.Node, error) {
+	if nodes, ok := d.xmlParts[partName]; ok {
+		return nodes, nil
+	}
+
+	data, ok := d.parts[partName]
+	if !ok {
+		return nil, fmt.Errorf("part %s not found", partName)
+	}
+	nodes, err := xmltree.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	d.xmlParts[partName] = nodes
+
+	return nodes, nil
+}
+
+// Replace the XML of a part, adding the part if it doesn't exist.
+func (d *XmlDocx) setXmlPart(partName string, nodes []xmltree.Node) {
+	if _, ok := d.parts[partName]; !ok {
+		d.setPart(partName, nil)
+	}
+	d.xmlParts[partName] = nodes
+}
+
+// Get the name of the part related to the main document by a relationship type, e.g. word/footnotes.xml.
+func (d *XmlDocx) getRelatedPartName(relType string) (string, bool) {
+	relationship := d.documentRelationships.FindByType(relType)
+	if relationship == nil || relationship.TargetMode == "External" {
+		return "", false
+	}
+
+	partName := relationships.ResolveTarget(d.documentPartName, relationship.Target)
+	if _, ok := d.parts[partName]; !ok {
+		return "", false
+	}
+
+	return partName, true
+}
+
+// Get the names of every part related to the main document by a relationship type, e.g. each header.
+func (d *XmlDocx) getRelatedPartNames(relType string) []string {
+	partNames := []string{}
+	for _, relationship := range d.documentRelationships.Relationships {
+		if relationship.Type != relType || relationship.TargetMode == "External" {
+			continue
+		}
+		partName := relationships.ResolveTarget(d.documentPartName, relationship.Target)
+		if _, ok := d.parts[partName]; ok && !slices.Contains(partNames, partName) {
+			partNames = append(partNames, partName)
+		}
+	}
+	return partNames
+}
+
+// Get the root element of the part related to the main document by a relationship type. If the document
+// doesn't have one, the part is added next to the main document from the XML given.
+func (d *XmlDocx) getOrAddXmlPart(relType string, fileName string, contentType string, xmlString string) (*xmltree.Element, error) {
+	partName, ok := d.getRelatedPartName(relType)
+	if !ok {
+		partName = path.Join(path.Dir(d.documentPartName), fileName)
+		nodes, err := xmltree.ParseString(xmlString)
+		if err != nil {
+			return nil, err
+		}
+
+		d.setXmlPart(partName, nodes)
+		d.documentRelationships.Add(relType, fileName)
+		d.contentTypes.AddOverride("/"+partName, contentType)
+	}
+
+	nodes, err := d.getXmlPart(partName)
+	if err != nil {
+		return nil, err
+	}
+	root := xmltree.Root(nodes)
+	if root == nil {
+		return nil, errors.New(partName + " has no root element")
+	}
+
+	return root, nil
+}
+
+func (d *XmlDocx) GetDocumentXml() (string, error) {
+	return xmltree.Marshal(d.document...), nil
+}
+
+func (d *XmlDocx) ReplaceDocumentXml(xmlString string) error {
+	document, err := xmltree.ParseString(xmlString)
+	if err != nil {
+		return err
+	}
+	if len(d.openComments) > 0 {
+		id := d.openComments[len(d.openComments)-1]
+		d.openComments = nil
+		return fmt.Errorf("comment %d was not ended", id)
+	}
+	if len(d.openBookmarks) > 0 {
+		d.openBookmarks = nil
+		return errors.New("a bookmark was not ended")
+	}
+	d.restartRepeatedLists(document)
+	splitStyledParagraphs(document)
+	splitReferenceRuns(document)
+	d.fixRepeatedBookmarks(document)
+	d.document = document
+	d.addedBookmarks = nil
+
+	return nil
+}
+
+func (d *XmlDocx) MergeTags(delims *tags.Delimiters) {
+	mergeXmlTags(d.document, delims)
+
+	for _, partName := range d.GetTemplatePartNames() {
+		if nodes, err := d.getXmlPart(partName); err == nil {
+			mergeXmlTags(nodes, delims)
+		}
+	}
+}
+
+// Turn content controls into tags so they are filled in from the data.
+func (d *XmlDocx) AddContentControlTags(delims *tags.Delimiters) {
+	contentcontrols.AddTags(d.document, delims.Left, delims.Right)
+
+	for _, partName := range d.GetTemplatePartNames() {
+		if nodes, err := d.getXmlPart(partName); err == nil {
+			contentcontrols.AddTags(nodes, delims.Left, delims.Right)
+		}
+	}
+}
+
+func mergeXmlTags(nodes []xmltree.Node, delims *tags.Delimiters) {
+	for _, paragraph := range xmltree.FindDescendants(nodes, "w:p") {
+		mergeXmlTagsInParagraph(paragraph, delims)
+	}
+}
+
+// Get the text elements of a paragraph in document order. This includes text in runs nested inside
+// hyperlinks, fields, smart tags, content controls and inserted revisions, but not deleted text
+// or paragraphs nested inside text boxes, which are merged separately.
+func getParagraphTextElements(paragraph *xmltree.Element) []*xmltree.Element {
+	textElements := []*xmltree.Element{}
+	xmltree.Walk(paragraph.Children, func(node xmltree.Node) bool {
+		element, ok := node.(*xmltree.Element)
+		if !ok {
+			return false
+		}
+		switch element.Tag() {
+		case "w:t":
+			textElements = append(textElements, element)
+			return false
+		case "w:p", "w:txbxContent", "w:del", "w:moveFrom":
+			return false
+		}
+		return true
+	})
+
+	return textElements
+}
+
+func mergeXmlTagsInParagraph(paragraph *xmltree.Element, delims *tags.Delimiters) {
+	texts := getParagraphTextElements(paragraph)
+
+	textValues := make([]string, len(texts))
+	for i, text := range texts {
+		textValues[i] = text.Text()
+	}
+	for i, mergedText := range delims.MergeTags(textValues) {
+		if mergedText != textValues[i] {
+			setXmlText(texts[i], mergedText)
+		}
+	}
+}
+
+// Set the text of a w:t element, preserving any spaces at the start or end.
+func setXmlText(text *xmltree.Element, value string) {
+	text.SetText(value)
+	if strings.TrimSpace(value) != value {
+		text.SetAttr("xml:space", "preserve")
+	}
+}
+
+func (d *XmlDocx) getMaxDrawingId() int {
+	maxId := 0
+	for _, docPr := range xmltree.FindDescendants(d.document, "wp:docPr") {
+		if id, ok := docPr.AttrValue("id"); ok {
+			if n, err := strconv.Atoi(id); err == nil {
+				maxId = max(maxId, n)
+			}
+		}
+	}
+	return maxId
+}
+
+// Declare a namespace on the root element of the document if it hasn't been already.
+func (d *XmlDocx) ensureNamespace(prefix string, namespace string) {
+	root := xmltree.Root(d.document)
+	if root == nil {
+		return
+	}
+	if _, ok := root.AttrValue("xmlns:" + prefix); !ok {
+		root.SetAttr("xmlns:"+prefix, namespace)
+	}
+}
+
+// Get a name for a new part which isn't already in use, e.g. word/media/image3.png.
+func (d *XmlDocx) newPartName(dir string, prefix string, ext string) string {
+	for i := 1; ; i++ {
+		partName := path.Join(dir, prefix+strconv.Itoa(i)+ext)
+		if _, ok := d.parts[partName]; !ok {
+			return partName
+		}
+	}
+}
+
+func (d *XmlDocx) AddInlineImage(i *images.InlineImage) (xmlString string, err error) {
+	data := i.GetData()
+	if data == nil {
+		return "", errors.New("image has no data")
+	}
+
+	// Add the image to the package
+	ext := strings.ToLower(i.Ext)
+	mediaPartName := d.newPartName(path.Join(path.Dir(d.documentPartName), "media"), "image", ext)
+	d.setPart(mediaPartName, *data)
+
+	relativeTarget := strings.TrimPrefix(mediaPartName, path.Dir(d.documentPartName)+"/")
+	relationshipId := d.documentRelationships.Add(relationships.IMAGE_TYPE, relativeTarget)
+
+	// Append the content types
+	contentTypes, err := i.GetContentTypes()
+	if err != nil {
+		return "", err
+	}
+	for _, contentType := range contentTypes {
+		d.contentTypes.AddContentType(contentType)
+	}
+
+	// Correctly size the image
+	w, h, err := i.GetSizeEmus()
+	if err != nil {
+		return "", err
+	}
+
+	d.ensureNamespace("r", RELATIONSHIPS_NAMESPACE)
+	d.ensureNamespace("wp", WORDPROCESSING_DRAWING_NAMESPACE)
+
+	d.maxDrawingId++
+	name := path.Base(mediaPartName)
+	xmlString = fmt.Sprintf(
+		`<w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0"><wp:extent cx="%[1]d" cy="%[2]d"/><wp:docPr id="%[3]d" name="Picture %[3]d"/>`+
+			`<wp:cNvGraphicFramePr><a:graphicFrameLocks xmlns:a="%[4]s" noChangeAspect="1"/></wp:cNvGraphicFramePr>`+
+			`<a:graphic xmlns:a="%[4]s"><a:graphicData uri="%[5]s"><pic:pic xmlns:pic="%[5]s">`+
+			`<pic:nvPicPr><pic:cNvPr id="0" name="%[6]s"/><pic:cNvPicPr/></pic:nvPicPr>`+
+			`<pic:blipFill><a:blip r:embed="%[7]s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+			`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%[1]d" cy="%[2]d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>`+
+			`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing>`,
+		w, h, d.maxDrawingId, DRAWING_NAMESPACE, PICTURE_NAMESPACE, name, relationshipId,
+	)
+
+	return xmlString, nil
+}
+
+func (d *XmlDocx) Save(w io.Writer) error {
+	// Write back the parts which are held in memory
+	d.setPart(d.documentPartName, []byte(xmltree.Marshal(d.document...)))
+	for partName, nodes := range d.xmlParts {
+		d.setPart(partName, []byte(xmltree.Marshal(nodes...)))
+	}
+
+	relsXml, err := d.documentRelationships.MarshalXml()
+	if err != nil {
+		return err
+	}
+	d.setPart(relationships.PartName(d.documentPartName), []byte(relsXml))
+
+	contentTypesXml, err := d.contentTypes.MarshalXml()
+	if err != nil {
+		return err
+	}
+	d.setPart("[Content_Types].xml", []byte(contentTypesXml))
+
+	generatedZip := zip.NewWriter(w)
+
+	for _, partName := range d.partNames {
+		newFile, err := generatedZip.Create(partName)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(newFile, bytes.NewReader(d.parts[partName])); err != nil {
+			return err
+		}
+	}
+
+	if err := generatedZip.Close(); err != nil {
+		return err
+	}
+
+	return nil
+}
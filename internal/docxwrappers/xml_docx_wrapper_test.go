@@ -0,0 +1,205 @@
+package docxwrappers
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/contenttypes"
+	"github.com/tomwatkins1994/go-docx-template/internal/images"
+	"github.com/tomwatkins1994/go-docx-template/internal/relationships"
+	"github.com/tomwatkins1994/go-docx-template/internal/tags"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestXmlGetDocumentXml(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	xmlString, err := docx.GetDocumentXml()
+	require.NoError(err)
+	assert.Contains(xmlString, "<w:document")
+}
+
+func TestXmlSetDocumentXml(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	newXmlString := `<w:document xmlns:w="` + WORDPROCESSING_NAMESPACE + `"><w:body><w:p><w:r><w:t>Hello, World!</w:t></w:r></w:p></w:body></w:document>`
+	err = docx.ReplaceDocumentXml(newXmlString)
+	require.NoError(err)
+
+	xmlString, err := docx.GetDocumentXml()
+	require.NoError(err)
+	assert.Equal(newXmlString, xmlString)
+}
+
+func TestXmlMergeTagsInParagraph(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:              "Tags in text nodes in different runs should get merged",
+			inputXml:          `<w:p><w:r><w:t>{{ .tag </w:t></w:r><w:r><w:t>}}</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>{{ .tag }}</w:t></w:r><w:r><w:t/></w:r></w:p>`,
+		},
+		{
+			name:              "Proofing errors between runs",
+			inputXml:          `<w:p><w:r><w:t>{{ .</w:t></w:r><w:proofErr w:type="spellStart"/><w:r><w:t>FirstName</w:t></w:r><w:proofErr w:type="spellEnd"/><w:r><w:t> }}</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>{{ .FirstName }}</w:t></w:r><w:proofErr w:type="spellStart"/><w:r><w:t/></w:r><w:proofErr w:type="spellEnd"/><w:r><w:t/></w:r></w:p>`,
+		},
+		{
+			name:              "Bookmarks between runs",
+			inputXml:          `<w:p><w:r><w:t>{{ .</w:t></w:r><w:bookmarkStart w:id="0" w:name="Name"/><w:r><w:t>Name }}</w:t></w:r><w:bookmarkEnd w:id="0"/></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>{{ .Name }}</w:t></w:r><w:bookmarkStart w:id="0" w:name="Name"/><w:r><w:t/></w:r><w:bookmarkEnd w:id="0"/></w:p>`,
+		},
+		{
+			name:              "Runs in hyperlinks",
+			inputXml:          `<w:p><w:r><w:t>{{ </w:t></w:r><w:hyperlink r:id="rId1"><w:r><w:t>.Link</w:t></w:r></w:hyperlink><w:r><w:t> }}</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>{{ .Link }}</w:t></w:r><w:hyperlink r:id="rId1"><w:r><w:t/></w:r></w:hyperlink><w:r><w:t/></w:r></w:p>`,
+		},
+		{
+			name:              "Runs in simple fields, smart tags and content controls",
+			inputXml:          `<w:p><w:fldSimple w:instr="PAGE"><w:r><w:t>{{ </w:t></w:r></w:fldSimple><w:smartTag w:element="place"><w:r><w:t>.Place</w:t></w:r></w:smartTag><w:sdt><w:sdtContent><w:r><w:t> }}</w:t></w:r></w:sdtContent></w:sdt></w:p>`,
+			expectedOutputXml: `<w:p><w:fldSimple w:instr="PAGE"><w:r><w:t>{{ .Place }}</w:t></w:r></w:fldSimple><w:smartTag w:element="place"><w:r><w:t/></w:r></w:smartTag><w:sdt><w:sdtContent><w:r><w:t/></w:r></w:sdtContent></w:sdt></w:p>`,
+		},
+		{
+			name:              "Inserted text is merged and deleted text is ignored",
+			inputXml:          `<w:p><w:r><w:t>{{ .</w:t></w:r><w:del w:id="1"><w:r><w:delText>Old</w:delText></w:r></w:del><w:ins w:id="2"><w:r><w:t>New }}</w:t></w:r></w:ins></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>{{ .New }}</w:t></w:r><w:del w:id="1"><w:r><w:delText>Old</w:delText></w:r></w:del><w:ins w:id="2"><w:r><w:t/></w:r></w:ins></w:p>`,
+		},
+		{
+			name:              "Tags keep the formatting of the run they start in and surrounding text keeps its own",
+			inputXml:          `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Dear {{ .Na</w:t></w:r><w:r><w:rPr><w:i/></w:rPr><w:t>me }} and welcome</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Dear {{ .Name }}</w:t></w:r><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve"> and welcome</w:t></w:r></w:p>`,
+		},
+		{
+			name:              "Autocorrected characters in merged tags should get normalised",
+			inputXml:          "<w:p><w:r><w:t>{{ if eq .Status </w:t></w:r><w:r><w:t>\u201cPaid\u201d }}</w:t></w:r></w:p>",
+			expectedOutputXml: `<w:p><w:r><w:t>{{ if eq .Status "Paid" }}</w:t></w:r><w:r><w:t/></w:r></w:p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			nodes, err := xmltree.ParseString(tt.inputXml)
+			require.NoError(err)
+
+			mergeXmlTagsInParagraph(xmltree.Root(nodes), tags.DefaultDelimiters)
+
+			assert.Equal(tt.expectedOutputXml, xmltree.Marshal(nodes...))
+		})
+	}
+}
+
+func TestXmlMergeTags(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Paragraphs in tables, nested tables, content controls and text boxes are merged separately to the paragraphs containing them
+	nodes, err := xmltree.ParseString(
+		`<w:body><w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{ .tag1 </w:t></w:r><w:r><w:t>}}</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+			`<w:tbl><w:tr><w:tc><w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{ .tag4 </w:t></w:r><w:r><w:t>}}</w:t></w:r></w:p></w:tc></w:tr></w:tbl></w:tc></w:tr></w:tbl>` +
+			`<w:sdt><w:sdtContent><w:p><w:r><w:t>{{ .tag5 </w:t></w:r><w:r><w:t>}}</w:t></w:r></w:p></w:sdtContent></w:sdt>` +
+			`<w:p><w:r><w:t>{{ .tag2 </w:t></w:r><w:r><w:pict><v:textbox><w:txbxContent><w:p><w:r><w:t>{{ .tag3 </w:t></w:r><w:r><w:t>}}</w:t></w:r></w:p></w:txbxContent></v:textbox></w:pict></w:r><w:r><w:t>}}</w:t></w:r></w:p></w:body>`,
+	)
+	require.NoError(err)
+
+	mergeXmlTags(nodes, tags.DefaultDelimiters)
+
+	assert.Equal(
+		`<w:body><w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{ .tag1 }}</w:t></w:r><w:r><w:t/></w:r></w:p></w:tc></w:tr></w:tbl>`+
+			`<w:tbl><w:tr><w:tc><w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{ .tag4 }}</w:t></w:r><w:r><w:t/></w:r></w:p></w:tc></w:tr></w:tbl></w:tc></w:tr></w:tbl>`+
+			`<w:sdt><w:sdtContent><w:p><w:r><w:t>{{ .tag5 }}</w:t></w:r><w:r><w:t/></w:r></w:p></w:sdtContent></w:sdt>`+
+			`<w:p><w:r><w:t>{{ .tag2 }}</w:t></w:r><w:r><w:pict><v:textbox><w:txbxContent><w:p><w:r><w:t>{{ .tag3 }}</w:t></w:r><w:r><w:t/></w:r></w:p></w:txbxContent></v:textbox></w:pict></w:r><w:r><w:t/></w:r></w:p></w:body>`,
+		xmltree.Marshal(nodes...),
+	)
+}
+
+func TestXmlAddInlineImage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	t.Run("Should return the XML string for the image", func(t *testing.T) {
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+
+		image, err := images.CreateInlineImage("../../test_templates/test_image.png")
+		require.NoError(err)
+
+		imageXml, err := docx.AddInlineImage(image)
+		assert.NoError(err)
+
+		nodes, err := xmltree.ParseString(imageXml)
+		require.NoError(err)
+		blip := xmltree.FindFirst(nodes, "a:blip")
+		require.NotNil(blip)
+
+		relationshipId, _ := blip.AttrValue("r:embed")
+		relationship := docx.documentRelationships.FindById(relationshipId)
+		require.NotNil(relationship)
+		assert.Equal(relationships.IMAGE_TYPE, relationship.Type)
+		assert.Contains(docx.parts, relationships.ResolveTarget(docx.documentPartName, relationship.Target))
+	})
+
+	t.Run("Should add the PNG content type to the documents content types", func(t *testing.T) {
+		docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+		require.NoError(err)
+
+		image, err := images.CreateInlineImage("../../test_templates/test_image.png")
+		require.NoError(err)
+
+		_, err = docx.AddInlineImage(image)
+		assert.NoError(err)
+		assert.Contains(docx.contentTypes.Defaults, contenttypes.PNG_CONTENT_TYPE)
+	})
+}
+
+func TestXmlSave(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	f, err := os.Create("../../test_templates/generated_xml_test_basic.docx")
+	require.Nil(err, "Error creating document")
+
+	err = docx.Save(f)
+	assert.Nil(err, "Error saving document")
+
+}
+
+func TestXmlSaveAndParse(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	docx, err := NewXmlDocxFromFilename("../../test_templates/test_basic.docx")
+	require.NoError(err)
+
+	var buf bytes.Buffer
+	require.NoError(docx.Save(&buf))
+
+	savedDocx, err := NewXmlDocx(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(err)
+
+	xmlString, err := docx.GetDocumentXml()
+	require.NoError(err)
+	savedXmlString, err := savedDocx.GetDocumentXml()
+	require.NoError(err)
+	assert.Equal(xmlString, savedXmlString)
+	assert.Equal(docx.partNames, savedDocx.partNames)
+}
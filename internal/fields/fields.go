@@ -0,0 +1,284 @@
+package fields
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// A field in a document, either a simple field or a complex field made of field characters, whose
+// result can be replaced. The result is the text Word shows until the field is updated.
+type Field struct {
+	// The field code, e.g. DOCVARIABLE ClientName \* MERGEFORMAT
+	Instruction string
+
+	// Whether the instruction has fields nested in it, in which case it can't be read on its own
+	nested bool
+
+	simple *xmltree.Element
+
+	// The runs of a complex field holding its field characters and the paragraphs they are in
+	beginRun          *xmltree.Element
+	beginParagraph    *xmltree.Element
+	separateRun       *xmltree.Element
+	separateParagraph *xmltree.Element
+	endRun            *xmltree.Element
+	endParagraph      *xmltree.Element
+
+	// The text elements of the result
+	result []*xmltree.Element
+}
+
+// Find the fields in XML in the order they end, so fields nested inside another field come before it.
+func Find(nodes []xmltree.Node) []*Field {
+	found := []*Field{}
+	stack := []*Field{}
+	inResult := map[*Field]bool{}
+
+	for _, paragraph := range xmltree.FindDescendants(nodes, "w:p") {
+		for _, child := range paragraph.Children {
+			element, ok := child.(*xmltree.Element)
+			if !ok {
+				continue
+			}
+			if element.Is("w:fldSimple") {
+				instruction, _ := element.AttrValue("w:instr")
+				found = append(found, &Field{
+					Instruction: strings.TrimSpace(instruction),
+					simple:      element,
+					result:      xmltree.FindDescendants(element.Children, "w:t"),
+				})
+				continue
+			}
+			if !element.Is("w:r") {
+				continue
+			}
+
+			for _, runChild := range element.Elements() {
+				switch {
+				case runChild.Is("w:fldChar"):
+					fieldCharType, _ := runChild.AttrValue("w:fldCharType")
+					switch fieldCharType {
+					case "begin":
+						if len(stack) > 0 && !inResult[stack[len(stack)-1]] {
+							stack[len(stack)-1].nested = true
+						}
+						stack = append(stack, &Field{beginRun: element, beginParagraph: paragraph})
+					case "separate":
+						if len(stack) > 0 {
+							f := stack[len(stack)-1]
+							f.separateRun = element
+							f.separateParagraph = paragraph
+							inResult[f] = true
+						}
+					case "end":
+						if len(stack) == 0 {
+							continue
+						}
+						f := stack[len(stack)-1]
+						f.Instruction = strings.TrimSpace(f.Instruction)
+						f.endRun = element
+						f.endParagraph = paragraph
+						stack = stack[:len(stack)-1]
+						found = append(found, f)
+					}
+				case runChild.Is("w:instrText") && len(stack) > 0 && !inResult[stack[len(stack)-1]]:
+					stack[len(stack)-1].Instruction += runChild.Text()
+				case runChild.Is("w:t") && len(stack) > 0 && inResult[stack[len(stack)-1]]:
+					f := stack[len(stack)-1]
+					f.result = append(f.result, runChild)
+				}
+			}
+		}
+	}
+
+	return found
+}
+
+// The type of the field in upper case followed by its arguments, with quotes removed, e.g.
+// DOCPROPERTY "Client Name" \* MERGEFORMAT gives DOCPROPERTY, Client Name, \*, MERGEFORMAT.
+// Fields with other fields nested in their instruction have no arguments.
+func (f *Field) Arguments() []string {
+	if f.nested {
+		return nil
+	}
+
+	arguments := []string{}
+	var buf strings.Builder
+	quoted, inArgument := false, false
+	for _, r := range f.Instruction {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArgument = true
+		case !quoted && (r == ' ' || r == '\t'):
+			if inArgument {
+				arguments = append(arguments, buf.String())
+				buf.Reset()
+			}
+			inArgument = false
+		default:
+			buf.WriteRune(r)
+			inArgument = true
+		}
+	}
+	if inArgument {
+		arguments = append(arguments, buf.String())
+	}
+	if len(arguments) > 0 {
+		arguments[0] = strings.ToUpper(arguments[0])
+	}
+
+	return arguments
+}
+
+// Replace the result of the field with text, keeping the formatting of the first run of the result.
+func (f *Field) SetResult(text string) {
+	if len(f.result) > 0 {
+		setText(f.result[0], text)
+		for _, t := range f.result[1:] {
+			t.SetText("")
+			t.RemoveAttr("xml:space")
+		}
+		return
+	}
+
+	run := xmltree.NewElement("w:r")
+	t := xmltree.NewElement("w:t")
+	setText(t, text)
+	run.Children = append(run.Children, t)
+	f.result = []*xmltree.Element{t}
+
+	if f.simple != nil {
+		f.simple.Children = append(f.simple.Children, run)
+		return
+	}
+
+	// Fields without a result have it added before the end character, along with a separate
+	// character if it is missing
+	if properties := f.endRun.Find("w:rPr"); properties != nil {
+		run.Children = append([]xmltree.Node{properties.Clone()}, run.Children...)
+	}
+	result := []xmltree.Node{run}
+	if f.separateRun == nil {
+		f.separateRun = newFieldCharRun("separate")
+		f.separateParagraph = f.endParagraph
+		result = append([]xmltree.Node{f.separateRun}, result...)
+	}
+	index := slices.Index(f.endParagraph.Children, xmltree.Node(f.endRun))
+	f.endParagraph.Children = slices.Insert(f.endParagraph.Children, index, result...)
+}
+
+// Mark the field to be updated by Word when the document is opened, e.g. because its result is missing
+// details such as page numbers.
+func (f *Field) SetDirty() {
+	if f.simple != nil {
+		f.simple.SetAttr("w:dirty", "true")
+	} else if fieldChar := f.beginRun.Find("w:fldChar"); fieldChar != nil {
+		fieldChar.SetAttr("w:dirty", "true")
+	}
+}
+
+// Replace the result of a complex field with whole paragraphs, e.g. the entries of a table of contents.
+// The paragraph the field begins in takes the properties of the first paragraph, and the end of the field
+// is moved into a paragraph of its own after the last. Fields which don't begin and end in paragraphs with
+// the same parent, such as fields ending in another table cell, are left as they are.
+func (f *Field) SetResultParagraphs(nodes []xmltree.Node, paragraphs []*xmltree.Element) bool {
+	if f.simple != nil || len(paragraphs) == 0 {
+		return false
+	}
+	if f.separateRun != nil && f.separateParagraph != f.beginParagraph {
+		return false
+	}
+	if f.separateRun == nil && f.endParagraph != f.beginParagraph {
+		return false
+	}
+	parent := findParent(nodes, f.beginParagraph)
+	if parent == nil {
+		return false
+	}
+	beginIndex := slices.Index(parent.Children, xmltree.Node(f.beginParagraph))
+	endIndex := slices.Index(parent.Children, xmltree.Node(f.endParagraph))
+	if endIndex < beginIndex {
+		return false
+	}
+
+	// The field code stays in the paragraph it begins in
+	codeEnd := slices.Index(f.beginParagraph.Children, xmltree.Node(f.endRun))
+	if f.separateRun != nil {
+		codeEnd = slices.Index(f.beginParagraph.Children, xmltree.Node(f.separateRun)) + 1
+	}
+	code := slices.Clone(f.beginParagraph.Children[:codeEnd])
+	if f.separateRun == nil {
+		f.separateRun = newFieldCharRun("separate")
+		f.separateParagraph = f.beginParagraph
+		code = append(code, f.separateRun)
+	}
+
+	end := xmltree.NewElement("w:p")
+	if properties := f.endParagraph.Find("w:pPr"); properties != nil {
+		end.Children = append(end.Children, properties.Clone())
+	}
+	end.Children = append(end.Children, f.endParagraph.Children[slices.Index(f.endParagraph.Children, xmltree.Node(f.endRun)):]...)
+
+	first := paragraphs[0]
+	if properties := first.Find("w:pPr"); properties != nil {
+		code = slices.DeleteFunc(code, func(node xmltree.Node) bool {
+			element, ok := node.(*xmltree.Element)
+			return ok && element.Is("w:pPr")
+		})
+		code = append([]xmltree.Node{properties}, code...)
+	}
+	for _, child := range first.Children {
+		if element, ok := child.(*xmltree.Element); !ok || !element.Is("w:pPr") {
+			code = append(code, child)
+		}
+	}
+	f.beginParagraph.Children = code
+
+	replacement := []xmltree.Node{f.beginParagraph}
+	for _, paragraph := range paragraphs[1:] {
+		replacement = append(replacement, paragraph)
+	}
+	replacement = append(replacement, end)
+	parent.Children = slices.Concat(parent.Children[:beginIndex], replacement, parent.Children[endIndex+1:])
+
+	f.endParagraph = end
+	f.result = xmltree.FindDescendants(replacement[:len(replacement)-1], "w:t")
+
+	return true
+}
+
+// Find the element a node is a child of.
+func findParent(nodes []xmltree.Node, child xmltree.Node) *xmltree.Element {
+	var parent *xmltree.Element
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		element, ok := node.(*xmltree.Element)
+		if !ok || parent != nil {
+			return false
+		}
+		if slices.Contains(element.Children, child) {
+			parent = element
+			return false
+		}
+		return true
+	})
+	return parent
+}
+
+func newFieldCharRun(fieldCharType string) *xmltree.Element {
+	run := xmltree.NewElement("w:r")
+	run.Children = append(run.Children, xmltree.NewElement("w:fldChar", "w:fldCharType", fieldCharType))
+	return run
+}
+
+// Set the text of a w:t element, preserving any spaces at the start or end.
+func setText(t *xmltree.Element, text string) {
+	t.SetText(text)
+	if strings.TrimSpace(text) != text {
+		t.SetAttr("xml:space", "preserve")
+	} else {
+		t.RemoveAttr("xml:space")
+	}
+}
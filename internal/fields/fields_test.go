@@ -0,0 +1,145 @@
+package fields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		instruction       string
+		expectedArguments []string
+	}{
+		{`DOCVARIABLE ClientName`, []string{"DOCVARIABLE", "ClientName"}},
+		{`docproperty  "Client Name" \* MERGEFORMAT`, []string{"DOCPROPERTY", "Client Name", `\*`, "MERGEFORMAT"}},
+		{`DOCVARIABLE ""`, []string{"DOCVARIABLE", ""}},
+		{``, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instruction, func(t *testing.T) {
+			field := &Field{Instruction: tt.instruction}
+			assert.Equal(t, tt.expectedArguments, field.Arguments())
+		})
+	}
+}
+
+func TestSetResult(t *testing.T) {
+	begin := `<w:r><w:fldChar w:fldCharType="begin"/></w:r>`
+	separate := `<w:r><w:fldChar w:fldCharType="separate"/></w:r>`
+	end := `<w:r><w:rPr><w:b/></w:rPr><w:fldChar w:fldCharType="end"/></w:r>`
+	instruction := func(text string) string {
+		return `<w:r><w:instrText xml:space="preserve">` + text + `</w:instrText></w:r>`
+	}
+
+	tests := []struct {
+		name                 string
+		inputXml             string
+		expectedInstructions []string
+		expectedOutputXml    string
+	}{
+		{
+			name:                 "Simple field",
+			inputXml:             `<w:p><w:fldSimple w:instr=" DOCVARIABLE Client "><w:r><w:t>Old</w:t></w:r></w:fldSimple></w:p>`,
+			expectedInstructions: []string{"DOCVARIABLE Client"},
+			expectedOutputXml:    `<w:p><w:fldSimple w:instr=" DOCVARIABLE Client "><w:r><w:t>Value</w:t></w:r></w:fldSimple></w:p>`,
+		},
+		{
+			name:                 "Simple field without a result",
+			inputXml:             `<w:p><w:fldSimple w:instr="DOCVARIABLE Client"/></w:p>`,
+			expectedInstructions: []string{"DOCVARIABLE Client"},
+			expectedOutputXml:    `<w:p><w:fldSimple w:instr="DOCVARIABLE Client"><w:r><w:t>Value</w:t></w:r></w:fldSimple></w:p>`,
+		},
+		{
+			name:                 "Complex field with a result in several runs",
+			inputXml:             `<w:p>` + begin + instruction(" DOCVARIABLE ") + instruction("Client ") + separate + `<w:r><w:t xml:space="preserve">Old </w:t></w:r><w:r><w:t>value</w:t></w:r>` + end + `</w:p>`,
+			expectedInstructions: []string{"DOCVARIABLE Client"},
+			expectedOutputXml:    `<w:p>` + begin + instruction(" DOCVARIABLE ") + instruction("Client ") + separate + `<w:r><w:t>Value</w:t></w:r><w:r><w:t/></w:r>` + end + `</w:p>`,
+		},
+		{
+			name:                 "Complex field without a result",
+			inputXml:             `<w:p>` + begin + instruction("DOCVARIABLE Client") + end + `</w:p>`,
+			expectedInstructions: []string{"DOCVARIABLE Client"},
+			expectedOutputXml:    `<w:p>` + begin + instruction("DOCVARIABLE Client") + separate + `<w:r><w:rPr><w:b/></w:rPr><w:t>Value</w:t></w:r>` + end + `</w:p>`,
+		},
+		{
+			name:                 "Nested field",
+			inputXml:             `<w:p>` + begin + instruction("DOCVARIABLE ") + begin + instruction("MERGEFIELD Name") + end + end + `</w:p>`,
+			expectedInstructions: []string{"MERGEFIELD Name", "DOCVARIABLE"},
+			expectedOutputXml:    `<w:p>` + begin + instruction("DOCVARIABLE ") + begin + instruction("MERGEFIELD Name") + separate + `<w:r><w:rPr><w:b/></w:rPr><w:t>Value</w:t></w:r>` + end + end + `</w:p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := xmltree.ParseString(tt.inputXml)
+			require.NoError(t, err)
+
+			found := Find(nodes)
+			instructions := []string{}
+			for _, field := range found {
+				instructions = append(instructions, field.Instruction)
+			}
+			assert.Equal(t, tt.expectedInstructions, instructions)
+
+			found[0].SetResult("Value")
+			assert.Equal(t, tt.expectedOutputXml, xmltree.Marshal(nodes...))
+		})
+	}
+}
+
+func TestSetResultParagraphs(t *testing.T) {
+	begin := `<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText> TOC \o "1-3" </w:instrText></w:r>`
+	separate := `<w:r><w:fldChar w:fldCharType="separate"/></w:r>`
+	end := `<w:r><w:fldChar w:fldCharType="end"/></w:r>`
+	paragraphProperties := `<w:pPr><w:jc w:val="center"/></w:pPr>`
+	entries := `<w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr><w:r><w:t>Introduction</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:pStyle w:val="TOC2"/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>`
+
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:     "Field in one paragraph",
+			inputXml: `<w:body><w:p>` + paragraphProperties + begin + separate + end + `<w:r><w:t>After</w:t></w:r></w:p></w:body>`,
+			expectedOutputXml: `<w:body><w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr>` + begin + separate + `<w:r><w:t>Introduction</w:t></w:r></w:p>` +
+				`<w:p><w:pPr><w:pStyle w:val="TOC2"/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>` +
+				`<w:p>` + paragraphProperties + end + `<w:r><w:t>After</w:t></w:r></w:p></w:body>`,
+		},
+		{
+			name:     "Field without a separate character",
+			inputXml: `<w:body><w:p>` + begin + end + `</w:p></w:body>`,
+			expectedOutputXml: `<w:body><w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr>` + begin + separate + `<w:r><w:t>Introduction</w:t></w:r></w:p>` +
+				`<w:p><w:pPr><w:pStyle w:val="TOC2"/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>` +
+				`<w:p>` + end + `</w:p></w:body>`,
+		},
+		{
+			name: "Result replaced across paragraphs",
+			inputXml: `<w:body><w:p><w:r><w:t>Before</w:t></w:r></w:p><w:p>` + begin + separate + `<w:r><w:t>Old</w:t></w:r></w:p>` +
+				`<w:p><w:r><w:t>Old entry</w:t></w:r></w:p><w:p>` + paragraphProperties + end + `</w:p></w:body>`,
+			expectedOutputXml: `<w:body><w:p><w:r><w:t>Before</w:t></w:r></w:p><w:p><w:pPr><w:pStyle w:val="TOC1"/></w:pPr>` + begin + separate + `<w:r><w:t>Introduction</w:t></w:r></w:p>` +
+				`<w:p><w:pPr><w:pStyle w:val="TOC2"/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>` +
+				`<w:p>` + paragraphProperties + end + `</w:p></w:body>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := xmltree.ParseString(tt.inputXml)
+			require.NoError(t, err)
+			paragraphs, err := xmltree.ParseString(entries)
+			require.NoError(t, err)
+
+			found := Find(nodes)
+			require.Len(t, found, 1)
+			ok := found[0].SetResultParagraphs(nodes, []*xmltree.Element{paragraphs[0].(*xmltree.Element), paragraphs[1].(*xmltree.Element)})
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedOutputXml, xmltree.Marshal(nodes...))
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package functions
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Functions for filtering, sorting and grouping lists of rows, e.g. for tables with subtotals.
+// Rows can be maps or structs, and fields can be nested using dots (e.g. "Customer.Name").
+// The list is always the last argument so the functions can be used in pipelines.
+//
+//	{{ range groupBy "Category" .Rows }}
+//		{{ .Key }}: {{ sumBy "Amount" .Items }}
+//	{{ end }}
+var aggregationFunctions = map[string]any{
+	"where":      where,
+	"sortBy":     sortBy,
+	"sortByDesc": sortByDesc,
+	"groupBy":    groupBy,
+	"sumBy":      sumBy,
+	"avgBy":      avgBy,
+	"countBy":    countBy,
+	"chunk":      chunk,
+}
+
+// A group of rows sharing the same value for a field.
+type Group struct {
+	Key   any
+	Items []any
+}
+
+// Get the value of a field from a map or struct. Nested fields are separated by dots.
+func fieldValue(item any, path string) (any, error) {
+	value := reflect.ValueOf(item)
+	for _, name := range strings.Split(path, ".") {
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return nil, nil
+			}
+			value = value.Elem()
+		}
+
+		switch value.Kind() {
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("cannot get field %q from a map without string keys", name)
+			}
+			value = value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+			if !value.IsValid() {
+				return nil, nil
+			}
+		case reflect.Struct:
+			field, ok := value.Type().FieldByName(name)
+			if !ok || !field.IsExported() {
+				return nil, fmt.Errorf("%s has no field %q", value.Type(), name)
+			}
+			value = value.FieldByIndex(field.Index)
+		default:
+			return nil, fmt.Errorf("cannot get field %q from %s", name, value.Kind())
+		}
+	}
+
+	if !value.IsValid() {
+		return nil, nil
+	}
+	return value.Interface(), nil
+}
+
+// Whether two field values are equal. Numbers are compared by value regardless of their type.
+func valuesEqual(a any, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	if isNumber(a) && isNumber(b) {
+		af, _ := toFloat64(a)
+		bf, _ := toFloat64(b)
+		return af == bf
+	}
+	return false
+}
+
+func isNumber(value any) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Compare two field values, returning a negative number if a is less than b, zero if they are
+// equal and a positive number if a is greater than b. Nil values are sorted first.
+func compareValues(a any, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if isNumber(a) && isNumber(b) {
+		af, _ := toFloat64(a)
+		bf, _ := toFloat64(b)
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			return at.Compare(bt)
+		}
+	}
+
+	return strings.Compare(toString(a), toString(b))
+}
+
+// Keep the rows where a field equals a value.
+//
+//	{{ range .Invoices | where "Status" "Paid" }}
+func where(field string, value any, rows any) ([]any, error) {
+	result := []any{}
+	for _, row := range toList(rows) {
+		rowValue, err := fieldValue(row, field)
+		if err != nil {
+			return nil, err
+		}
+		if valuesEqual(rowValue, value) {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
+
+// Sort rows by a field in ascending order. The sort is stable so rows with equal values keep their order.
+//
+//	{{ range sortBy "Name" .People }}
+func sortBy(field string, rows any) ([]any, error) {
+	return sortRows(field, rows, false)
+}
+
+// Sort rows by a field in descending order.
+func sortByDesc(field string, rows any) ([]any, error) {
+	return sortRows(field, rows, true)
+}
+
+func sortRows(field string, rows any, descending bool) ([]any, error) {
+	list := toList(rows)
+	sortValues := make([]any, len(list))
+	for i, row := range list {
+		value, err := fieldValue(row, field)
+		if err != nil {
+			return nil, err
+		}
+		sortValues[i] = value
+	}
+
+	indices := make([]int, len(list))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		comparison := compareValues(sortValues[indices[i]], sortValues[indices[j]])
+		if descending {
+			return comparison > 0
+		}
+		return comparison < 0
+	})
+
+	result := make([]any, len(list))
+	for i, index := range indices {
+		result[i] = list[index]
+	}
+	return result, nil
+}
+
+// Group rows by a field. Groups are returned in the order their key first appears.
+//
+//	{{ range groupBy "Category" .Rows }}{{ .Key }} ({{ len .Items }}){{ end }}
+func groupBy(field string, rows any) ([]*Group, error) {
+	groups := []*Group{}
+	for _, row := range toList(rows) {
+		key, err := fieldValue(row, field)
+		if err != nil {
+			return nil, err
+		}
+
+		var group *Group
+		for _, existingGroup := range groups {
+			if valuesEqual(existingGroup.Key, key) {
+				group = existingGroup
+				break
+			}
+		}
+		if group == nil {
+			group = &Group{Key: key}
+			groups = append(groups, group)
+		}
+		group.Items = append(group.Items, row)
+	}
+	return groups, nil
+}
+
+func fieldValues(field string, rows any) ([]any, error) {
+	list := toList(rows)
+	values := make([]any, 0, len(list))
+	for _, row := range list {
+		value, err := fieldValue(row, field)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// Add up a field across rows. The result is an int64 unless any of the values are floats.
+//
+//	{{ sumBy "Amount" .Items }}
+func sumBy(field string, rows any) (any, error) {
+	values, err := fieldValues(field, rows)
+	if err != nil {
+		return nil, err
+	}
+	return sum(values)
+}
+
+// The average of a field across rows. Rows where the field is missing are ignored.
+func avgBy(field string, rows any) (float64, error) {
+	values, err := fieldValues(field, rows)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	var total float64
+	for _, value := range values {
+		f, err := toFloat64(value)
+		if err != nil {
+			return 0, err
+		}
+		total += f
+	}
+	return total / float64(len(values)), nil
+}
+
+// Count the rows for each value of a field.
+//
+//	{{ range $status, $count := countBy "Status" .Invoices }}{{ $status }}: {{ $count }}{{ end }}
+func countBy(field string, rows any) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, row := range toList(rows) {
+		value, err := fieldValue(row, field)
+		if err != nil {
+			return nil, err
+		}
+		counts[toString(value)]++
+	}
+	return counts, nil
+}
+
+// Split a list into chunks of a size. The argument order matches Sprig.
+//
+//	{{ range chunk 3 .Photos }}
+func chunk(size int, rows any) ([][]any, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("chunk size must be at least 1, got %d", size)
+	}
+
+	list := toList(rows)
+	chunks := [][]any{}
+	for start := 0; start < len(list); start += size {
+		chunks = append(chunks, list[start:min(start+size, len(list))])
+	}
+	return chunks, nil
+}
@@ -0,0 +1,179 @@
+package functions
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testInvoice struct {
+	Number   string
+	Customer struct{ Name string }
+	Status   string
+	Amount   float64
+}
+
+func TestFieldValue(t *testing.T) {
+	invoice := testInvoice{Number: "INV-001", Status: "Paid", Amount: 10}
+	invoice.Customer.Name = "TW Software"
+
+	tests := []struct {
+		name           string
+		item           any
+		field          string
+		expectedResult any
+		expectError    bool
+	}{
+		{name: "Map", item: map[string]any{"Status": "Paid"}, field: "Status", expectedResult: "Paid"},
+		{name: "Typed map", item: map[string]int{"Count": 2}, field: "Count", expectedResult: 2},
+		{name: "Missing map key", item: map[string]any{}, field: "Status", expectedResult: nil},
+		{name: "Struct", item: invoice, field: "Amount", expectedResult: 10.0},
+		{name: "Pointer to a struct", item: &invoice, field: "Number", expectedResult: "INV-001"},
+		{name: "Nested struct", item: invoice, field: "Customer.Name", expectedResult: "TW Software"},
+		{
+			name:           "Nested map",
+			item:           map[string]any{"Customer": map[string]any{"Name": "TW Software"}},
+			field:          "Customer.Name",
+			expectedResult: "TW Software",
+		},
+		{name: "Missing struct field", item: invoice, field: "Total", expectError: true},
+		{name: "Unsupported type", item: "text", field: "Length", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := fieldValue(tt.item, tt.field)
+			assert.Equal(tt.expectError, err != nil)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+}
+
+func TestAggregationFunctions(t *testing.T) {
+	rows := []map[string]any{
+		{"Category": "Hardware", "Item": "Laptop", "Amount": 1200.0, "Quantity": 1},
+		{"Category": "Software", "Item": "Licence", "Amount": 300.0, "Quantity": 5},
+		{"Category": "Hardware", "Item": "Monitor", "Amount": 250.5, "Quantity": 2},
+		{"Category": "Services", "Item": "Support", "Amount": 99.5, "Quantity": 1},
+	}
+	invoices := []testInvoice{
+		{Number: "INV-001", Status: "Paid", Amount: 10},
+		{Number: "INV-002", Status: "Due", Amount: 20},
+		{Number: "INV-003", Status: "Paid", Amount: 30},
+	}
+
+	tests := []struct {
+		name           string
+		template       string
+		data           any
+		expectedResult string
+		expectError    bool
+	}{
+		{
+			name:           "where",
+			template:       `{{ range where "Category" "Hardware" . }}{{ .Item }},{{ end }}`,
+			data:           rows,
+			expectedResult: "Laptop,Monitor,",
+		},
+		{
+			name:           "where with a struct slice",
+			template:       `{{ range . | where "Status" "Paid" }}{{ .Number }},{{ end }}`,
+			data:           invoices,
+			expectedResult: "INV-001,INV-003,",
+		},
+		{
+			name:           "where compares numbers by value",
+			template:       `{{ range where "Quantity" 1.0 . }}{{ .Item }},{{ end }}`,
+			data:           rows,
+			expectedResult: "Laptop,Support,",
+		},
+		{
+			name:           "sortBy",
+			template:       `{{ range sortBy "Amount" . }}{{ .Item }},{{ end }}`,
+			data:           rows,
+			expectedResult: "Support,Monitor,Licence,Laptop,",
+		},
+		{
+			name:           "sortBy is stable",
+			template:       `{{ range sortBy "Category" . }}{{ .Item }},{{ end }}`,
+			data:           rows,
+			expectedResult: "Laptop,Monitor,Support,Licence,",
+		},
+		{
+			name:           "sortByDesc",
+			template:       `{{ range sortByDesc "Amount" . }}{{ .Number }},{{ end }}`,
+			data:           invoices,
+			expectedResult: "INV-003,INV-002,INV-001,",
+		},
+		{
+			name:           "groupBy with subtotals",
+			template:       `{{ range groupBy "Category" . }}{{ .Key }}: {{ len .Items }} {{ sumBy "Amount" .Items }};{{ end }}`,
+			data:           rows,
+			expectedResult: "Hardware: 2 1450.5;Software: 1 300;Services: 1 99.5;",
+		},
+		{
+			name:           "sumBy with whole numbers",
+			template:       `{{ sumBy "Quantity" . }}`,
+			data:           rows,
+			expectedResult: "9",
+		},
+		{
+			name:           "avgBy",
+			template:       `{{ avgBy "Amount" . }}`,
+			data:           invoices,
+			expectedResult: "20",
+		},
+		{
+			name:           "avgBy with no rows",
+			template:       `{{ avgBy "Amount" . }}`,
+			data:           []testInvoice{},
+			expectedResult: "0",
+		},
+		{
+			name:           "countBy",
+			template:       `{{ range $status, $count := countBy "Status" . }}{{ $status }}={{ $count }};{{ end }}`,
+			data:           invoices,
+			expectedResult: "Due=1;Paid=2;",
+		},
+		{
+			name:           "chunk",
+			template:       `{{ range chunk 3 . }}[{{ range . }}{{ . }}{{ end }}]{{ end }}`,
+			data:           []int{1, 2, 3, 4, 5, 6, 7},
+			expectedResult: "[123][456][7]",
+		},
+		{
+			name:        "chunk with an invalid size",
+			template:    `{{ chunk 0 . }}`,
+			data:        []int{1, 2},
+			expectError: true,
+		},
+		{
+			name:        "Unknown field",
+			template:    `{{ sumBy "Total" . }}`,
+			data:        invoices,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			tmpl, err := template.New("").Funcs(DefaultFuncMap).Parse(tt.template)
+			require.NoError(err)
+
+			buf := &bytes.Buffer{}
+			err = tmpl.Execute(buf, tt.data)
+			assert.Equal(tt.expectError, err != nil, "unexpected error state: %v", err)
+			if !tt.expectError {
+				assert.Equal(tt.expectedResult, buf.String())
+			}
+		})
+	}
+}
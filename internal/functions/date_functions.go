@@ -0,0 +1,374 @@
+package functions
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Date functions. Month and day names default to the given locale unless one is passed to the function.
+func newDateFunctions(defaultLocale language.Tag) template.FuncMap {
+	return template.FuncMap{
+		"date": date,
+		"dateFormat": func(layout string, value any, locale ...string) (string, error) {
+			return dateFormat(defaultLocale, layout, value, locale...)
+		},
+		"now":        time.Now,
+		"inTimezone": inTimezone,
+		"addDays":    addDays,
+		"addMonths":  addMonths,
+		"monthName": func(value any, locale ...string) (string, error) {
+			return monthName(defaultLocale, value, locale...)
+		},
+		"dayName": func(value any, locale ...string) (string, error) {
+			return dayName(defaultLocale, value, locale...)
+		},
+		"duration":       duration,
+		"durationFormat": durationFormat,
+	}
+}
+
+// Layouts tried in order when converting a string into a time.
+var isoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Convert a value into a time. Accepts time.Time values, unix timestamps (in seconds) and ISO 8601 strings.
+func toTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, fmt.Errorf("cannot convert nil to a time")
+		}
+		return *v, nil
+	case string:
+		for _, layout := range isoLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as a date", v)
+	}
+
+	reflectVal := reflect.ValueOf(value)
+	switch reflectVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Unix(reflectVal.Int(), 0), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Unix(int64(reflectVal.Uint()), 0), nil
+	case reflect.Float32, reflect.Float64:
+		sec, frac := math.Modf(reflectVal.Float())
+		return time.Unix(int64(sec), int64(frac*1e9)), nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot convert %T to a time", value)
+}
+
+// Convert a value into a time outside of a template, e.g. when filling in a date picker content control.
+func ToTime(value any) (time.Time, error) {
+	return toTime(value)
+}
+
+// Format a date using a Go layout. The argument order matches Sprig so it can be used in pipelines.
+//
+//	{{ .Date | date "02/01/2006" }}
+func date(layout string, value any) (string, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return "", err
+	}
+
+	return t.Format(layout), nil
+}
+
+// Format a date using either a Go layout or a strftime style layout (e.g. "%d %B %Y").
+// Month and day names are translated when a locale is passed.
+//
+//	{{ dateFormat "%A %d %B %Y" .Date "de" }}
+func dateFormat(defaultLocale language.Tag, layout string, value any, locale ...string) (string, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return "", err
+	}
+
+	names, err := dateNamesForLocale(defaultLocale, locale...)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.Contains(layout, "%") {
+		return formatStrftime(t, layout, names), nil
+	}
+
+	return formatGoLayout(t, layout, names), nil
+}
+
+// Convert a date into the given IANA time zone (e.g. "Europe/London").
+func inTimezone(name string, value any) (time.Time, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.In(loc), nil
+}
+
+func addDays(days int, value any) (time.Time, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.AddDate(0, 0, days), nil
+}
+
+// Add calendar months to a date. Unlike time.AddDate, the day is clamped to the end of
+// the month so 31st January plus one month is the 29th or 28th of February.
+func addMonths(months int, value any) (time.Time, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	target := firstOfMonth.AddDate(0, months, 0)
+	lastDay := target.AddDate(0, 1, -1).Day()
+
+	return target.AddDate(0, 0, min(t.Day(), lastDay)-1), nil
+}
+
+func monthName(defaultLocale language.Tag, value any, locale ...string) (string, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return "", err
+	}
+
+	names, err := dateNamesForLocale(defaultLocale, locale...)
+	if err != nil {
+		return "", err
+	}
+
+	return names.months[t.Month()-1], nil
+}
+
+func dayName(defaultLocale language.Tag, value any, locale ...string) (string, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return "", err
+	}
+
+	names, err := dateNamesForLocale(defaultLocale, locale...)
+	if err != nil {
+		return "", err
+	}
+
+	return names.days[t.Weekday()], nil
+}
+
+func formatStrftime(t time.Time, layout string, names *dateNames) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' || i == len(layout)-1 {
+			sb.WriteByte(layout[i])
+			continue
+		}
+
+		i++
+		switch layout[i] {
+		case 'a':
+			sb.WriteString(names.shortDays[t.Weekday()])
+		case 'A':
+			sb.WriteString(names.days[t.Weekday()])
+		case 'b', 'h':
+			sb.WriteString(names.shortMonths[t.Month()-1])
+		case 'B':
+			sb.WriteString(names.months[t.Month()-1])
+		case 'd':
+			sb.WriteString(t.Format("02"))
+		case 'e':
+			sb.WriteString(t.Format("_2"))
+		case 'H':
+			sb.WriteString(t.Format("15"))
+		case 'I':
+			sb.WriteString(t.Format("03"))
+		case 'j':
+			sb.WriteString(t.Format("002"))
+		case 'm':
+			sb.WriteString(t.Format("01"))
+		case 'M':
+			sb.WriteString(t.Format("04"))
+		case 'p':
+			sb.WriteString(t.Format("PM"))
+		case 'S':
+			sb.WriteString(t.Format("05"))
+		case 'f':
+			sb.WriteString(fmt.Sprintf("%06d", t.Nanosecond()/1000))
+		case 'y':
+			sb.WriteString(t.Format("06"))
+		case 'Y':
+			sb.WriteString(t.Format("2006"))
+		case 'z':
+			sb.WriteString(t.Format("-0700"))
+		case 'Z':
+			sb.WriteString(t.Format("MST"))
+		case 'F':
+			sb.WriteString(t.Format("2006-01-02"))
+		case 'T':
+			sb.WriteString(t.Format("15:04:05"))
+		case 'D':
+			sb.WriteString(t.Format("01/02/06"))
+		case 'R':
+			sb.WriteString(t.Format("15:04"))
+		case 'u':
+			sb.WriteString(strconv.Itoa((int(t.Weekday())+6)%7 + 1))
+		case 'w':
+			sb.WriteString(strconv.Itoa(int(t.Weekday())))
+		case '%':
+			sb.WriteByte('%')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(layout[i])
+		}
+	}
+
+	return sb.String()
+}
+
+// Go layout tokens which produce names, longest first so "January" is matched before "Jan".
+var goLayoutNameTokens = []string{"January", "Jan", "Monday", "Mon"}
+
+// Format a time using a Go layout, substituting month and day names with those for the locale.
+func formatGoLayout(t time.Time, layout string, names *dateNames) string {
+	var sb strings.Builder
+
+	for len(layout) > 0 {
+		index, token := -1, ""
+		for _, nameToken := range goLayoutNameTokens {
+			if i := strings.Index(layout, nameToken); i != -1 && (index == -1 || i < index) {
+				index, token = i, nameToken
+			}
+		}
+		if index == -1 {
+			sb.WriteString(t.Format(layout))
+			break
+		}
+
+		sb.WriteString(t.Format(layout[:index]))
+		switch token {
+		case "January":
+			sb.WriteString(names.months[t.Month()-1])
+		case "Jan":
+			sb.WriteString(names.shortMonths[t.Month()-1])
+		case "Monday":
+			sb.WriteString(names.days[t.Weekday()])
+		case "Mon":
+			sb.WriteString(names.shortDays[t.Weekday()])
+		}
+		layout = layout[index+len(token):]
+	}
+
+	return sb.String()
+}
+
+// Convert a value into a duration. Accepts time.Duration values, numbers (in seconds)
+// and strings such as "1h30m".
+func duration(value any) (time.Duration, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		return time.ParseDuration(v)
+	}
+
+	reflectVal := reflect.ValueOf(value)
+	switch reflectVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(reflectVal.Int()) * time.Second, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(reflectVal.Uint()) * time.Second, nil
+	case reflect.Float32, reflect.Float64:
+		return time.Duration(reflectVal.Float() * float64(time.Second)), nil
+	}
+
+	return 0, fmt.Errorf("cannot convert %T to a duration", value)
+}
+
+// Format a duration using a layout made up of the following verbs:
+//
+//	%d days
+//	%h hours, %H zero padded hours
+//	%m minutes, %M zero padded minutes
+//	%s seconds, %S zero padded seconds
+//
+// Hours are only wrapped at 24 when the layout also contains days, so "%h:%M" of 26 hours gives "26:00".
+func durationFormat(layout string, value any) (string, error) {
+	d, err := duration(value)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if d < 0 {
+		sb.WriteByte('-')
+		d = -d
+	}
+
+	totalSeconds := int64(d / time.Second)
+	days := totalSeconds / 86400
+	hours := totalSeconds / 3600
+	if strings.Contains(layout, "%d") {
+		hours %= 24
+	}
+	minutes := totalSeconds / 60 % 60
+	seconds := totalSeconds % 60
+
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' || i == len(layout)-1 {
+			sb.WriteByte(layout[i])
+			continue
+		}
+
+		i++
+		switch layout[i] {
+		case 'd':
+			sb.WriteString(strconv.FormatInt(days, 10))
+		case 'h':
+			sb.WriteString(strconv.FormatInt(hours, 10))
+		case 'H':
+			sb.WriteString(fmt.Sprintf("%02d", hours))
+		case 'm':
+			sb.WriteString(strconv.FormatInt(minutes, 10))
+		case 'M':
+			sb.WriteString(fmt.Sprintf("%02d", minutes))
+		case 's':
+			sb.WriteString(strconv.FormatInt(seconds, 10))
+		case 'S':
+			sb.WriteString(fmt.Sprintf("%02d", seconds))
+		case '%':
+			sb.WriteByte('%')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(layout[i])
+		}
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,271 @@
+package functions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+var testDate = time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+
+func TestToTime(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        any
+		expectedTime time.Time
+		expectError  bool
+	}{
+		{
+			name:         "Time",
+			value:        testDate,
+			expectedTime: testDate,
+		},
+		{
+			name:         "Pointer to a time",
+			value:        &testDate,
+			expectedTime: testDate,
+		},
+		{
+			name:         "Unix timestamp",
+			value:        testDate.Unix(),
+			expectedTime: testDate,
+		},
+		{
+			name:         "Unix timestamp as a float",
+			value:        float64(testDate.Unix()) + 0.5,
+			expectedTime: testDate.Add(500 * time.Millisecond),
+		},
+		{
+			name:         "RFC 3339 string",
+			value:        "2024-03-05T14:07:09Z",
+			expectedTime: testDate,
+		},
+		{
+			name:         "ISO date string",
+			value:        "2024-03-05",
+			expectedTime: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "Invalid string",
+			value:       "not a date",
+			expectError: true,
+		},
+		{
+			name:        "Unsupported type",
+			value:       []string{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := toTime(tt.value)
+			assert.Equal(tt.expectError, err != nil)
+			if !tt.expectError {
+				assert.True(tt.expectedTime.Equal(result), "expected %v, got %v", tt.expectedTime, result)
+			}
+		})
+	}
+}
+
+func TestDate(t *testing.T) {
+	assert := assert.New(t)
+
+	result, err := date("02/01/2006 15:04", "2024-03-05T14:07:09Z")
+	assert.NoError(err)
+	assert.Equal("05/03/2024 14:07", result)
+}
+
+func TestDateFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		layout         string
+		locale         []string
+		expectedResult string
+	}{
+		{
+			name:           "Go layout",
+			layout:         "Monday 2 January 2006",
+			expectedResult: "Tuesday 5 March 2024",
+		},
+		{
+			name:           "Go layout with locale",
+			layout:         "Monday 2 January 2006",
+			locale:         []string{"de"},
+			expectedResult: "Dienstag 5 März 2024",
+		},
+		{
+			name:           "Go layout with short names and locale",
+			layout:         "Mon 2 Jan 2006",
+			locale:         []string{"fr-FR"},
+			expectedResult: "mar. 5 mars 2024",
+		},
+		{
+			name:           "Strftime layout",
+			layout:         "%d/%m/%Y %H:%M:%S",
+			expectedResult: "05/03/2024 14:07:09",
+		},
+		{
+			name:           "Strftime layout with names",
+			layout:         "%A %e %B %Y",
+			expectedResult: "Tuesday  5 March 2024",
+		},
+		{
+			name:           "Strftime layout with locale",
+			layout:         "%a %d %b %Y",
+			locale:         []string{"es"},
+			expectedResult: "mar 05 mar 2024",
+		},
+		{
+			name:           "Strftime literal percent",
+			layout:         "100%% on %F",
+			expectedResult: "100% on 2024-03-05",
+		},
+		{
+			name:           "Unknown locale falls back to English",
+			layout:         "%B",
+			locale:         []string{"ja"},
+			expectedResult: "March",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := dateFormat(language.English, tt.layout, testDate, tt.locale...)
+			assert.NoError(err)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+
+	t.Run("Invalid locale should return an error", func(t *testing.T) {
+		_, err := dateFormat(language.English, "%B", testDate, "not a locale!")
+		assert.Error(t, err)
+	})
+}
+
+func TestInTimezone(t *testing.T) {
+	assert := assert.New(t)
+
+	result, err := inTimezone("America/New_York", testDate)
+	assert.NoError(err)
+	assert.Equal("2024-03-05 09:07", result.Format("2006-01-02 15:04"))
+
+	_, err = inTimezone("Not/A_Zone", testDate)
+	assert.Error(err)
+}
+
+func TestAddDays(t *testing.T) {
+	assert := assert.New(t)
+
+	result, err := addDays(30, testDate)
+	assert.NoError(err)
+	assert.Equal("2024-04-04", result.Format("2006-01-02"))
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		name           string
+		date           string
+		months         int
+		expectedResult string
+	}{
+		{
+			name:           "Add a month",
+			date:           "2024-03-05",
+			months:         1,
+			expectedResult: "2024-04-05",
+		},
+		{
+			name:           "End of month is clamped",
+			date:           "2024-01-31",
+			months:         1,
+			expectedResult: "2024-02-29",
+		},
+		{
+			name:           "Subtract months across a year",
+			date:           "2024-03-31",
+			months:         -4,
+			expectedResult: "2023-11-30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := addMonths(tt.months, tt.date)
+			assert.NoError(err)
+			assert.Equal(tt.expectedResult, result.Format("2006-01-02"))
+		})
+	}
+}
+
+func TestMonthAndDayNames(t *testing.T) {
+	assert := assert.New(t)
+
+	month, err := monthName(language.English, testDate)
+	assert.NoError(err)
+	assert.Equal("March", month)
+
+	month, err = monthName(language.English, testDate, "it")
+	assert.NoError(err)
+	assert.Equal("marzo", month)
+
+	month, err = monthName(language.German, testDate)
+	assert.NoError(err)
+	assert.Equal("März", month)
+
+	day, err := dayName(language.English, testDate, "nl")
+	assert.NoError(err)
+	assert.Equal("dinsdag", day)
+}
+
+func TestDurationFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		layout         string
+		value          any
+		expectedResult string
+	}{
+		{
+			name:           "Duration value",
+			layout:         "%H:%M:%S",
+			value:          90*time.Minute + 5*time.Second,
+			expectedResult: "01:30:05",
+		},
+		{
+			name:           "Seconds",
+			layout:         "%h:%M",
+			value:          26 * 3600,
+			expectedResult: "26:00",
+		},
+		{
+			name:           "Hours wrap when days are shown",
+			layout:         "%d days %h hours",
+			value:          "26h",
+			expectedResult: "1 days 2 hours",
+		},
+		{
+			name:           "Negative duration",
+			layout:         "%m minutes",
+			value:          -5 * time.Minute,
+			expectedResult: "-5 minutes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := durationFormat(tt.layout, tt.value)
+			assert.NoError(err)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package functions
 
 import (
+	"maps"
 	"strings"
 	"text/template"
 
@@ -8,13 +9,27 @@ import (
 	"golang.org/x/text/language"
 )
 
-var DefaultFuncMap = template.FuncMap{
-	"upper": strings.ToUpper,
-	"lower": strings.ToLower,
-	"title": title,
+var DefaultFuncMap = NewFuncMap(language.English)
+
+// Create the built-in functions for a locale. The locale is used for title casing,
+// number formatting, spelling numbers and month and day names.
+func NewFuncMap(locale language.Tag) template.FuncMap {
+	funcMap := template.FuncMap{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"title": func(text string) string {
+			return title(locale, text)
+		},
+	}
+	maps.Copy(funcMap, newDateFunctions(locale))
+	maps.Copy(funcMap, newNumberFunctions(locale))
+	maps.Copy(funcMap, newSpellingFunctions(locale))
+	maps.Copy(funcMap, aggregationFunctions)
+
+	return funcMap
 }
 
-func title(text string) string {
-	caser := cases.Title(language.English)
+func title(locale language.Tag, text string) string {
+	caser := cases.Title(locale)
 	return caser.String(text)
 }
@@ -1,10 +1,21 @@
 package functions
 
-import "testing"
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
 
 func TestTitle(t *testing.T) {
-	titleCase := title("tom watkins")
+	titleCase := title(language.English, "tom watkins")
 	if titleCase != "Tom Watkins" {
 		t.Fatalf("Should return in title case but returned: %v", titleCase)
 	}
 }
+
+func TestTitleWithLocale(t *testing.T) {
+	titleCase := title(language.Dutch, "ijsselmeer")
+	if titleCase != "IJsselmeer" {
+		t.Fatalf("Should return in Dutch title case but returned: %v", titleCase)
+	}
+}
@@ -0,0 +1,263 @@
+package functions
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// Number functions. Grouping, decimal separators and currency positions follow the given locale.
+func newNumberFunctions(locale language.Tag) template.FuncMap {
+	formatter := newNumberFormatter(locale)
+
+	return template.FuncMap{
+		"formatNumber":      formatter.formatNumber,
+		"formatPercent":     formatter.formatPercent,
+		"formatCurrency":    formatter.formatCurrency,
+		"formatCurrencyISO": formatter.formatCurrencyISO,
+		"round":             round,
+	}
+}
+
+// Convert a value into a float64. Accepts any numeric type and numeric strings.
+func toFloat64(value any) (float64, error) {
+	if s, ok := value.(string); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot convert %q to a number", s)
+		}
+		return f, nil
+	}
+
+	reflectVal := reflect.ValueOf(value)
+	switch reflectVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflectVal.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflectVal.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return reflectVal.Float(), nil
+	}
+
+	return 0, fmt.Errorf("cannot convert %T to a number", value)
+}
+
+// Round a number to the given number of decimal places, rounding half away from zero.
+//
+//	{{ round .Total 2 }}
+func round(value any, places int) (float64, error) {
+	f, err := toFloat64(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return roundDecimal(f, places), nil
+}
+
+// Round a number using the shortest decimal form of it, so 1.005 rounds to 1.01 as it does on paper
+// rather than to 1 as its binary value, 1.00499..., would.
+func roundDecimal(f float64, places int) float64 {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return f
+	}
+
+	whole, fraction, _ := strings.Cut(strconv.FormatFloat(math.Abs(f), 'f', -1, 64), ".")
+	digits := whole + fraction
+	keep := len(whole) + places
+	if keep >= len(digits) {
+		return f
+	}
+	if keep < 0 {
+		return 0
+	}
+
+	kept := []byte(digits[:keep])
+	if digits[keep] >= '5' {
+		i := len(kept) - 1
+		for ; i >= 0 && kept[i] == '9'; i-- {
+			kept[i] = '0'
+		}
+		if i >= 0 {
+			kept[i]++
+		} else {
+			kept = append([]byte{'1'}, kept...)
+		}
+	}
+	if len(kept) == 0 {
+		return 0
+	}
+
+	// The kept digits are the number scaled up by the places
+	rounded, err := strconv.ParseFloat(string(kept)+"e"+strconv.Itoa(-places), 64)
+	if err != nil {
+		return f
+	}
+	if f < 0 {
+		return -rounded
+	}
+	return rounded
+}
+
+type numberFormatter struct {
+	locale  language.Tag
+	printer *message.Printer
+}
+
+func newNumberFormatter(locale language.Tag) *numberFormatter {
+	return &numberFormatter{locale, message.NewPrinter(locale)}
+}
+
+// Format a number with the locale's grouping and decimal separators.
+// If decimals are passed the number is rounded and always shown with that many decimal places.
+//
+//	{{ formatNumber .Total 2 }}
+func (f *numberFormatter) formatNumber(value any, decimals ...int) (string, error) {
+	n, err := toFloat64(value)
+	if err != nil {
+		return "", err
+	}
+
+	if len(decimals) > 0 {
+		n, _ = round(n, decimals[0])
+		return f.printer.Sprint(number.Decimal(n, number.Scale(decimals[0]))), nil
+	}
+
+	return f.printer.Sprint(number.Decimal(n)), nil
+}
+
+// Format a ratio as a percentage, so 0.125 becomes 13% or 12.5% with one decimal place.
+//
+//	{{ formatPercent .Discount 1 }}
+func (f *numberFormatter) formatPercent(value any, decimals ...int) (string, error) {
+	n, err := toFloat64(value)
+	if err != nil {
+		return "", err
+	}
+
+	scale := 0
+	if len(decimals) > 0 {
+		scale = decimals[0]
+	}
+	n, _ = round(n, scale+2)
+
+	return f.printer.Sprint(number.Percent(n, number.Scale(scale))), nil
+}
+
+// Format an amount with the symbol of an ISO 4217 currency code, e.g. $1,234.56 or 1.234,56 €.
+//
+//	{{ formatCurrency .Total "EUR" }}
+func (f *numberFormatter) formatCurrency(value any, code string) (string, error) {
+	return f.formatAmount(value, code, func(unit currency.Unit) string {
+		return f.printer.Sprint(currency.Symbol(unit))
+	})
+}
+
+// Format an amount with an ISO 4217 currency code, e.g. USD 1,234.56 or 1.234,56 EUR.
+//
+//	{{ formatCurrencyISO .Total "EUR" }}
+func (f *numberFormatter) formatCurrencyISO(value any, code string) (string, error) {
+	return f.formatAmount(value, code, func(unit currency.Unit) string {
+		return unit.String()
+	})
+}
+
+func (f *numberFormatter) formatAmount(value any, code string, label func(unit currency.Unit) string) (string, error) {
+	n, err := toFloat64(value)
+	if err != nil {
+		return "", err
+	}
+
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", fmt.Errorf("invalid currency code %q: %v", code, err)
+	}
+
+	scale, _ := currency.Standard.Rounding(unit)
+	n, _ = round(n, scale)
+	formattedNumber := f.printer.Sprint(number.Decimal(math.Abs(n), number.Scale(scale)))
+
+	sign := ""
+	if n < 0 {
+		sign = "-"
+	}
+
+	unitLabel := label(unit)
+	switch position := currencyPositionForTag(f.locale); {
+	case position == currencySuffix:
+		return sign + formattedNumber + nonBreakingSpace + unitLabel, nil
+	case position == currencyPrefixWithSpace || unitLabel == unit.String():
+		return sign + unitLabel + nonBreakingSpace + formattedNumber, nil
+	default:
+		return sign + unitLabel + formattedNumber, nil
+	}
+}
+
+// Used between amounts and currencies so Word doesn't wrap them onto separate lines.
+const nonBreakingSpace = "\u00a0"
+
+type currencyPosition int
+
+const (
+	currencyPrefix currencyPosition = iota
+	currencyPrefixWithSpace
+	currencySuffix
+)
+
+// Where the currency symbol is placed for each language. Languages not listed use a prefix with no space.
+var currencyPositionsByLanguage = map[string]currencyPosition{
+	"bg": currencySuffix,
+	"cs": currencySuffix,
+	"da": currencySuffix,
+	"de": currencySuffix,
+	"el": currencySuffix,
+	"es": currencySuffix,
+	"et": currencySuffix,
+	"fi": currencySuffix,
+	"fr": currencySuffix,
+	"hr": currencySuffix,
+	"hu": currencySuffix,
+	"it": currencySuffix,
+	"lt": currencySuffix,
+	"lv": currencySuffix,
+	"nb": currencySuffix,
+	"nl": currencyPrefixWithSpace,
+	"no": currencySuffix,
+	"pl": currencySuffix,
+	"pt": currencySuffix,
+	"ro": currencySuffix,
+	"ru": currencySuffix,
+	"sk": currencySuffix,
+	"sl": currencySuffix,
+	"sv": currencySuffix,
+	"uk": currencySuffix,
+}
+
+// Regional variations which differ from the language's position.
+var currencyPositionsByLocale = map[string]currencyPosition{
+	"de-AT": currencyPrefixWithSpace,
+	"de-CH": currencyPrefixWithSpace,
+	"de-LI": currencyPrefixWithSpace,
+	"it-CH": currencyPrefixWithSpace,
+	"pt-BR": currencyPrefixWithSpace,
+}
+
+func currencyPositionForTag(tag language.Tag) currencyPosition {
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	if position, ok := currencyPositionsByLocale[base.String()+"-"+region.String()]; ok {
+		return position
+	}
+	if position, ok := currencyPositionsByLanguage[base.String()]; ok {
+		return position
+	}
+
+	return currencyPrefix
+}
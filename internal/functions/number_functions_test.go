@@ -0,0 +1,261 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          any
+		expectedResult float64
+		expectError    bool
+	}{
+		{
+			name:           "Int",
+			value:          12,
+			expectedResult: 12,
+		},
+		{
+			name:           "Unsigned int",
+			value:          uint8(30),
+			expectedResult: 30,
+		},
+		{
+			name:           "Float",
+			value:          12.5,
+			expectedResult: 12.5,
+		},
+		{
+			name:           "Numeric string",
+			value:          " 1234.56 ",
+			expectedResult: 1234.56,
+		},
+		{
+			name:        "Non numeric string",
+			value:       "twelve",
+			expectError: true,
+		},
+		{
+			name:        "Unsupported type",
+			value:       []int{1},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := toFloat64(tt.value)
+			assert.Equal(tt.expectError, err != nil)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		value          any
+		places         int
+		expectedResult float64
+	}{
+		{value: 1.25, places: 1, expectedResult: 1.3},
+		{value: -1.25, places: 1, expectedResult: -1.3},
+		{value: 1234.5678, places: 2, expectedResult: 1234.57},
+		{value: 1250, places: -2, expectedResult: 1300},
+		{value: 1.005, places: 2, expectedResult: 1.01},
+		{value: 2.675, places: 2, expectedResult: 2.68},
+		{value: -2.675, places: 2, expectedResult: -2.68},
+		{value: 9.995, places: 2, expectedResult: 10},
+		{value: 0.5, places: 0, expectedResult: 1},
+		{value: 0.4, places: 0, expectedResult: 0},
+		{value: 450, places: -3, expectedResult: 0},
+		{value: 550, places: -3, expectedResult: 1000},
+		{value: 1.5, places: 3, expectedResult: 1.5},
+	}
+
+	for _, tt := range tests {
+		result, err := round(tt.value, tt.places)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedResult, result)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         language.Tag
+		value          any
+		decimals       []int
+		expectedResult string
+	}{
+		{
+			name:           "English grouping",
+			locale:         language.English,
+			value:          1234567.5,
+			expectedResult: "1,234,567.5",
+		},
+		{
+			name:           "German grouping",
+			locale:         language.German,
+			value:          1234567.5,
+			expectedResult: "1.234.567,5",
+		},
+		{
+			name:           "Fixed decimals",
+			locale:         language.English,
+			value:          1234.5,
+			decimals:       []int{2},
+			expectedResult: "1,234.50",
+		},
+		{
+			name:           "Fixed decimals are rounded",
+			locale:         language.German,
+			value:          "1234.565",
+			decimals:       []int{2},
+			expectedResult: "1.234,57",
+		},
+		{
+			name:           "Fixed decimals are rounded as written",
+			locale:         language.English,
+			value:          1.005,
+			decimals:       []int{2},
+			expectedResult: "1.01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := newNumberFormatter(tt.locale).formatNumber(tt.value, tt.decimals...)
+			assert.NoError(err)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         language.Tag
+		value          any
+		decimals       []int
+		expectedResult string
+	}{
+		{
+			name:           "English",
+			locale:         language.English,
+			value:          0.125,
+			expectedResult: "13%",
+		},
+		{
+			name:           "English with decimals",
+			locale:         language.English,
+			value:          0.125,
+			decimals:       []int{1},
+			expectedResult: "12.5%",
+		},
+		{
+			name:           "German with decimals",
+			locale:         language.German,
+			value:          0.125,
+			decimals:       []int{1},
+			expectedResult: "12,5\u00a0%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := newNumberFormatter(tt.locale).formatPercent(tt.value, tt.decimals...)
+			assert.NoError(err)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         language.Tag
+		value          any
+		code           string
+		expectedResult string
+	}{
+		{
+			name:           "US dollars in English",
+			locale:         language.AmericanEnglish,
+			value:          1234.56,
+			code:           "USD",
+			expectedResult: "$1,234.56",
+		},
+		{
+			name:           "Euros in German",
+			locale:         language.German,
+			value:          1234.56,
+			code:           "EUR",
+			expectedResult: "1.234,56\u00a0€",
+		},
+		{
+			name:           "Negative amount",
+			locale:         language.BritishEnglish,
+			value:          -10,
+			code:           "GBP",
+			expectedResult: "-£10.00",
+		},
+		{
+			name:           "Amount rounded as written",
+			locale:         language.BritishEnglish,
+			value:          2.675,
+			code:           "GBP",
+			expectedResult: "£2.68",
+		},
+		{
+			name:           "Currency without minor units",
+			locale:         language.English,
+			value:          1234.5,
+			code:           "JPY",
+			expectedResult: "¥1,235",
+		},
+		{
+			name:           "Swiss German",
+			locale:         language.MustParse("de-CH"),
+			value:          1234.5,
+			code:           "CHF",
+			expectedResult: "CHF\u00a01’234.50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := newNumberFormatter(tt.locale).formatCurrency(tt.value, tt.code)
+			assert.NoError(err)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+
+	t.Run("Invalid currency code should return an error", func(t *testing.T) {
+		_, err := newNumberFormatter(language.English).formatCurrency(10, "ZZZZ")
+		assert.Error(t, err)
+	})
+}
+
+func TestFormatCurrencyISO(t *testing.T) {
+	assert := assert.New(t)
+
+	result, err := newNumberFormatter(language.AmericanEnglish).formatCurrencyISO(1234.56, "USD")
+	assert.NoError(err)
+	assert.Equal("USD\u00a01,234.56", result)
+
+	result, err = newNumberFormatter(language.French).formatCurrencyISO(1234.56, "EUR")
+	assert.NoError(err)
+	assert.Equal("1\u00a0234,56\u00a0EUR", result)
+}
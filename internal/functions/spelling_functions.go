@@ -0,0 +1,318 @@
+package functions
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"text/template"
+
+	"golang.org/x/text/language"
+)
+
+// Converts numbers into words for a language.
+type NumberSpeller interface {
+	// Spell a whole number, e.g. "one thousand two hundred and five".
+	Spell(n int64) string
+	// Write a number as an ordinal using digits, e.g. "21st".
+	Ordinal(n int64) string
+	// Spell a whole number as an ordinal, e.g. "twenty-first".
+	SpellOrdinal(n int64) string
+	// Spell an amount of money. The major and minor amounts are never negative, so the sign is passed
+	// separately. If minorUnit is empty the minor amount should be written as a fraction of 100,
+	// e.g. "one thousand two hundred and five pounds 50/100".
+	SpellAmount(negative bool, major int64, minor int64, majorUnit string, minorUnit string) string
+}
+
+var (
+	numberSpellers   = map[string]NumberSpeller{"en": EnglishNumberSpeller{}}
+	numberSpellersMu sync.RWMutex
+)
+
+// Register a speller for a locale. Spellers are looked up by the full locale (e.g. "en-US")
+// and then by the base language (e.g. "en"), falling back to English.
+func RegisterNumberSpeller(locale language.Tag, speller NumberSpeller) {
+	numberSpellersMu.Lock()
+	defer numberSpellersMu.Unlock()
+
+	numberSpellers[locale.String()] = speller
+}
+
+func numberSpellerForTag(tag language.Tag) NumberSpeller {
+	numberSpellersMu.RLock()
+	defer numberSpellersMu.RUnlock()
+
+	if speller, ok := numberSpellers[tag.String()]; ok {
+		return speller
+	}
+	base, _ := tag.Base()
+	if speller, ok := numberSpellers[base.String()]; ok {
+		return speller
+	}
+
+	return numberSpellers["en"]
+}
+
+func numberSpellerForLocale(defaultLocale language.Tag, locale ...string) (NumberSpeller, error) {
+	if len(locale) == 0 {
+		return numberSpellerForTag(defaultLocale), nil
+	}
+
+	tag, err := language.Parse(locale[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return numberSpellerForTag(tag), nil
+}
+
+// Spelling functions. Numbers are spelled in the given locale unless one is passed to the function.
+func newSpellingFunctions(defaultLocale language.Tag) template.FuncMap {
+	return template.FuncMap{
+		"spell": func(value any, locale ...string) (string, error) {
+			return spell(defaultLocale, value, locale...)
+		},
+		"ordinal": func(value any, locale ...string) (string, error) {
+			return ordinal(defaultLocale, value, locale...)
+		},
+		"spellOrdinal": func(value any, locale ...string) (string, error) {
+			return spellOrdinal(defaultLocale, value, locale...)
+		},
+		"spellCurrency": func(value any, majorUnit string, minorUnit ...string) (string, error) {
+			return spellCurrency(defaultLocale, value, majorUnit, minorUnit...)
+		},
+	}
+}
+
+// Convert a value into a whole number, returning an error if it has a fractional part.
+func toWholeNumber(value any) (int64, error) {
+	f, err := toFloat64(value)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("%v is not a whole number", value)
+	}
+	// MaxInt64 can't be represented exactly, so it rounds up to a value that would overflow
+	if f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("%v is too large to spell", value)
+	}
+
+	return int64(f), nil
+}
+
+// Spell a whole number in words.
+//
+//	{{ spell .Quantity }}
+func spell(defaultLocale language.Tag, value any, locale ...string) (string, error) {
+	n, err := toWholeNumber(value)
+	if err != nil {
+		return "", err
+	}
+
+	speller, err := numberSpellerForLocale(defaultLocale, locale...)
+	if err != nil {
+		return "", err
+	}
+
+	return speller.Spell(n), nil
+}
+
+// Write a whole number as an ordinal using digits, e.g. 21st.
+//
+//	{{ ordinal .Day }}
+func ordinal(defaultLocale language.Tag, value any, locale ...string) (string, error) {
+	n, err := toWholeNumber(value)
+	if err != nil {
+		return "", err
+	}
+
+	speller, err := numberSpellerForLocale(defaultLocale, locale...)
+	if err != nil {
+		return "", err
+	}
+
+	return speller.Ordinal(n), nil
+}
+
+// Spell a whole number as an ordinal, e.g. twenty-first.
+//
+//	{{ spellOrdinal .Position }}
+func spellOrdinal(defaultLocale language.Tag, value any, locale ...string) (string, error) {
+	n, err := toWholeNumber(value)
+	if err != nil {
+		return "", err
+	}
+
+	speller, err := numberSpellerForLocale(defaultLocale, locale...)
+	if err != nil {
+		return "", err
+	}
+
+	return speller.SpellOrdinal(n), nil
+}
+
+// Spell an amount of money, rounding it to two decimal places.
+//
+//	{{ spellCurrency .Total "pounds" }}
+//	{{ spellCurrency .Total "dollars" "cents" }}
+func spellCurrency(locale language.Tag, value any, majorUnit string, minorUnit ...string) (string, error) {
+	f, err := toFloat64(value)
+	if err != nil {
+		return "", err
+	}
+
+	if math.IsNaN(f) {
+		return "", fmt.Errorf("%v is not a number", value)
+	}
+	// As in toWholeNumber, MaxInt64 rounds up to a value that would overflow. This also catches infinity.
+	rounded := math.Round(roundDecimal(math.Abs(f), 2) * 100)
+	if rounded >= math.MaxInt64 {
+		return "", fmt.Errorf("%v is too large to spell", value)
+	}
+
+	cents := int64(rounded)
+	major, minor := cents/100, cents%100
+	negative := f < 0 && cents > 0
+
+	minorUnitName := ""
+	if len(minorUnit) > 0 {
+		minorUnitName = minorUnit[0]
+	}
+
+	return numberSpellerForTag(locale).SpellAmount(negative, major, minor, majorUnit, minorUnitName), nil
+}
+
+// Spells numbers in British English, e.g. "one hundred and five".
+type EnglishNumberSpeller struct{}
+
+var englishOnes = []string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+	"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+}
+
+var englishTens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+
+var englishScales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+
+func (s EnglishNumberSpeller) Spell(n int64) string {
+	if n == 0 {
+		return englishOnes[0]
+	}
+	if n < 0 {
+		// Use uint64 so the minimum int64 value can be negated
+		return "minus " + s.spellUnsigned(uint64(-(n+1))+1)
+	}
+
+	return s.spellUnsigned(uint64(n))
+}
+
+func (s EnglishNumberSpeller) spellUnsigned(n uint64) string {
+	var groups []uint64
+	for n > 0 {
+		groups = append(groups, n%1000)
+		n /= 1000
+	}
+
+	var parts []string
+	for i := len(groups) - 1; i >= 0; i-- {
+		group := groups[i]
+		if group == 0 {
+			continue
+		}
+		// Numbers such as 1,005 are spelled "one thousand and five"
+		if i == 0 && group < 100 && len(parts) > 0 {
+			parts = append(parts, "and")
+		}
+		parts = append(parts, s.spellHundreds(group))
+		if englishScales[i] != "" {
+			parts = append(parts, englishScales[i])
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (s EnglishNumberSpeller) spellHundreds(n uint64) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, englishOnes[n/100], "hundred")
+		n %= 100
+		if n > 0 {
+			parts = append(parts, "and")
+		}
+	}
+	if n >= 20 {
+		tens := englishTens[n/10]
+		if n%10 > 0 {
+			tens += "-" + englishOnes[n%10]
+		}
+		parts = append(parts, tens)
+	} else if n > 0 {
+		parts = append(parts, englishOnes[n])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (s EnglishNumberSpeller) Ordinal(n int64) string {
+	suffix := "th"
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs%100 < 11 || abs%100 > 13 {
+		switch abs % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+var englishIrregularOrdinals = map[string]string{
+	"one":    "first",
+	"two":    "second",
+	"three":  "third",
+	"five":   "fifth",
+	"eight":  "eighth",
+	"nine":   "ninth",
+	"twelve": "twelfth",
+}
+
+func (s EnglishNumberSpeller) SpellOrdinal(n int64) string {
+	words := s.Spell(n)
+
+	// Only the last word changes, e.g. "twenty-one" becomes "twenty-first"
+	lastWordStart := strings.LastIndexAny(words, " -") + 1
+	lastWord := words[lastWordStart:]
+	if irregular, ok := englishIrregularOrdinals[lastWord]; ok {
+		lastWord = irregular
+	} else if strings.HasSuffix(lastWord, "y") {
+		lastWord = strings.TrimSuffix(lastWord, "y") + "ieth"
+	} else {
+		lastWord += "th"
+	}
+
+	return words[:lastWordStart] + lastWord
+}
+
+func (s EnglishNumberSpeller) SpellAmount(negative bool, major int64, minor int64, majorUnit string, minorUnit string) string {
+	amount := s.Spell(major) + " " + majorUnit
+	if negative {
+		amount = "minus " + amount
+	}
+	if minorUnit == "" {
+		return fmt.Sprintf("%s %02d/100", amount, minor)
+	}
+	if minor == 0 {
+		return amount
+	}
+
+	return amount + " and " + s.Spell(minor) + " " + minorUnit
+}
@@ -0,0 +1,245 @@
+package functions
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestEnglishSpell(t *testing.T) {
+	tests := []struct {
+		value          int64
+		expectedResult string
+	}{
+		{value: 0, expectedResult: "zero"},
+		{value: 7, expectedResult: "seven"},
+		{value: 15, expectedResult: "fifteen"},
+		{value: 21, expectedResult: "twenty-one"},
+		{value: 100, expectedResult: "one hundred"},
+		{value: 105, expectedResult: "one hundred and five"},
+		{value: 1005, expectedResult: "one thousand and five"},
+		{value: 1205, expectedResult: "one thousand two hundred and five"},
+		{value: 2000000, expectedResult: "two million"},
+		{value: 1000001, expectedResult: "one million and one"},
+		{value: -42, expectedResult: "minus forty-two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expectedResult, func(t *testing.T) {
+			assert.Equal(t, tt.expectedResult, EnglishNumberSpeller{}.Spell(tt.value))
+		})
+	}
+}
+
+func TestEnglishOrdinal(t *testing.T) {
+	tests := []struct {
+		value          int64
+		expectedResult string
+	}{
+		{value: 1, expectedResult: "1st"},
+		{value: 2, expectedResult: "2nd"},
+		{value: 3, expectedResult: "3rd"},
+		{value: 4, expectedResult: "4th"},
+		{value: 11, expectedResult: "11th"},
+		{value: 12, expectedResult: "12th"},
+		{value: 13, expectedResult: "13th"},
+		{value: 21, expectedResult: "21st"},
+		{value: 112, expectedResult: "112th"},
+		{value: 123, expectedResult: "123rd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expectedResult, func(t *testing.T) {
+			assert.Equal(t, tt.expectedResult, EnglishNumberSpeller{}.Ordinal(tt.value))
+		})
+	}
+}
+
+func TestEnglishSpellOrdinal(t *testing.T) {
+	tests := []struct {
+		value          int64
+		expectedResult string
+	}{
+		{value: 1, expectedResult: "first"},
+		{value: 5, expectedResult: "fifth"},
+		{value: 12, expectedResult: "twelfth"},
+		{value: 14, expectedResult: "fourteenth"},
+		{value: 20, expectedResult: "twentieth"},
+		{value: 21, expectedResult: "twenty-first"},
+		{value: 100, expectedResult: "one hundredth"},
+		{value: 1002, expectedResult: "one thousand and second"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expectedResult, func(t *testing.T) {
+			assert.Equal(t, tt.expectedResult, EnglishNumberSpeller{}.SpellOrdinal(tt.value))
+		})
+	}
+}
+
+func TestSpell(t *testing.T) {
+	t.Run("Whole numbers of any type should be spelled", func(t *testing.T) {
+		assert := assert.New(t)
+
+		result, err := spell(language.English, uint8(30))
+		assert.NoError(err)
+		assert.Equal("thirty", result)
+
+		result, err = spell(language.English, "12")
+		assert.NoError(err)
+		assert.Equal("twelve", result)
+	})
+
+	t.Run("Numbers with a fractional part should return an error", func(t *testing.T) {
+		_, err := spell(language.English, 12.5)
+		assert.Error(t, err)
+	})
+
+	t.Run("Numbers too large for an int64 should return an error", func(t *testing.T) {
+		_, err := spell(language.English, math.Pow(2, 63))
+		assert.Error(t, err)
+	})
+}
+
+func TestSpellCurrency(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          any
+		majorUnit      string
+		minorUnit      []string
+		expectedResult string
+		expectError    bool
+	}{
+		{
+			name:           "Minor amount as a fraction",
+			value:          1205.5,
+			majorUnit:      "pounds",
+			expectedResult: "one thousand two hundred and five pounds 50/100",
+		},
+		{
+			name:           "Whole amount as a fraction",
+			value:          12,
+			majorUnit:      "dollars",
+			expectedResult: "twelve dollars 00/100",
+		},
+		{
+			name:           "Minor amount in words",
+			value:          "21.05",
+			majorUnit:      "euros",
+			minorUnit:      []string{"cents"},
+			expectedResult: "twenty-one euros and five cents",
+		},
+		{
+			name:           "Whole amount in words",
+			value:          100,
+			majorUnit:      "pounds",
+			minorUnit:      []string{"pence"},
+			expectedResult: "one hundred pounds",
+		},
+		{
+			name:           "Negative amount",
+			value:          -1205.5,
+			majorUnit:      "pounds",
+			expectedResult: "minus one thousand two hundred and five pounds 50/100",
+		},
+		{
+			name:           "Negative amount less than one",
+			value:          -0.5,
+			majorUnit:      "pounds",
+			minorUnit:      []string{"pence"},
+			expectedResult: "minus zero pounds and fifty pence",
+		},
+		{
+			name:           "Negative amount rounding to zero",
+			value:          -0.001,
+			majorUnit:      "pounds",
+			expectedResult: "zero pounds 00/100",
+		},
+		{
+			name:           "Amount rounded as written",
+			value:          2.675,
+			majorUnit:      "pounds",
+			minorUnit:      []string{"pence"},
+			expectedResult: "two pounds and sixty-eight pence",
+		},
+		{
+			name:        "Amount too large to spell",
+			value:       1e20,
+			majorUnit:   "pounds",
+			expectError: true,
+		},
+		{
+			name:        "Amount whose minor units are too large to spell",
+			value:       9.3e16,
+			majorUnit:   "pounds",
+			minorUnit:   []string{"pence"},
+			expectError: true,
+		},
+		{
+			name:        "Negative amount too large to spell",
+			value:       -1e20,
+			majorUnit:   "pounds",
+			expectError: true,
+		},
+		{
+			name:        "Not a number",
+			value:       math.NaN(),
+			majorUnit:   "pounds",
+			expectError: true,
+		},
+		{
+			name:        "Infinity",
+			value:       math.Inf(1),
+			majorUnit:   "pounds",
+			expectError: true,
+		},
+		{
+			name:        "Negative infinity",
+			value:       math.Inf(-1),
+			majorUnit:   "pounds",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result, err := spellCurrency(language.English, tt.value, tt.majorUnit, tt.minorUnit...)
+			if tt.expectError {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+}
+
+type testNumberSpeller struct {
+	EnglishNumberSpeller
+}
+
+func (s testNumberSpeller) Spell(n int64) string {
+	return "test"
+}
+
+func TestRegisterNumberSpeller(t *testing.T) {
+	assert := assert.New(t)
+
+	RegisterNumberSpeller(language.MustParse("cy"), testNumberSpeller{})
+
+	result, err := spell(language.MustParse("cy-GB"), 1)
+	assert.NoError(err)
+	assert.Equal("test", result)
+
+	result, err = spell(language.English, 1, "cy")
+	assert.NoError(err)
+	assert.Equal("test", result)
+
+	result, err = spell(language.French, 1)
+	assert.NoError(err)
+	assert.Equal("one", result, "Locales without a speller should fall back to English")
+}
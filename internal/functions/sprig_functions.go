@@ -0,0 +1,723 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmlutils"
+)
+
+// General purpose functions using the same names and semantics as the Sprig library
+// (https://masterminds.github.io/sprig/). Functions which clash with the built-in
+// functions (e.g. title, date and round) are left out as the built-in ones already behave the same way.
+var SprigFuncMap = template.FuncMap{
+	// Strings
+	"trim":       strings.TrimSpace,
+	"trimAll":    func(cutset string, s string) string { return strings.Trim(s, cutset) },
+	"trimPrefix": func(prefix string, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix string, s string) string { return strings.TrimSuffix(s, suffix) },
+	"repeat":     func(count int, s string) string { return strings.Repeat(s, max(count, 0)) },
+	"substr":     substr,
+	"nospace":    nospace,
+	"trunc":      trunc,
+	"abbrev":     abbrev,
+	"initials":   initials,
+	"contains":   func(substr string, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix":  func(prefix string, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix string, s string) bool { return strings.HasSuffix(s, suffix) },
+	"quote":      quote,
+	"squote":     squote,
+	"cat":        cat,
+	"replace":    func(old string, new string, s string) string { return strings.ReplaceAll(s, old, new) },
+	"plural":     plural,
+	"indent":     indent,
+	"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+	"snakecase":  func(s string) string { return strings.Join(splitWords(s), "_") },
+	"kebabcase":  func(s string) string { return strings.Join(splitWords(s), "-") },
+	"camelcase":  camelcase,
+	"join":       join,
+	"split":      split,
+	"splitList":  func(sep string, s string) []string { return strings.Split(s, sep) },
+	"toString":   toString,
+	"toStrings":  toStrings,
+
+	// Defaults and flow control
+	"default":  defaultValue,
+	"empty":    empty,
+	"coalesce": coalesce,
+	"ternary":  ternary,
+	"fail":     func(message string) (string, error) { return "", errors.New(message) },
+
+	// Lists
+	"list":      list,
+	"first":     first,
+	"last":      last,
+	"rest":      rest,
+	"initial":   initial,
+	"append":    appendToList,
+	"push":      appendToList,
+	"prepend":   prependToList,
+	"concat":    concat,
+	"reverse":   reverse,
+	"uniq":      uniq,
+	"has":       has,
+	"without":   without,
+	"compact":   compact,
+	"slice":     sliceList,
+	"sortAlpha": sortAlpha,
+	"until":     func(count int) []int { return untilStep(0, count, 1) },
+	"untilStep": untilStep,
+
+	// Dictionaries
+	"dict":   dict,
+	"get":    func(d map[string]any, key string) any { return d[key] },
+	"set":    func(d map[string]any, key string, value any) map[string]any { d[key] = value; return d },
+	"unset":  func(d map[string]any, key string) map[string]any { delete(d, key); return d },
+	"hasKey": func(d map[string]any, key string) bool { _, ok := d[key]; return ok },
+	"keys":   keys,
+	"values": values,
+	"pick":   pick,
+	"omit":   omit,
+
+	// Maths
+	"add":  add,
+	"add1": func(a any) int64 { return toInt64(a) + 1 },
+	"sub":  func(a any, b any) int64 { return toInt64(a) - toInt64(b) },
+	"mul":  mul,
+	"div":  func(a any, b any) (int64, error) { return divide(toInt64(a), toInt64(b)) },
+	"mod":  func(a any, b any) (int64, error) { return modulo(toInt64(a), toInt64(b)) },
+	"max":  maxInt,
+	"min":  minInt,
+	"sum":  sum,
+	"addf": addf,
+	"subf": func(a any, b any) float64 { return toFloat64OrZero(a) - toFloat64OrZero(b) },
+	"mulf": mulf,
+	"divf": func(a any, b any) float64 { return toFloat64OrZero(a) / toFloat64OrZero(b) },
+	"maxf": maxf,
+	"minf": minf,
+	"floor": func(a any) float64 {
+		return math.Floor(toFloat64OrZero(a))
+	},
+	"ceil": func(a any) float64 {
+		return math.Ceil(toFloat64OrZero(a))
+	},
+
+	// Conversions
+	"atoi":    func(s string) int { i, _ := strconv.Atoi(s); return i },
+	"int":     func(v any) int { return int(toInt64(v)) },
+	"int64":   toInt64,
+	"float64": toFloat64OrZero,
+}
+
+// Conversions
+
+// Convert a value into an int64 the same way Sprig does, returning 0 if it can't be converted.
+func toInt64(value any) int64 {
+	if s, ok := value.(string); ok {
+		if i, err := strconv.ParseInt(strings.TrimSpace(s), 0, 64); err == nil {
+			return i
+		}
+	}
+	if b, ok := value.(bool); ok {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	reflectVal := reflect.ValueOf(value)
+	switch reflectVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflectVal.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(reflectVal.Uint())
+	}
+
+	return int64(toFloat64OrZero(value))
+}
+
+func toFloat64OrZero(value any) float64 {
+	f, err := toFloat64(value)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func toString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case fmt.Stringer:
+		return v.String()
+	case error:
+		return v.Error()
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+func toStrings(value any) []string {
+	items := toList(value)
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = toString(item)
+	}
+	return result
+}
+
+// Convert any slice or array into a []any. Other values are returned as a single item list and nil as an empty list.
+func toList(value any) []any {
+	if value == nil {
+		return []any{}
+	}
+	if l, ok := value.([]any); ok {
+		return l
+	}
+
+	reflectVal := reflect.ValueOf(value)
+	switch reflectVal.Kind() {
+	case reflect.Slice, reflect.Array:
+		l := make([]any, reflectVal.Len())
+		for i := range reflectVal.Len() {
+			l[i] = reflectVal.Index(i).Interface()
+		}
+		return l
+	}
+
+	return []any{value}
+}
+
+// Strings
+
+func substr(start int, end int, s string) string {
+	return cutText(s, func(runes []rune) []rune {
+		if start < 0 {
+			return runes[:min(max(end, 0), len(runes))]
+		}
+		if end < 0 || end > len(runes) {
+			return runes[min(start, len(runes)):]
+		}
+		if start > end {
+			return nil
+		}
+		return runes[start:end]
+	})
+}
+
+func nospace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Truncate a string to a length. Negative lengths keep the end of the string.
+func trunc(length int, s string) string {
+	return cutText(s, func(runes []rune) []rune {
+		if length < 0 && len(runes)+length > 0 {
+			return runes[len(runes)+length:]
+		}
+		if length >= 0 && len(runes) > length {
+			return runes[:length]
+		}
+		return runes
+	})
+}
+
+// Truncate a string with an ellipsis so the result is no longer than width.
+func abbrev(width int, s string) string {
+	return cutText(s, func(runes []rune) []rune {
+		if width < 4 || len(runes) <= width {
+			return runes
+		}
+		return append(runes[:width-3], '.', '.', '.')
+	})
+}
+
+// Cut part of a string by characters rather than bytes. Strings in the template data are
+// XML escaped, so they are unescaped first to avoid cutting through an entity such as &amp;.
+func cutText(s string, cut func(runes []rune) []rune) string {
+	runes := []rune(html.UnescapeString(s))
+	escaped, err := xmlutils.EscapeXmlString(string(cut(runes)))
+	if err != nil {
+		return s
+	}
+	return escaped
+}
+
+func initials(s string) string {
+	var sb strings.Builder
+	for _, word := range strings.Fields(s) {
+		for _, r := range word {
+			sb.WriteRune(r)
+			break
+		}
+	}
+	return sb.String()
+}
+
+func quote(values ...any) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			quoted = append(quoted, strconv.Quote(toString(value)))
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+func squote(values ...any) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			quoted = append(quoted, "'"+toString(value)+"'")
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+func cat(values ...any) string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			strs = append(strs, toString(value))
+		}
+	}
+	return strings.Join(strs, " ")
+}
+
+func plural(one string, many string, count int) string {
+	if count == 1 {
+		return one
+	}
+	return many
+}
+
+func indent(spaces int, s string) string {
+	padding := strings.Repeat(" ", max(spaces, 0))
+	return padding + strings.ReplaceAll(s, "\n", "\n"+padding)
+}
+
+// Split text into lower case words on spaces, underscores, hyphens and changes of case.
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		case unicode.IsUpper(r) && len(current) > 0:
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(current))
+				current = nil
+			}
+		}
+		current = append(current, unicode.ToLower(r))
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+func camelcase(s string) string {
+	var sb strings.Builder
+	for _, word := range splitWords(s) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	return sb.String()
+}
+
+func join(sep string, value any) string {
+	return strings.Join(toStrings(value), sep)
+}
+
+// Split a string into a dictionary with the keys _0, _1, _2 etc.
+func split(sep string, s string) map[string]string {
+	parts := strings.Split(s, sep)
+	result := make(map[string]string, len(parts))
+	for i, part := range parts {
+		result["_"+strconv.Itoa(i)] = part
+	}
+	return result
+}
+
+// Defaults and flow control
+
+// Whether a value is empty. Zero values, nil and empty collections are empty.
+func empty(value any) bool {
+	reflectVal := reflect.ValueOf(value)
+	if !reflectVal.IsValid() {
+		return true
+	}
+
+	switch reflectVal.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return reflectVal.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return reflectVal.IsNil()
+	case reflect.Struct:
+		return false
+	}
+
+	return reflectVal.IsZero()
+}
+
+// Return the given value, or the default if it's empty.
+//
+//	{{ .Status | default "New" }}
+func defaultValue(defaultVal any, given ...any) any {
+	if len(given) == 0 || empty(given[0]) {
+		return defaultVal
+	}
+	return given[0]
+}
+
+func coalesce(values ...any) any {
+	for _, value := range values {
+		if !empty(value) {
+			return value
+		}
+	}
+	return nil
+}
+
+func ternary(trueVal any, falseVal any, condition bool) any {
+	if condition {
+		return trueVal
+	}
+	return falseVal
+}
+
+// Lists
+
+func list(values ...any) []any {
+	return values
+}
+
+func first(value any) any {
+	l := toList(value)
+	if len(l) == 0 {
+		return nil
+	}
+	return l[0]
+}
+
+func last(value any) any {
+	l := toList(value)
+	if len(l) == 0 {
+		return nil
+	}
+	return l[len(l)-1]
+}
+
+func rest(value any) []any {
+	l := toList(value)
+	if len(l) == 0 {
+		return []any{}
+	}
+	return l[1:]
+}
+
+func initial(value any) []any {
+	l := toList(value)
+	if len(l) == 0 {
+		return []any{}
+	}
+	return l[:len(l)-1]
+}
+
+func appendToList(value any, item any) []any {
+	l := toList(value)
+	result := make([]any, 0, len(l)+1)
+	return append(append(result, l...), item)
+}
+
+func prependToList(value any, item any) []any {
+	l := toList(value)
+	result := make([]any, 0, len(l)+1)
+	return append(append(result, item), l...)
+}
+
+func concat(lists ...any) []any {
+	result := []any{}
+	for _, l := range lists {
+		result = append(result, toList(l)...)
+	}
+	return result
+}
+
+func reverse(value any) []any {
+	l := toList(value)
+	result := make([]any, len(l))
+	for i, item := range l {
+		result[len(l)-1-i] = item
+	}
+	return result
+}
+
+func uniq(value any) []any {
+	result := []any{}
+	for _, item := range toList(value) {
+		if !has(item, result) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Whether a list contains a value.
+//
+//	{{ if has "admin" .Roles }}
+func has(needle any, haystack any) bool {
+	for _, item := range toList(haystack) {
+		if reflect.DeepEqual(item, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func without(value any, omit ...any) []any {
+	result := []any{}
+	for _, item := range toList(value) {
+		if !has(item, omit) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func compact(value any) []any {
+	result := []any{}
+	for _, item := range toList(value) {
+		if !empty(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Slice a list. With one index the list is sliced from that index, with two it is sliced between them.
+func sliceList(value any, indices ...int) ([]any, error) {
+	l := toList(value)
+	start, end := 0, len(l)
+	if len(indices) > 0 {
+		start = indices[0]
+	}
+	if len(indices) > 1 {
+		end = indices[1]
+	}
+	if start < 0 || end > len(l) || start > end {
+		return nil, fmt.Errorf("slice indices %v out of range for a list of length %d", indices, len(l))
+	}
+	return l[start:end], nil
+}
+
+func sortAlpha(value any) []string {
+	strs := toStrings(value)
+	sort.Strings(strs)
+	return strs
+}
+
+func untilStep(start int, stop int, step int) []int {
+	result := []int{}
+	if step == 0 || (step > 0 && start >= stop) || (step < 0 && start <= stop) {
+		return result
+	}
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		result = append(result, i)
+	}
+	return result
+}
+
+// Dictionaries
+
+// Create a dictionary from key value pairs.
+//
+//	{{ $person := dict "Name" "Tom" "Age" 30 }}
+func dict(values ...any) map[string]any {
+	result := make(map[string]any, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		key := toString(values[i])
+		if i+1 < len(values) {
+			result[key] = values[i+1]
+		} else {
+			result[key] = ""
+		}
+	}
+	return result
+}
+
+func keys(dicts ...map[string]any) []string {
+	result := []string{}
+	for _, d := range dicts {
+		for key := range d {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// The values of a dictionary, ordered by key.
+func values(d map[string]any) []any {
+	result := make([]any, 0, len(d))
+	for _, key := range keys(d) {
+		result = append(result, d[key])
+	}
+	return result
+}
+
+func pick(d map[string]any, keys ...string) map[string]any {
+	result := make(map[string]any, len(keys))
+	for _, key := range keys {
+		if value, ok := d[key]; ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func omit(d map[string]any, keys ...string) map[string]any {
+	result := make(map[string]any, len(d))
+	for key, value := range d {
+		result[key] = value
+	}
+	for _, key := range keys {
+		delete(result, key)
+	}
+	return result
+}
+
+// Maths
+
+func add(values ...any) int64 {
+	var total int64
+	for _, value := range values {
+		total += toInt64(value)
+	}
+	return total
+}
+
+func mul(a any, values ...any) int64 {
+	total := toInt64(a)
+	for _, value := range values {
+		total *= toInt64(value)
+	}
+	return total
+}
+
+func divide(a int64, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+func modulo(a int64, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a % b, nil
+}
+
+func maxInt(a any, values ...any) int64 {
+	result := toInt64(a)
+	for _, value := range values {
+		result = max(result, toInt64(value))
+	}
+	return result
+}
+
+func minInt(a any, values ...any) int64 {
+	result := toInt64(a)
+	for _, value := range values {
+		result = min(result, toInt64(value))
+	}
+	return result
+}
+
+// Add up numbers. Lists are flattened so both sum 1 2 3 and sum .Amounts work.
+// The result is an int64 unless any of the numbers are floats.
+func sum(values ...any) (any, error) {
+	var intTotal int64
+	var floatTotal float64
+	isFloat := false
+
+	for _, value := range values {
+		for _, item := range toList(value) {
+			switch reflect.ValueOf(item).Kind() {
+			case reflect.Float32, reflect.Float64:
+				isFloat = true
+			}
+			f, err := toFloat64(item)
+			if err != nil {
+				return nil, err
+			}
+			floatTotal += f
+			intTotal += toInt64(item)
+		}
+	}
+
+	if isFloat {
+		return floatTotal, nil
+	}
+	return intTotal, nil
+}
+
+func addf(values ...any) float64 {
+	var total float64
+	for _, value := range values {
+		total += toFloat64OrZero(value)
+	}
+	return total
+}
+
+func mulf(a any, values ...any) float64 {
+	total := toFloat64OrZero(a)
+	for _, value := range values {
+		total *= toFloat64OrZero(value)
+	}
+	return total
+}
+
+func maxf(a any, values ...any) float64 {
+	result := toFloat64OrZero(a)
+	for _, value := range values {
+		result = math.Max(result, toFloat64OrZero(value))
+	}
+	return result
+}
+
+func minf(a any, values ...any) float64 {
+	result := toFloat64OrZero(a)
+	for _, value := range values {
+		result = math.Min(result, toFloat64OrZero(value))
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package functions
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprigFunctions(t *testing.T) {
+	tests := []struct {
+		name           string
+		template       string
+		data           any
+		expectedResult string
+		expectError    bool
+	}{
+		// Strings
+		{name: "trim", template: `{{ trim "  hello  " }}`, expectedResult: "hello"},
+		{name: "trimAll", template: `{{ trimAll "$" "$5.00$" }}`, expectedResult: "5.00"},
+		{name: "trimPrefix", template: `{{ trimPrefix "-" "-hello" }}`, expectedResult: "hello"},
+		{name: "trimSuffix", template: `{{ trimSuffix "-" "hello-" }}`, expectedResult: "hello"},
+		{name: "repeat", template: `{{ repeat 3 "ab" }}`, expectedResult: "ababab"},
+		{name: "substr", template: `{{ substr 0 5 "hello world" }}`, expectedResult: "hello"},
+		{name: "substr with negative end", template: `{{ substr 6 -1 "hello world" }}`, expectedResult: "world"},
+		{name: "nospace", template: `{{ nospace "hello w o r l d" }}`, expectedResult: "helloworld"},
+		{name: "trunc", template: `{{ trunc 5 "hello world" }}`, expectedResult: "hello"},
+		{name: "trunc with negative length", template: `{{ trunc -5 "hello world" }}`, expectedResult: "world"},
+		{name: "abbrev", template: `{{ abbrev 8 "hello world" }}`, expectedResult: "hello..."},
+		{name: "substr with escaped text", template: `{{ substr 2 5 "A &amp; B" }}`, expectedResult: "&amp; B"},
+		{name: "trunc with escaped text", template: `{{ trunc 3 "A &amp; B" }}`, expectedResult: "A &amp;"},
+		{name: "trunc with escaped tag", template: `{{ trunc 2 "&lt;b&gt;" }}`, expectedResult: "&lt;b"},
+		{name: "trunc with multibyte text", template: `{{ trunc 4 "Café au lait" }}`, expectedResult: "Café"},
+		{name: "abbrev with escaped text", template: `{{ abbrev 6 "A &amp; B &amp; C" }}`, expectedResult: "A &amp;..."},
+		{name: "abbrev with multibyte text", template: `{{ abbrev 5 "naïveté" }}`, expectedResult: "na..."},
+		{name: "initials", template: `{{ initials "Tom Watkins" }}`, expectedResult: "TW"},
+		{name: "contains", template: `{{ contains "cat" "catch" }}`, expectedResult: "true"},
+		{name: "hasPrefix", template: `{{ hasPrefix "cat" "catch" }}`, expectedResult: "true"},
+		{name: "hasSuffix", template: `{{ hasSuffix "cat" "catch" }}`, expectedResult: "false"},
+		{name: "quote", template: `{{ quote "a" "b" }}`, expectedResult: `"a" "b"`},
+		{name: "squote", template: `{{ squote "a" }}`, expectedResult: `'a'`},
+		{name: "cat", template: `{{ cat "hello" "beautiful" "world" }}`, expectedResult: "hello beautiful world"},
+		{name: "replace", template: `{{ "I Am Henry VIII" | replace " " "-" }}`, expectedResult: "I-Am-Henry-VIII"},
+		{name: "plural", template: `{{ plural "item" "items" 2 }}`, expectedResult: "items"},
+		{name: "indent", template: `{{ indent 2 "a\nb" }}`, expectedResult: "  a\n  b"},
+		{name: "snakecase", template: `{{ snakecase "FirstName" }}`, expectedResult: "first_name"},
+		{name: "kebabcase", template: `{{ kebabcase "HTTPServer" }}`, expectedResult: "http-server"},
+		{name: "camelcase", template: `{{ camelcase "http_server" }}`, expectedResult: "HttpServer"},
+		{name: "join", template: `{{ join ", " .Items }}`, data: map[string]any{"Items": []string{"a", "b", "c"}}, expectedResult: "a, b, c"},
+		{name: "join mixed list", template: `{{ list 1 "two" 3.5 | join "-" }}`, expectedResult: "1-two-3.5"},
+		{name: "split", template: `{{ $parts := split "$" "foo$bar$baz" }}{{ $parts._1 }}`, expectedResult: "bar"},
+		{name: "splitList", template: `{{ splitList "," "a,b,c" | last }}`, expectedResult: "c"},
+		{name: "toString", template: `{{ toString 12 | printf "%q" }}`, expectedResult: `"12"`},
+
+		// Defaults and flow control
+		{name: "default with empty value", template: `{{ .Missing | default "New" }}`, data: map[string]any{}, expectedResult: "New"},
+		{name: "default with value", template: `{{ .Status | default "New" }}`, data: map[string]any{"Status": "Open"}, expectedResult: "Open"},
+		{name: "default with zero", template: `{{ 0 | default 5 }}`, expectedResult: "5"},
+		{name: "empty", template: `{{ empty "" }} {{ empty (list) }} {{ empty 1 }}`, expectedResult: "true true false"},
+		{name: "coalesce", template: `{{ coalesce "" 0 "first" "second" }}`, expectedResult: "first"},
+		{name: "ternary", template: `{{ ternary "yes" "no" true }} {{ false | ternary "yes" "no" }}`, expectedResult: "yes no"},
+		{name: "fail", template: `{{ fail "missing data" }}`, expectError: true},
+
+		// Lists
+		{name: "first", template: `{{ first (list 1 2 3) }}`, expectedResult: "1"},
+		{name: "first of native slice", template: `{{ first .Items }}`, data: map[string]any{"Items": []string{"a", "b"}}, expectedResult: "a"},
+		{name: "first of empty list", template: `{{ first (list) }}`, expectedResult: "<no value>"},
+		{name: "last", template: `{{ last (list 1 2 3) }}`, expectedResult: "3"},
+		{name: "rest", template: `{{ rest (list 1 2 3) }}`, expectedResult: "[2 3]"},
+		{name: "initial", template: `{{ initial (list 1 2 3) }}`, expectedResult: "[1 2]"},
+		{name: "append", template: `{{ append (list 1 2) 3 }}`, expectedResult: "[1 2 3]"},
+		{name: "prepend", template: `{{ prepend (list 1 2) 0 }}`, expectedResult: "[0 1 2]"},
+		{name: "concat", template: `{{ concat (list 1 2) (list 3) }}`, expectedResult: "[1 2 3]"},
+		{name: "reverse", template: `{{ reverse (list 1 2 3) }}`, expectedResult: "[3 2 1]"},
+		{name: "uniq", template: `{{ uniq (list 1 1 2 1 3) }}`, expectedResult: "[1 2 3]"},
+		{name: "has", template: `{{ has 2 (list 1 2 3) }} {{ has 4 (list 1 2 3) }}`, expectedResult: "true false"},
+		{name: "without", template: `{{ without (list 1 2 3 4) 1 3 }}`, expectedResult: "[2 4]"},
+		{name: "compact", template: `{{ compact (list 1 "" 0 "a") }}`, expectedResult: "[1 a]"},
+		{name: "slice", template: `{{ slice (list 1 2 3 4) 1 3 }}`, expectedResult: "[2 3]"},
+		{name: "slice out of range", template: `{{ slice (list 1 2) 1 3 }}`, expectError: true},
+		{name: "sortAlpha", template: `{{ sortAlpha (list "c" "a" "b") }}`, expectedResult: "[a b c]"},
+		{name: "until", template: `{{ until 3 }}`, expectedResult: "[0 1 2]"},
+		{name: "untilStep", template: `{{ untilStep 10 0 -4 }}`, expectedResult: "[10 6 2]"},
+
+		// Dictionaries
+		{name: "dict and get", template: `{{ $d := dict "Name" "Tom" "Age" 30 }}{{ get $d "Name" }} {{ $d.Age }}`, expectedResult: "Tom 30"},
+		{name: "set and unset", template: `{{ $d := dict "a" 1 }}{{ $_ := set $d "b" 2 }}{{ $_ := unset $d "a" }}{{ keys $d }}`, expectedResult: "[b]"},
+		{name: "hasKey", template: `{{ hasKey (dict "a" 1) "a" }}`, expectedResult: "true"},
+		{name: "keys", template: `{{ keys (dict "b" 1 "a" 2) }}`, expectedResult: "[a b]"},
+		{name: "values", template: `{{ values (dict "b" 1 "a" 2) }}`, expectedResult: "[2 1]"},
+		{name: "pick", template: `{{ keys (pick (dict "a" 1 "b" 2 "c" 3) "a" "c") }}`, expectedResult: "[a c]"},
+		{name: "omit", template: `{{ keys (omit (dict "a" 1 "b" 2 "c" 3) "a") }}`, expectedResult: "[b c]"},
+
+		// Maths
+		{name: "add", template: `{{ add 1 2 "3" }}`, expectedResult: "6"},
+		{name: "add1", template: `{{ add1 .Index }}`, data: map[string]any{"Index": 0}, expectedResult: "1"},
+		{name: "sub", template: `{{ sub 5 3 }}`, expectedResult: "2"},
+		{name: "mul", template: `{{ mul 2 3 4 }}`, expectedResult: "24"},
+		{name: "div", template: `{{ div 7 2 }}`, expectedResult: "3"},
+		{name: "div by zero", template: `{{ div 7 0 }}`, expectError: true},
+		{name: "mod", template: `{{ mod 7 2 }}`, expectedResult: "1"},
+		{name: "max", template: `{{ max 1 5 3 }}`, expectedResult: "5"},
+		{name: "min", template: `{{ min 4 2 3 }}`, expectedResult: "2"},
+		{name: "sum of values", template: `{{ sum 1 2 3 }}`, expectedResult: "6"},
+		{name: "sum of a list", template: `{{ sum .Amounts }}`, data: map[string]any{"Amounts": []float64{1.5, 2.25}}, expectedResult: "3.75"},
+		{name: "sum of non numbers", template: `{{ sum "a" }}`, expectError: true},
+		{name: "addf", template: `{{ addf 1.5 2 }}`, expectedResult: "3.5"},
+		{name: "subf", template: `{{ subf 1.5 2 }}`, expectedResult: "-0.5"},
+		{name: "mulf", template: `{{ mulf 1.5 2 }}`, expectedResult: "3"},
+		{name: "divf", template: `{{ divf 3 2 }}`, expectedResult: "1.5"},
+		{name: "maxf and minf", template: `{{ maxf 1.5 2.5 }} {{ minf 1.5 2.5 }}`, expectedResult: "2.5 1.5"},
+		{name: "floor and ceil", template: `{{ floor 1.5 }} {{ ceil 1.5 }}`, expectedResult: "1 2"},
+
+		// Conversions
+		{name: "atoi", template: `{{ atoi "42" }}`, expectedResult: "42"},
+		{name: "int", template: `{{ int "12" }} {{ int 3.9 }}`, expectedResult: "12 3"},
+		{name: "float64", template: `{{ float64 "1.5" }}`, expectedResult: "1.5"},
+		{name: "toStrings", template: `{{ toStrings (list 1 2) | join "," }}`, expectedResult: "1,2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			tmpl, err := template.New("").Funcs(SprigFuncMap).Parse(tt.template)
+			require.NoError(err)
+
+			buf := &bytes.Buffer{}
+			err = tmpl.Execute(buf, tt.data)
+			assert.Equal(tt.expectError, err != nil, "unexpected error state: %v", err)
+			if !tt.expectError {
+				assert.Equal(tt.expectedResult, buf.String())
+			}
+		})
+	}
+}
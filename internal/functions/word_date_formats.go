@@ -0,0 +1,135 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The parts of a Word date format in the order they are matched, with their Go layouts. Parts
+// without a Go layout can be formatted but not converted into a layout.
+var wordDateParts = []struct {
+	word     string
+	goLayout string
+}{
+	{"yyyy", "2006"},
+	{"yy", "06"},
+	{"MMMM", "January"},
+	{"MMM", "Jan"},
+	{"MM", "01"},
+	{"M", "1"},
+	{"dddd", "Monday"},
+	{"ddd", "Mon"},
+	{"dd", "02"},
+	{"d", "2"},
+	{"HH", "15"},
+	{"H", ""},
+	{"hh", "03"},
+	{"h", "3"},
+	{"mm", "04"},
+	{"m", "4"},
+	{"ss", "05"},
+	{"s", "5"},
+	{"AM/PM", "PM"},
+	{"am/pm", "pm"},
+}
+
+// A part of a Word date format. Either a date part such as "MMMM" or text which is written out
+// as it is, e.g. "of" in "d 'of' MMMM" or the slashes in "dd/MM/yyyy".
+type wordDateFormatPart struct {
+	word     string
+	goLayout string
+	text     string
+	quoted   bool
+}
+
+// Split a Word date format into its parts. Quotes which aren't closed run to the end of the
+// format, as they do in Word, and are returned with an error.
+func splitWordDateFormat(format string) ([]wordDateFormatPart, error) {
+	var parts []wordDateFormatPart
+	var err error
+	for format != "" {
+		if format[0] == '\'' {
+			literal, rest, found := strings.Cut(format[1:], "'")
+			if !found {
+				err = errors.New("has an unclosed quote")
+			}
+			parts = append(parts, wordDateFormatPart{text: literal, quoted: true})
+			format = rest
+			continue
+		}
+
+		matched := false
+		for _, part := range wordDateParts {
+			if strings.HasPrefix(format, part.word) {
+				parts = append(parts, wordDateFormatPart{word: part.word, goLayout: part.goLayout})
+				format = format[len(part.word):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			_, size := utf8.DecodeRuneInString(format)
+			parts = append(parts, wordDateFormatPart{text: format[:size]})
+			format = format[size:]
+		}
+	}
+	return parts, err
+}
+
+// Format a date with a Word date format, e.g. from a date picker or the \@ switch of a field.
+// Text in single quotes is written out as it is.
+//
+//	FormatWordDate(t, "dddd, d MMMM yyyy") => "Friday, 1 March 2024"
+func FormatWordDate(t time.Time, format string) string {
+	parts, _ := splitWordDateFormat(format)
+
+	var buf strings.Builder
+	for _, part := range parts {
+		switch {
+		case part.word == "H":
+			buf.WriteString(strconv.Itoa(t.Hour()))
+		case part.word != "":
+			buf.WriteString(t.Format(part.goLayout))
+		default:
+			buf.WriteString(part.text)
+		}
+	}
+	return buf.String()
+}
+
+// Text which Go would take for part of a date layout.
+var goLayoutRegex = regexp.MustCompile(`[0-9]|Jan|Mon|MST|PM|pm`)
+
+// Convert a Word date format, e.g. "d MMMM yyyy", into a Go layout, e.g. "2 January 2006".
+// Errors describe the format, e.g. `"H:mm" has parts which can't be converted`.
+func WordDateLayout(format string) (string, error) {
+	parts, err := splitWordDateFormat(format)
+	if err != nil {
+		return "", fmt.Errorf("%q %w", format, err)
+	}
+
+	var layout strings.Builder
+	for _, part := range parts {
+		switch {
+		case part.word != "" && part.goLayout != "":
+			layout.WriteString(part.goLayout)
+		case part.quoted && goLayoutRegex.MatchString(part.text):
+			return "", fmt.Errorf("%q has text which Go would take for part of the date", format)
+		case part.word != "" || (!part.quoted && strings.ContainsFunc(part.text, isLetterOrDigit)):
+			return "", fmt.Errorf("%q has parts which can't be converted", format)
+		default:
+			layout.WriteString(part.text)
+		}
+	}
+	return layout.String(), nil
+}
+
+func isLetterOrDigit(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
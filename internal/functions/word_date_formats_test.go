@@ -0,0 +1,60 @@
+package functions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatWordDate(t *testing.T) {
+	date := time.Date(2024, 3, 1, 14, 5, 9, 0, time.UTC)
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"dd/MM/yyyy", "01/03/2024"},
+		{"M/d/yy", "3/1/24"},
+		{"dddd, d MMMM yyyy", "Friday, 1 March 2024"},
+		{"ddd d MMM", "Fri 1 Mar"},
+		{"HH:mm:ss", "14:05:09"},
+		{"H:mm", "14:05"},
+		{"h:mm am/pm", "2:05 pm"},
+		{"d 'of' MMMM", "1 of March"},
+		{"d 'day 1", "1 day 1"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FormatWordDate(date, tt.format), tt.format)
+	}
+}
+
+func TestWordDateLayout(t *testing.T) {
+	tests := []struct {
+		format         string
+		expectedLayout string
+	}{
+		{"dd/MM/yyyy", "02/01/2006"},
+		{"M/d/yy", "1/2/06"},
+		{"ddd d MMM", "Mon 2 Jan"},
+		{"HH:mm:ss", "15:04:05"},
+		{"h:mm am/pm", "3:04 pm"},
+		{"d 'of' MMMM", "2 of January"},
+	}
+
+	for _, tt := range tests {
+		layout, err := WordDateLayout(tt.format)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedLayout, layout, tt.format)
+	}
+
+	_, err := WordDateLayout("d 'day 1' MMMM")
+	assert.EqualError(t, err, `"d 'day 1' MMMM" has text which Go would take for part of the date`)
+
+	_, err = WordDateLayout("H:mm")
+	assert.EqualError(t, err, `"H:mm" has parts which can't be converted`)
+
+	_, err = WordDateLayout("d 'of MMMM")
+	assert.EqualError(t, err, `"d 'of MMMM" has an unclosed quote`)
+}
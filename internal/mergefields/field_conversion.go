@@ -0,0 +1,347 @@
+package mergefields
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/functions"
+)
+
+// A word of a field instruction, e.g. MERGEFIELD, \* or "quoted text". Quoted text can have nested fields.
+type token struct {
+	segments []segment
+	quoted   bool
+}
+
+// The text of the token, or false if it has nested fields.
+func (t token) literal() (string, bool) {
+	var buf strings.Builder
+	for _, s := range t.segments {
+		if s.field != nil {
+			return "", false
+		}
+		buf.WriteString(s.text)
+	}
+	return buf.String(), true
+}
+
+// Split a field instruction into words, keeping quoted text together.
+func tokenize(instruction []segment) []token {
+	tokens := []token{}
+	current := -1
+	inQuotes := false
+	appendText := func(text string) {
+		segments := tokens[current].segments
+		if len(segments) > 0 && segments[len(segments)-1].field == nil {
+			segments[len(segments)-1].text += text
+		} else {
+			tokens[current].segments = append(segments, segment{text: text})
+		}
+	}
+
+	for _, s := range instruction {
+		if s.field != nil {
+			if current == -1 {
+				tokens = append(tokens, token{})
+				current = len(tokens) - 1
+			}
+			tokens[current].segments = append(tokens[current].segments, s)
+			continue
+		}
+
+		chars := []rune(s.text)
+		for i := 0; i < len(chars); i++ {
+			c := chars[i]
+			switch {
+			case inQuotes && c == '\\' && i+1 < len(chars) && (chars[i+1] == '"' || chars[i+1] == '\\'):
+				i++
+				appendText(string(chars[i]))
+			case c == '"' && inQuotes:
+				inQuotes = false
+				current = -1
+			case c == '"':
+				tokens = append(tokens, token{quoted: true})
+				current = len(tokens) - 1
+				inQuotes = true
+			case unicode.IsSpace(c) && !inQuotes:
+				current = -1
+			default:
+				if current == -1 {
+					tokens = append(tokens, token{})
+					current = len(tokens) - 1
+				}
+				appendText(string(c))
+			}
+		}
+	}
+
+	return tokens
+}
+
+// Convert a field into the text of a tag. Fields which can't be converted return false along with the reason.
+// Fields which are removed return true with the reason.
+func convertField(f *field) (text string, ok bool, reason string) {
+	tokens := tokenize(f.instruction)
+	switch f.keyword() {
+	case "MERGEFIELD":
+		text, err := convertMergeField(tokens)
+		if err != nil {
+			return "", false, err.Error()
+		}
+		return text, true, ""
+	case "IF":
+		text, err := convertIf(tokens)
+		if err != nil {
+			return "", false, err.Error()
+		}
+		return text, true, ""
+	case "NEXT":
+		return "", true, "NEXT fields move on to the next record, which a template can't do, so it was removed. Loop over the records with range instead"
+	case "NEXTIF", "SKIPIF", "MERGEREC", "MERGESEQ":
+		return "", false, f.keyword() + " fields depend on the records being merged, which a template can't do"
+	}
+
+	return "", false, "merge fields can only be converted inside IF fields"
+}
+
+// Get the expression for the value of a merge field, e.g. .FirstName or (index . "First Name").
+func fieldExpression(name string) string {
+	if identifierRegex.MatchString(name) {
+		return "." + name
+	}
+	return fmt.Sprintf("(index . %s)", strconv.Quote(name))
+}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var switchesWithArguments = []string{`\*`, `\@`, `\#`, `\b`, `\f`}
+
+// Convert a MERGEFIELD, e.g. MERGEFIELD Total \# "#,##0.00" \b "Total: " becomes {{ if .Total }}Total: {{ formatNumber .Total 2 }}{{ end -}}
+func convertMergeField(tokens []token) (string, error) {
+	if len(tokens) < 2 {
+		return "", fmt.Errorf("the field has no name")
+	}
+	name, ok := tokens[1].literal()
+	if !ok || name == "" {
+		return "", fmt.Errorf("the field name isn't text")
+	}
+
+	value := fieldExpression(name)
+	expression := value
+	apply := func(format string, args ...any) {
+		if strings.HasPrefix(expression, ".") || strings.HasPrefix(expression, "(") {
+			args = append(args, expression)
+		} else {
+			args = append(args, "("+expression+")")
+		}
+		expression = fmt.Sprintf(format, args...)
+	}
+	before, after := "", ""
+
+	for i := 2; i < len(tokens); i++ {
+		fieldSwitch, _ := tokens[i].literal()
+		argument := ""
+		if slices.Contains(switchesWithArguments, strings.ToLower(fieldSwitch)) && i+1 < len(tokens) {
+			i++
+			if argument, ok = tokens[i].literal(); !ok {
+				return "", fmt.Errorf("the %s switch has a nested field", fieldSwitch)
+			}
+		}
+
+		switch strings.ToLower(fieldSwitch) {
+		case `\*`:
+			switch strings.ToLower(argument) {
+			case "mergeformat", "charformat":
+				// The formatting of the field is kept by the run the tag is in
+			case "upper":
+				apply("upper %s")
+			case "lower":
+				apply("lower %s")
+			case "caps":
+				apply("title %s")
+			default:
+				return "", fmt.Errorf(`the \* %s format has no equivalent function`, argument)
+			}
+		case `\@`:
+			layout, err := functions.WordDateLayout(argument)
+			if err != nil {
+				return "", fmt.Errorf(`the \@ date format %w`, err)
+			}
+			apply("date %q %s", layout)
+		case `\#`:
+			decimals, err := numberFormatDecimals(argument)
+			if err != nil {
+				return "", err
+			}
+			apply("formatNumber %[2]s %[1]d", decimals)
+		case `\b`:
+			before = argument
+		case `\f`:
+			after = argument
+		default:
+			return "", fmt.Errorf("the %s switch isn't supported", fieldSwitch)
+		}
+	}
+
+	tag := "{{ " + expression + " }}"
+	if before != "" || after != "" {
+		// Text before and after the value is only shown when there is a value. The right trim marker stops
+		// the end tag being taken for a table row loop, and there's never any whitespace after it to trim.
+		tag = "{{ if " + value + " }}" + before + tag + after + "{{ end -}}"
+	}
+	return tag, nil
+}
+
+var comparisonFunctions = map[string]string{
+	"=":  "eq",
+	"<>": "ne",
+	"<":  "lt",
+	"<=": "le",
+	">":  "gt",
+	">=": "ge",
+}
+
+// Convert an IF field, e.g. IF { MERGEFIELD Gender } = "Male" "Mr" "Ms" becomes {{ if eq (print .Gender) "Male" }}Mr{{ else }}Ms{{ end -}}
+func convertIf(tokens []token) (string, error) {
+	if len(tokens) != 5 && len(tokens) != 6 {
+		return "", fmt.Errorf("the field should have a comparison followed by the text to show when it is true and false")
+	}
+	operator, _ := tokens[2].literal()
+	function, ok := comparisonFunctions[operator]
+	if !ok {
+		return "", fmt.Errorf("the %q comparison isn't supported", operator)
+	}
+
+	// Text is compared as it appears in the document, while numbers are compared by value
+	left, leftIsNumber, err := convertOperand(tokens[1], operator)
+	if err != nil {
+		return "", err
+	}
+	right, rightIsNumber, err := convertOperand(tokens[3], operator)
+	if err != nil {
+		return "", err
+	}
+	condition := fmt.Sprintf("%s %s %s", function, asText(left), asText(right))
+	switch {
+	case function != "eq" && function != "ne" && (leftIsNumber || rightIsNumber):
+		condition = fmt.Sprintf("%s %s %s", function, left, right)
+	case (function == "eq" || function == "ne") && (left == `""` || right == `""`):
+		// Fields are blank when they have no value, which print would show as <nil>
+		value := left
+		if value == `""` {
+			value = right
+		}
+		condition = value
+		if function == "eq" {
+			condition = "not " + value
+		}
+	}
+
+	trueText, err := convertText(tokens[4])
+	if err != nil {
+		return "", err
+	}
+	tag := fmt.Sprintf("{{ if %s }}%s", condition, trueText)
+	if len(tokens) == 6 {
+		falseText, err := convertText(tokens[5])
+		if err != nil {
+			return "", err
+		}
+		if falseText != "" {
+			tag += "{{ else }}" + falseText
+		}
+	}
+	return tag + "{{ end -}}", nil
+}
+
+var numberRegex = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Convert one side of a comparison into an expression, returning whether it is a number.
+func convertOperand(operand token, operator string) (string, bool, error) {
+	if len(operand.segments) == 1 && operand.segments[0].field != nil {
+		nested := operand.segments[0].field
+		if nested.keyword() != "MERGEFIELD" {
+			return "", false, fmt.Errorf("%s fields can't be compared", nested.keyword())
+		}
+		tokens := tokenize(nested.instruction)
+		name, ok := "", false
+		if len(tokens) >= 2 {
+			name, ok = tokens[1].literal()
+		}
+		if !ok || name == "" {
+			return "", false, fmt.Errorf("the field name isn't text")
+		}
+		return fieldExpression(name), false, nil
+	}
+
+	text, ok := operand.literal()
+	if !ok {
+		return "", false, fmt.Errorf("comparisons with text and fields together aren't supported")
+	}
+	if (operator == "=" || operator == "<>") && strings.ContainsAny(text, "*?") {
+		return "", false, fmt.Errorf("comparisons with wildcards aren't supported")
+	}
+	if !operand.quoted && numberRegex.MatchString(text) {
+		return text, true, nil
+	}
+	return strconv.Quote(text), false, nil
+}
+
+// Make an expression compare as text, so values which aren't strings can be compared with text.
+func asText(expression string) string {
+	if strings.HasPrefix(expression, `"`) {
+		return expression
+	}
+	if numberRegex.MatchString(expression) {
+		return strconv.Quote(expression)
+	}
+	return "(print " + expression + ")"
+}
+
+// Convert the text shown by an IF field, including any merge fields or IF fields in it.
+func convertText(text token) (string, error) {
+	var buf strings.Builder
+	for _, s := range text.segments {
+		if s.field == nil {
+			// Whitespace after a nested field would be trimmed by its end tag so is printed instead
+			if trimmed := strings.TrimLeftFunc(s.text, unicode.IsSpace); strings.HasSuffix(buf.String(), "-}}") && trimmed != s.text {
+				buf.WriteString("{{ " + strconv.Quote(strings.TrimSuffix(s.text, trimmed)) + " }}")
+				s.text = trimmed
+			}
+			buf.WriteString(s.text)
+			continue
+		}
+		nestedTokens := tokenize(s.field.instruction)
+		var nested string
+		var err error
+		switch s.field.keyword() {
+		case "MERGEFIELD":
+			nested, err = convertMergeField(nestedTokens)
+		case "IF":
+			nested, err = convertIf(nestedTokens)
+		default:
+			err = fmt.Errorf("%s fields inside IF fields aren't supported", s.field.keyword())
+		}
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(nested)
+	}
+	return buf.String(), nil
+}
+
+// The number of decimal places shown by a number format, e.g. "#,##0.00" shows 2. Only formats
+// which group thousands and have no other text can be converted.
+func numberFormatDecimals(format string) (int, error) {
+	if !numberFormatRegex.MatchString(format) {
+		return 0, fmt.Errorf(`the \# %q number format has no equivalent function`, format)
+	}
+	_, decimals, _ := strings.Cut(format, ".")
+	return len(decimals), nil
+}
+
+var numberFormatRegex = regexp.MustCompile(`^#,##0(\.0+)?$`)
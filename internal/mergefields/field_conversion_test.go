@@ -0,0 +1,80 @@
+package mergefields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertField(t *testing.T) {
+	tests := []struct {
+		instruction    string
+		expectedText   string
+		expectedReason string
+	}{
+		{`MERGEFIELD Name`, `{{ .Name }}`, ""},
+		{`mergefield  Name  \* MERGEFORMAT`, `{{ .Name }}`, ""},
+		{`MERGEFIELD Name \* Caps`, `{{ title .Name }}`, ""},
+		{`MERGEFIELD Date \@ "dddd, d MMMM yyyy"`, `{{ date "Monday, 2 January 2006" .Date }}`, ""},
+		{`MERGEFIELD Total \# #,##0.00`, `{{ formatNumber .Total 2 }}`, ""},
+		{`MERGEFIELD Total \# "#,##0" \* Upper`, `{{ upper (formatNumber .Total 0) }}`, ""},
+		{`MERGEFIELD Phone \b "Tel: " \f "."`, `{{ if .Phone }}Tel: {{ .Phone }}.{{ end -}}`, ""},
+		{`MERGEFIELD Name \* FirstCap`, "", `the \* FirstCap format has no equivalent function`},
+		{`MERGEFIELD Date \@ "H:mm"`, "", `the \@ date format "H:mm" has parts which can't be converted`},
+		{`MERGEFIELD Name \m`, "", `the \m switch isn't supported`},
+		{`IF "a" = "b" "Yes" "No"`, `{{ if eq "a" "b" }}Yes{{ else }}No{{ end -}}`, ""},
+		{`IF 1 < 2`, "", `the field should have a comparison followed by the text to show when it is true and false`},
+		{`IF "a" ~ "b" "Yes" "No"`, "", `the "~" comparison isn't supported`},
+		{`IF "Smith" = "Sm*" "Yes" "No"`, "", `comparisons with wildcards aren't supported`},
+		{`MERGEREC`, "", `MERGEREC fields depend on the records being merged, which a template can't do`},
+	}
+
+	for _, tt := range tests {
+		text, _, reason := convertField(&field{instruction: []segment{{text: tt.instruction}}})
+		assert.Equal(t, tt.expectedText, text, tt.instruction)
+		assert.Equal(t, tt.expectedReason, reason, tt.instruction)
+	}
+}
+
+func TestConvertIfWithNestedFields(t *testing.T) {
+	mergeField := func(name string) segment {
+		return segment{field: &field{instruction: []segment{{text: " MERGEFIELD " + name + " "}}}}
+	}
+
+	tests := []struct {
+		name         string
+		instruction  []segment
+		expectedText string
+	}{
+		{
+			name:         "Text comparison",
+			instruction:  []segment{{text: "IF "}, mergeField("Gender"), {text: ` = "Male" "Mr" "Ms"`}},
+			expectedText: `{{ if eq (print .Gender) "Male" }}Mr{{ else }}Ms{{ end -}}`,
+		},
+		{
+			name:         "Number comparison",
+			instruction:  []segment{{text: "IF "}, mergeField("Total"), {text: ` >= 100 "Free delivery" ""`}},
+			expectedText: `{{ if ge .Total 100 }}Free delivery{{ end -}}`,
+		},
+		{
+			name:         "Blank comparison",
+			instruction:  []segment{{text: "IF "}, mergeField("Company"), {text: ` = "" "Dear customer" "Dear `}, mergeField("Company"), {text: `"`}},
+			expectedText: `{{ if not .Company }}Dear customer{{ else }}Dear {{ .Company }}{{ end -}}`,
+		},
+		{
+			name: "Nested IF",
+			instruction: []segment{{text: "IF "}, mergeField("Member"), {text: ` = "Yes" "`},
+				{field: &field{instruction: []segment{{text: "IF "}, mergeField("Gold"), {text: ` = "Yes" "Gold"`}}}},
+				{text: ` member" ""`}},
+			expectedText: `{{ if eq (print .Member) "Yes" }}{{ if eq (print .Gold) "Yes" }}Gold{{ end -}}{{ " " }}member{{ end -}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, ok, reason := convertField(&field{instruction: tt.instruction})
+			assert.True(t, ok, reason)
+			assert.Equal(t, tt.expectedText, text)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package mergefields
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// A field which couldn't be converted and was left in the document, or removed if noted in the reason.
+type Issue struct {
+	// The field code, with nested fields in braces, e.g. "IF { MERGEFIELD Total } > 100 "Large" "Small""
+	Field  string
+	Reason string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("{ %s }: %s", i.Field, i.Reason)
+}
+
+// A part of a field's instruction, either text or a nested field.
+type segment struct {
+	text  string
+	field *field
+}
+
+type field struct {
+	instruction []segment
+
+	// The indexes of the runs holding the begin and end markers within the paragraph
+	begin int
+	end   int
+
+	// The formatting of the field and of its result, which is kept for the converted tag
+	properties       *xmltree.Element
+	resultProperties *xmltree.Element
+	inResult         bool
+}
+
+// The field code with nested fields in braces, e.g. "IF { MERGEFIELD Title } = "Dr" "Doctor" "".
+func (f *field) code() string {
+	var buf strings.Builder
+	for _, s := range f.instruction {
+		if s.field != nil {
+			buf.WriteString("{ " + s.field.code() + " }")
+		} else {
+			buf.WriteString(s.text)
+		}
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// The field type in upper case, e.g. MERGEFIELD.
+func (f *field) keyword() string {
+	tokens := tokenize(f.instruction)
+	if len(tokens) == 0 {
+		return ""
+	}
+	keyword, _ := tokens[0].literal()
+	return strings.ToUpper(keyword)
+}
+
+// Whether the field is a merge field or has one nested in its instruction.
+func (f *field) hasMergeField() bool {
+	if f.keyword() == "MERGEFIELD" {
+		return true
+	}
+	for _, s := range f.instruction {
+		if s.field != nil && s.field.hasMergeField() {
+			return true
+		}
+	}
+	return false
+}
+
+// Mail merge fields which control the records being merged and have no equivalent in a template.
+var recordFields = []string{"NEXT", "NEXTIF", "SKIPIF", "MERGEREC", "MERGESEQ"}
+
+// Convert the MERGEFIELD and IF fields of a mail merge document into tags, e.g. { MERGEFIELD FirstName }
+// becomes {{ .FirstName }}. The fields can be simple fields or complex fields made of field characters.
+// Fields which can't be converted are left as they are, apart from NEXT fields which are removed, and
+// returned as issues.
+func Convert(xmlString string) (string, []Issue, error) {
+	nodes, err := xmltree.ParseString(xmlString)
+	if err != nil {
+		return "", nil, err
+	}
+
+	issues := []Issue{}
+	for _, paragraph := range xmltree.FindDescendants(nodes, "w:p") {
+		issues = append(issues, convertParagraph(paragraph)...)
+	}
+
+	return xmltree.Marshal(nodes...), issues, nil
+}
+
+func convertParagraph(paragraph *xmltree.Element) []Issue {
+	fields := []*field{}
+	stack := []*field{}
+
+	for i, child := range paragraph.Children {
+		element, ok := child.(*xmltree.Element)
+		if !ok {
+			continue
+		}
+		if element.Is("w:fldSimple") && len(stack) == 0 {
+			instruction, _ := element.AttrValue("w:instr")
+			f := &field{instruction: []segment{{text: instruction}}, begin: i, end: i}
+			if run := element.Find("w:r"); run != nil {
+				f.resultProperties = run.Find("w:rPr")
+			}
+			fields = append(fields, f)
+			continue
+		}
+		if !element.Is("w:r") {
+			continue
+		}
+
+		for _, runChild := range element.Elements() {
+			switch {
+			case runChild.Is("w:fldChar"):
+				fieldCharType, _ := runChild.AttrValue("w:fldCharType")
+				switch fieldCharType {
+				case "begin":
+					f := &field{begin: i, properties: element.Find("w:rPr")}
+					if len(stack) > 0 && !stack[len(stack)-1].inResult {
+						parent := stack[len(stack)-1]
+						parent.instruction = append(parent.instruction, segment{field: f})
+					}
+					stack = append(stack, f)
+				case "separate":
+					if len(stack) > 0 {
+						stack[len(stack)-1].inResult = true
+					}
+				case "end":
+					// Fields which began in an earlier paragraph have already been reported
+					if len(stack) == 0 {
+						continue
+					}
+					f := stack[len(stack)-1]
+					f.end = i
+					stack = stack[:len(stack)-1]
+					if len(stack) == 0 {
+						fields = append(fields, f)
+					}
+				}
+			case runChild.Is("w:instrText") && len(stack) > 0 && !stack[len(stack)-1].inResult:
+				f := stack[len(stack)-1]
+				f.instruction = append(f.instruction, segment{text: runChild.Text()})
+			case runChild.Is("w:t") && len(stack) == 1 && stack[0].inResult && stack[0].resultProperties == nil:
+				// The result shows the formatting the merged value should have, e.g. bold names
+				stack[0].resultProperties = element.Find("w:rPr")
+			}
+		}
+	}
+
+	issues := []Issue{}
+	replacements := map[*field][]xmltree.Node{}
+	for _, f := range fields {
+		if !isMailMergeField(f) {
+			continue
+		}
+
+		text, ok, reason := convertField(f)
+		if reason != "" {
+			issues = append(issues, Issue{f.code(), reason})
+		}
+		if !ok {
+			continue
+		}
+		properties := f.resultProperties
+		if properties == nil {
+			properties = f.properties
+		}
+		replacements[f] = []xmltree.Node{}
+		if text != "" {
+			replacements[f] = append(replacements[f], newRun(properties, text))
+		}
+	}
+	if len(stack) > 0 && isMailMergeField(stack[0]) {
+		issues = append(issues, Issue{stack[0].code(), "the field spans more than one paragraph so was left as it is"})
+	}
+
+	// Replace the fields from the end so the indexes of earlier ones stay the same
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		replacement, ok := replacements[f]
+		if !ok {
+			continue
+		}
+		children := append([]xmltree.Node{}, paragraph.Children[:f.begin]...)
+		children = append(children, replacement...)
+		paragraph.Children = append(children, paragraph.Children[f.end+1:]...)
+	}
+
+	return issues
+}
+
+func isMailMergeField(f *field) bool {
+	return slices.Contains(recordFields, f.keyword()) || f.hasMergeField()
+}
+
+// Create a run holding text with the formatting of the field.
+func newRun(properties *xmltree.Element, text string) *xmltree.Element {
+	run := xmltree.NewElement("w:r")
+	if properties != nil {
+		run.Children = append(run.Children, properties.Clone())
+	}
+	textElement := xmltree.NewElement("w:t", "xml:space", "preserve")
+	textElement.SetText(text)
+	run.Children = append(run.Children, textElement)
+	return run
+}
@@ -0,0 +1,86 @@
+package mergefields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const documentStart = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`
+const documentEnd = `</w:body></w:document>`
+
+// The runs of a complex field, e.g. complexField(" MERGEFIELD Name ", "«Name»").
+func complexField(instruction string, result string) string {
+	return `<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve">` + instruction + `</w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>` + result + `</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>`
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+		expectedIssues    []Issue
+	}{
+		{
+			name:              "Simple field",
+			inputXml:          `<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:fldSimple w:instr=" MERGEFIELD FirstName \* MERGEFORMAT "><w:r><w:rPr><w:i/></w:rPr><w:t>«FirstName»</w:t></w:r></w:fldSimple></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">{{ .FirstName }}</w:t></w:r></w:p>`,
+			expectedIssues:    []Issue{},
+		},
+		{
+			name:              "Complex field",
+			inputXml:          `<w:p>` + complexField(` MERGEFIELD "First Name" \* Upper `, "«First Name»") + `</w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">{{ upper (index . "First Name") }}</w:t></w:r></w:p>`,
+			expectedIssues:    []Issue{},
+		},
+		{
+			name: "IF field with nested merge fields",
+			inputXml: `<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> IF </w:instrText></w:r>` +
+				complexField(" MERGEFIELD Address2 ", "«Address2»") +
+				`<w:r><w:instrText xml:space="preserve"> &lt;&gt; "" "</w:instrText></w:r>` +
+				complexField(" MERGEFIELD Address2 ", "«Address2»") +
+				`<w:r><w:instrText xml:space="preserve">, " "" </w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>2 High Street, </w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t xml:space="preserve">{{ if .Address2 }}{{ .Address2 }}, {{ end -}}</w:t></w:r></w:p>`,
+			expectedIssues:    []Issue{},
+		},
+		{
+			name:              "NEXT fields are removed",
+			inputXml:          `<w:p><w:fldSimple w:instr=" NEXT "/><w:fldSimple w:instr=" MERGEFIELD Name "/></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t xml:space="preserve">{{ .Name }}</w:t></w:r></w:p>`,
+			expectedIssues: []Issue{
+				{"NEXT", "NEXT fields move on to the next record, which a template can't do, so it was removed. Loop over the records with range instead"},
+			},
+		},
+		{
+			name:              "Fields which can't be converted are left as they are",
+			inputXml:          `<w:p><w:fldSimple w:instr=" MERGEFIELD Total \# &quot;£#,##0&quot; "/><w:fldSimple w:instr=" PAGE "/></w:p>`,
+			expectedOutputXml: `<w:p><w:fldSimple w:instr=" MERGEFIELD Total \# &quot;£#,##0&quot; "/><w:fldSimple w:instr=" PAGE "/></w:p>`,
+			expectedIssues: []Issue{
+				{`MERGEFIELD Total \# "£#,##0"`, `the \# "£#,##0" number format has no equivalent function`},
+			},
+		},
+		{
+			name: "Fields spanning paragraphs are left as they are",
+			inputXml: `<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> IF </w:instrText></w:r>` + complexField(" MERGEFIELD Paid ", "«Paid»") +
+				`<w:r><w:instrText xml:space="preserve"> = "Yes" "Thank you</w:instrText></w:r></w:p>` +
+				`<w:p><w:r><w:instrText xml:space="preserve">" "" </w:instrText></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> IF </w:instrText></w:r>` + complexField(" MERGEFIELD Paid ", "«Paid»") +
+				`<w:r><w:instrText xml:space="preserve"> = "Yes" "Thank you</w:instrText></w:r></w:p>` +
+				`<w:p><w:r><w:instrText xml:space="preserve">" "" </w:instrText></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`,
+			expectedIssues: []Issue{
+				{`IF { MERGEFIELD Paid } = "Yes" "Thank you`, "the field spans more than one paragraph so was left as it is"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputXml, issues, err := Convert(documentStart + tt.inputXml + documentEnd)
+			require.NoError(t, err)
+			assert.Equal(t, documentStart+tt.expectedOutputXml+documentEnd, outputXml)
+			assert.Equal(t, tt.expectedIssues, issues)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package numbering
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// Markers written into the rendered XML around a loop which repeats lists and at the start of each of its
+// iterations, so the lists can be numbered from the start each time. They are private use characters so
+// they can't be confused with text in the document.
+const (
+	LoopStart      = "\ue004"
+	IterationStart = "\ue005"
+	LoopEnd        = "\ue006"
+)
+
+const markerCharacters = LoopStart + IterationStart + LoopEnd
+
+var markerRemover = strings.NewReplacer(LoopStart, "", IterationStart, "", LoopEnd, "")
+
+// Remove the markers from rendered XML without restarting any lists.
+func RemoveMarkers(xmlString string) string {
+	if !strings.ContainsAny(xmlString, markerCharacters) {
+		return xmlString
+	}
+	return markerRemover.Replace(xmlString)
+}
+
+// Give the lists in each iteration of a loop marked in rendered XML numbering instances of their own,
+// which start again from the first number, and remove the markers. Lists which continue from before
+// the loop or are repeated by nested loops without markers are numbered as they were. The numbering is
+// the root element of the numbering part, which can be nil if the document doesn't have one. Paragraphs
+// numbered by their style, e.g. List Number, are given numbering properties of their own from the
+// numbering of the style, which is given by style ID.
+func RestartRepeatedLists(nodes []xmltree.Node, numbering *xmltree.Element, styleNumbering map[string]*xmltree.Element) {
+	// The numbering instances used in the current iteration of each loop the walk is in, outermost first,
+	// by the IDs of the instances they replace
+	loops := []map[string]string{}
+
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		switch n := node.(type) {
+		case *xmltree.Text:
+			if !strings.ContainsAny(n.Data, markerCharacters) {
+				return false
+			}
+			for _, r := range n.Data {
+				switch string(r) {
+				case LoopStart:
+					loops = append(loops, map[string]string{})
+				case IterationStart:
+					if len(loops) > 0 {
+						loops[len(loops)-1] = map[string]string{}
+					}
+				case LoopEnd:
+					if len(loops) > 0 {
+						loops = loops[:len(loops)-1]
+					}
+				}
+			}
+			n.Data = markerRemover.Replace(n.Data)
+			return false
+		case *xmltree.Element:
+			if len(loops) == 0 || numbering == nil {
+				return true
+			}
+			if n.Is("w:pPr") {
+				addStyleNumbering(n, styleNumbering)
+				return true
+			}
+			if !n.Is("w:numPr") {
+				return true
+			}
+			numId := n.Find("w:numId")
+			if numId == nil {
+				return false
+			}
+			id, _ := numId.AttrValue("w:val")
+			if id == "" || id == "0" {
+				return false
+			}
+			iteration := loops[len(loops)-1]
+			newId, ok := iteration[id]
+			if !ok {
+				newId = restartNumbering(numbering, id)
+				iteration[id] = newId
+			}
+			numId.SetAttr("w:val", newId)
+			return false
+		}
+		return false
+	})
+}
+
+// The elements which come after w:numPr in paragraph properties.
+var afterNumberingProperties = []string{
+	"w:suppressLineNumbers", "w:pBdr", "w:shd", "w:tabs", "w:suppressAutoHyphens", "w:kinsoku", "w:wordWrap",
+	"w:overflowPunct", "w:topLinePunct", "w:autoSpaceDE", "w:autoSpaceDN", "w:bidi", "w:adjustRightInd",
+	"w:snapToGrid", "w:spacing", "w:ind", "w:contextualSpacing", "w:mirrorIndents", "w:suppressOverlap", "w:jc",
+	"w:textDirection", "w:textAlignment", "w:textboxTightWrap", "w:outlineLvl", "w:divId", "w:cnfStyle",
+	"w:rPr", "w:sectPr", "w:pPrChange",
+}
+
+// Copy the numbering of a paragraph's style into its properties, so the paragraph can be given a
+// numbering instance of its own. Numbering the paragraph sets itself, including a level, is kept.
+func addStyleNumbering(properties *xmltree.Element, styleNumbering map[string]*xmltree.Element) {
+	style := properties.Find("w:pStyle")
+	if style == nil {
+		return
+	}
+	styleId, _ := style.AttrValue("w:val")
+	numberingProperties, ok := styleNumbering[styleId]
+	if !ok {
+		return
+	}
+
+	existing := properties.Find("w:numPr")
+	if existing == nil {
+		properties.InsertBefore(numberingProperties.Clone(), afterNumberingProperties...)
+		return
+	}
+	if existing.Find("w:numId") == nil {
+		if numId := numberingProperties.Find("w:numId"); numId != nil {
+			existing.Children = append(existing.Children, numId.Clone())
+		}
+	}
+}
+
+// Add a copy of a numbering instance which starts each level of the list again, returning its ID.
+// The ID given is returned if there is no instance with it.
+func restartNumbering(numbering *xmltree.Element, numId string) string {
+	var num *xmltree.Element
+	maxNumId := 0
+	for _, n := range numbering.FindAll("w:num") {
+		value, _ := n.AttrValue("w:numId")
+		if value == numId {
+			num = n
+		}
+		if id, err := strconv.Atoi(value); err == nil {
+			maxNumId = max(maxNumId, id)
+		}
+	}
+	if num == nil {
+		return numId
+	}
+
+	restarted := num.Clone()
+	newId := strconv.Itoa(maxNumId + 1)
+	restarted.SetAttr("w:numId", newId)
+	for _, start := range getLevelStarts(numbering, num) {
+		override := findLevel(restarted.FindAll("w:lvlOverride"), start.level)
+		if override == nil {
+			override = xmltree.NewElement("w:lvlOverride", "w:ilvl", start.level)
+			restarted.Children = append(restarted.Children, override)
+		}
+		// A start the template already overrides is kept
+		if override.Find("w:startOverride") == nil {
+			override.Children = append([]xmltree.Node{xmltree.NewElement("w:startOverride", "w:val", start.value)}, override.Children...)
+		}
+	}
+	numbering.InsertBefore(restarted, "w:numIdMacAtCleanup")
+
+	return newId
+}
+
+// The first number of a level of a list.
+type levelStart struct {
+	level string
+	value string
+}
+
+// Get the first number of each level of the list definition a numbering instance uses. Lists whose levels
+// aren't found, such as those defined by a numbering style, start the first level from 1.
+func getLevelStarts(numbering *xmltree.Element, num *xmltree.Element) []levelStart {
+	starts := []levelStart{}
+	if abstractNumId := num.Find("w:abstractNumId"); abstractNumId != nil {
+		id, _ := abstractNumId.AttrValue("w:val")
+		for _, abstractNum := range numbering.FindAll("w:abstractNum") {
+			if value, _ := abstractNum.AttrValue("w:abstractNumId"); value != id {
+				continue
+			}
+			for _, lvl := range abstractNum.FindAll("w:lvl") {
+				level, _ := lvl.AttrValue("w:ilvl")
+				if start := lvl.Find("w:start"); start != nil {
+					value, _ := start.AttrValue("w:val")
+					starts = append(starts, levelStart{level, value})
+				}
+			}
+		}
+	}
+	if len(starts) == 0 {
+		starts = append(starts, levelStart{"0", "1"})
+	}
+	return starts
+}
+
+func findLevel(elements []*xmltree.Element, level string) *xmltree.Element {
+	for _, element := range elements {
+		if value, _ := element.AttrValue("w:ilvl"); value == level {
+			return element
+		}
+	}
+	return nil
+}
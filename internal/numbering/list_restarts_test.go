@@ -0,0 +1,105 @@
+package numbering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestRestartRepeatedLists(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	numberingNodes, err := xmltree.ParseString(`<w:numbering>` +
+		`<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:start w:val="1"/></w:lvl><w:lvl w:ilvl="1"><w:start w:val="3"/></w:lvl></w:abstractNum>` +
+		`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>` +
+		`<w:num w:numId="2"><w:abstractNumId w:val="0"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="5"/></w:lvlOverride></w:num>` +
+		`<w:numIdMacAtCleanup w:val="2"/>` +
+		`</w:numbering>`)
+	require.NoError(err)
+	numbering := xmltree.Root(numberingNodes)
+
+	item := func(numId string) string {
+		return `<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="` + numId + `"/></w:numPr></w:pPr></w:p>`
+	}
+	text := func(text string) string {
+		return `<w:p><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	nodes, err := xmltree.ParseString(`<w:body>` +
+		item("1") +
+		text(LoopStart) +
+		text(IterationStart+"Customer 1") + item("1") + item("1") + item("2") +
+		text(IterationStart+"Customer 2") + item("1") +
+		text(LoopEnd) +
+		item("1") +
+		`</w:body>`)
+	require.NoError(err)
+
+	RestartRepeatedLists(nodes, numbering, nil)
+
+	assert.Equal(`<w:body>`+
+		item("1")+
+		text("")+
+		text("Customer 1")+item("3")+item("3")+item("4")+
+		text("Customer 2")+item("5")+
+		text("")+
+		item("1")+
+		`</w:body>`, xmltree.Marshal(nodes...))
+
+	restart := `<w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride><w:lvlOverride w:ilvl="1"><w:startOverride w:val="3"/></w:lvlOverride>`
+	assert.Contains(xmltree.Marshal(numbering), `<w:num w:numId="3"><w:abstractNumId w:val="0"/>`+restart+`</w:num>`+
+		`<w:num w:numId="4"><w:abstractNumId w:val="0"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="5"/></w:lvlOverride><w:lvlOverride w:ilvl="1"><w:startOverride w:val="3"/></w:lvlOverride></w:num>`+
+		`<w:num w:numId="5"><w:abstractNumId w:val="0"/>`+restart+`</w:num>`+
+		`<w:numIdMacAtCleanup w:val="2"/>`)
+}
+
+func TestRestartRepeatedListsInNestedLoops(t *testing.T) {
+	numberingNodes, err := xmltree.ParseString(`<w:numbering><w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num></w:numbering>`)
+	require.NoError(t, err)
+
+	item := func(numId string) string {
+		return `<w:p><w:pPr><w:numPr><w:numId w:val="` + numId + `"/></w:numPr></w:pPr></w:p>`
+	}
+	nodes, err := xmltree.ParseString(`<w:body><w:p><w:r><w:t>` + LoopStart + IterationStart + `</w:t></w:r></w:p>` + item("1") +
+		`<w:p><w:r><w:t>` + LoopStart + IterationStart + `</w:t></w:r></w:p>` + item("1") + `<w:p><w:r><w:t>` + LoopEnd + `</w:t></w:r></w:p>` +
+		item("1") + `<w:p><w:r><w:t>` + LoopEnd + `</w:t></w:r></w:p></w:body>`)
+	require.NoError(t, err)
+
+	RestartRepeatedLists(nodes, xmltree.Root(numberingNodes), nil)
+
+	// The list after the nested loop carries on from the list before it in the same iteration
+	numIds := []string{}
+	for _, numId := range xmltree.FindDescendants(nodes, "w:numId") {
+		value, _ := numId.AttrValue("w:val")
+		numIds = append(numIds, value)
+	}
+	assert.Equal(t, []string{"2", "3", "2"}, numIds)
+}
+
+func TestRemoveMarkers(t *testing.T) {
+	assert.Equal(t, "<w:t>Customer 1</w:t>", RemoveMarkers("<w:t>"+LoopStart+IterationStart+"Customer 1"+LoopEnd+"</w:t>"))
+}
+
+func TestRestartRepeatedListsNumberedByStyle(t *testing.T) {
+	numberingNodes, err := xmltree.ParseString(`<w:numbering><w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num></w:numbering>`)
+	require.NoError(t, err)
+	styleNodes, err := xmltree.ParseString(`<w:numPr><w:numId w:val="1"/></w:numPr>`)
+	require.NoError(t, err)
+	styleNumbering := map[string]*xmltree.Element{"ListNumber": xmltree.Root(styleNodes)}
+
+	item := `<w:p><w:pPr><w:pStyle w:val="ListNumber"/><w:jc w:val="left"/></w:pPr></w:p>`
+	nodes, err := xmltree.ParseString(`<w:body>` + item + `<w:p><w:r><w:t>` + LoopStart + IterationStart + `</w:t></w:r></w:p>` + item +
+		`<w:p><w:r><w:t>` + IterationStart + `</w:t></w:r></w:p>` + item + `<w:p><w:r><w:t>` + LoopEnd + `</w:t></w:r></w:p></w:body>`)
+	require.NoError(t, err)
+
+	RestartRepeatedLists(nodes, xmltree.Root(numberingNodes), styleNumbering)
+
+	// Paragraphs before the loop keep the numbering of their style
+	restarted := func(numId string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="ListNumber"/><w:numPr><w:numId w:val="` + numId + `"/></w:numPr><w:jc w:val="left"/></w:pPr></w:p>`
+	}
+	empty := `<w:p><w:r><w:t></w:t></w:r></w:p>`
+	assert.Equal(t, `<w:body>`+item+empty+restarted("2")+empty+restarted("3")+empty+`</w:body>`, xmltree.Marshal(nodes...))
+}
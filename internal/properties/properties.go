@@ -0,0 +1,275 @@
+package properties
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// The properties of a document, shown in Word under File > Info and used by document management systems.
+// Empty values are left as they are in the template. Values can contain tags, which are filled in from the data.
+type Properties struct {
+	Title          string
+	Subject        string
+	Author         string
+	LastModifiedBy string
+	Keywords       string
+	Description    string
+	Category       string
+	Created        time.Time
+	Modified       time.Time
+
+	// Stored in the extended properties
+	Company string
+	Manager string
+
+	// Properties added under File > Properties > Custom. Values can be strings, numbers, booleans or dates.
+	Custom map[string]any
+}
+
+const (
+	CORE_PROPERTIES_NAMESPACE     = "http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+	EXTENDED_PROPERTIES_NAMESPACE = "http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"
+	CUSTOM_PROPERTIES_NAMESPACE   = "http://schemas.openxmlformats.org/officeDocument/2006/custom-properties"
+	VARIANT_TYPES_NAMESPACE       = "http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"
+	DUBLIN_CORE_NAMESPACE         = "http://purl.org/dc/elements/1.1/"
+	DUBLIN_CORE_TERMS_NAMESPACE   = "http://purl.org/dc/terms/"
+	XML_SCHEMA_INSTANCE_NAMESPACE = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// The XML of parts for documents which don't have them.
+const (
+	CorePropertiesXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<cp:coreProperties xmlns:cp="` + CORE_PROPERTIES_NAMESPACE + `" xmlns:dc="` + DUBLIN_CORE_NAMESPACE + `" xmlns:dcterms="` + DUBLIN_CORE_TERMS_NAMESPACE + `" ` +
+		`xmlns:dcmitype="http://purl.org/dc/dcmitype/" xmlns:xsi="` + XML_SCHEMA_INSTANCE_NAMESPACE + `"/>`
+	ExtendedPropertiesXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Properties xmlns="` + EXTENDED_PROPERTIES_NAMESPACE + `" xmlns:vt="` + VARIANT_TYPES_NAMESPACE + `"/>`
+	CustomPropertiesXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Properties xmlns="` + CUSTOM_PROPERTIES_NAMESPACE + `" xmlns:vt="` + VARIANT_TYPES_NAMESPACE + `"/>`
+)
+
+// Whether any of the extended properties are set.
+func (p *Properties) HasExtendedProperties() bool {
+	return p.Company != "" || p.Manager != ""
+}
+
+// Set the properties stored in the core properties part, e.g. docProps/core.xml.
+func SetCoreProperties(root *xmltree.Element, p *Properties) {
+	cp := getPrefix(root, CORE_PROPERTIES_NAMESPACE, "cp")
+	dc := getPrefix(root, DUBLIN_CORE_NAMESPACE, "dc")
+	dcterms := getPrefix(root, DUBLIN_CORE_TERMS_NAMESPACE, "dcterms")
+
+	setText(root, qualify(dc, "title"), p.Title)
+	setText(root, qualify(dc, "subject"), p.Subject)
+	setText(root, qualify(dc, "creator"), p.Author)
+	setText(root, qualify(cp, "lastModifiedBy"), p.LastModifiedBy)
+	setText(root, qualify(cp, "keywords"), p.Keywords)
+	setText(root, qualify(dc, "description"), p.Description)
+	setText(root, qualify(cp, "category"), p.Category)
+
+	setDate(root, dcterms, "created", p.Created)
+	setDate(root, dcterms, "modified", p.Modified)
+}
+
+// Set the properties stored in the extended properties part, e.g. docProps/app.xml.
+func SetExtendedProperties(root *xmltree.Element, p *Properties) {
+	prefix := getPrefix(root, EXTENDED_PROPERTIES_NAMESPACE, "ep")
+
+	setText(root, qualify(prefix, "Company"), p.Company)
+	setText(root, qualify(prefix, "Manager"), p.Manager)
+}
+
+// Set the properties stored in the custom properties part, e.g. docProps/custom.xml. Properties with
+// the same name are replaced.
+func SetCustomProperties(root *xmltree.Element, custom map[string]any) error {
+	prefix := getPrefix(root, CUSTOM_PROPERTIES_NAMESPACE, "op")
+	vt := getPrefix(root, VARIANT_TYPES_NAMESPACE, "vt")
+
+	maxId := 1
+	existing := map[string]*xmltree.Element{}
+	for _, property := range root.FindAll(qualify(prefix, "property")) {
+		if id, ok := property.AttrValue("pid"); ok {
+			if n, err := strconv.Atoi(id); err == nil {
+				maxId = max(maxId, n)
+			}
+		}
+		if name, ok := property.AttrValue("name"); ok {
+			existing[name] = property
+		}
+	}
+
+	// Properties are added in order of their names so documents are always the same
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		value, err := newVariant(vt, custom[name])
+		if err != nil {
+			return fmt.Errorf("custom property %q: %w", name, err)
+		}
+
+		property, ok := existing[name]
+		if !ok {
+			maxId++
+			// All custom properties share the format ID of user defined properties
+			property = xmltree.NewElement(qualify(prefix, "property"), "fmtid", "{D5CDD505-2E9C-101B-9397-08002B2CF9AE}", "pid", strconv.Itoa(maxId), "name", name)
+			root.Children = append(root.Children, property)
+		}
+		property.Children = []xmltree.Node{value}
+	}
+
+	return nil
+}
+
+// The names DOCPROPERTY fields use for the core and extended properties, by the local names of their elements.
+var fieldNames = map[string]string{
+	"title":          "Title",
+	"subject":        "Subject",
+	"creator":        "Author",
+	"lastModifiedBy": "LastSavedBy",
+	"keywords":       "Keywords",
+	"description":    "Comments",
+	"category":       "Category",
+	"Company":        "Company",
+	"Manager":        "Manager",
+}
+
+// Get the values which DOCPROPERTY fields show from the root element of a properties part, by the names
+// the fields use, e.g. Author for dc:creator. Dates are left out as Word shows them in the format of the
+// reader's locale.
+func GetFieldValues(root *xmltree.Element) map[string]string {
+	values := map[string]string{}
+	if root.Name.Local != "Properties" || getNamespace(root) != CUSTOM_PROPERTIES_NAMESPACE {
+		for _, element := range root.Elements() {
+			if name, ok := fieldNames[element.Name.Local]; ok {
+				values[name] = element.Text()
+			}
+		}
+		return values
+	}
+
+	for _, property := range root.Elements() {
+		name, ok := property.AttrValue("name")
+		if !ok || len(property.Elements()) == 0 {
+			continue
+		}
+		switch value := property.Elements()[0]; value.Name.Local {
+		case "lpwstr", "lpstr", "bstr", "i1", "i2", "i4", "i8", "int", "ui1", "ui2", "ui4", "ui8", "uint", "r4", "r8", "decimal":
+			values[name] = value.Text()
+		case "bool":
+			// Word shows yes or no properties as Y or N
+			if isTrue, _ := strconv.ParseBool(value.Text()); isTrue {
+				values[name] = "Y"
+			} else {
+				values[name] = "N"
+			}
+		}
+	}
+	return values
+}
+
+// Get the namespace of an element from the declarations on it.
+func getNamespace(element *xmltree.Element) string {
+	name := "xmlns"
+	if element.Name.Space != "" {
+		name = "xmlns:" + element.Name.Space
+	}
+	namespace, _ := element.AttrValue(name)
+	return namespace
+}
+
+// Create the element holding the value of a custom property, e.g. <vt:lpwstr>Text</vt:lpwstr>.
+func newVariant(vt string, value any) (*xmltree.Element, error) {
+	variant := func(variantType string, text string) *xmltree.Element {
+		element := xmltree.NewElement(vt + ":" + variantType)
+		element.SetText(text)
+		return element
+	}
+
+	switch v := value.(type) {
+	case string:
+		return variant("lpwstr", v), nil
+	case bool:
+		return variant("bool", strconv.FormatBool(v)), nil
+	case time.Time:
+		return variant("filetime", v.UTC().Format("2006-01-02T15:04:05Z")), nil
+	}
+
+	reflectVal := reflect.ValueOf(value)
+	switch reflectVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := reflectVal.Int(); n >= math.MinInt32 && n <= math.MaxInt32 {
+			return variant("i4", strconv.FormatInt(n, 10)), nil
+		}
+		return variant("i8", strconv.FormatInt(reflectVal.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n := reflectVal.Uint(); n <= math.MaxInt32 {
+			return variant("i4", strconv.FormatUint(n, 10)), nil
+		}
+		return variant("i8", strconv.FormatUint(reflectVal.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		return variant("r8", strconv.FormatFloat(reflectVal.Float(), 'f', -1, 64)), nil
+	case reflect.String:
+		return variant("lpwstr", reflectVal.String()), nil
+	}
+
+	return nil, fmt.Errorf("%T values can't be stored", value)
+}
+
+// Get the prefix used for a namespace on the root element, declaring it with a prefix if it isn't.
+// The prefix is empty if it is the default namespace.
+func getPrefix(root *xmltree.Element, namespace string, prefix string) string {
+	for _, attr := range root.Attr {
+		if attr.Value != namespace {
+			continue
+		}
+		if attr.Name.Space == "xmlns" {
+			return attr.Name.Local
+		}
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			return ""
+		}
+	}
+	root.SetAttr("xmlns:"+prefix, namespace)
+	return prefix
+}
+
+// Set a date in the format the core properties use. Dates which aren't set are left as they are.
+func setDate(root *xmltree.Element, dcterms string, name string, date time.Time) {
+	if date.IsZero() {
+		return
+	}
+	xsi := getPrefix(root, XML_SCHEMA_INSTANCE_NAMESPACE, "xsi")
+	element := setText(root, qualify(dcterms, name), date.UTC().Format("2006-01-02T15:04:05Z"))
+	element.SetAttr(qualify(xsi, "type"), qualify(dcterms, "W3CDTF"))
+}
+
+// Qualify a name with a prefix, unless it is in the default namespace.
+func qualify(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + ":" + name
+}
+
+// Set the text of a child element, adding it if it doesn't exist. Empty values are left as they are.
+func setText(root *xmltree.Element, name string, value string) *xmltree.Element {
+	element := root.Find(name)
+	if value == "" {
+		return element
+	}
+	if element == nil {
+		element = xmltree.NewElement(name)
+		root.Children = append(root.Children, element)
+	}
+	element.SetText(value)
+	return element
+}
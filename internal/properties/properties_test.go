@@ -0,0 +1,86 @@
+package properties
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func parseRoot(t *testing.T, xmlString string) *xmltree.Element {
+	nodes, err := xmltree.ParseString(xmlString)
+	require.NoError(t, err)
+	return xmltree.Root(nodes)
+}
+
+func TestSetCoreProperties(t *testing.T) {
+	assert := assert.New(t)
+
+	root := parseRoot(t, `<cp:coreProperties xmlns:cp="`+CORE_PROPERTIES_NAMESPACE+`" xmlns:dc="`+DUBLIN_CORE_NAMESPACE+`" xmlns:dcterms="`+DUBLIN_CORE_TERMS_NAMESPACE+`">`+
+		`<dc:title></dc:title><dc:creator>Template Author</dc:creator><cp:revision>75</cp:revision></cp:coreProperties>`)
+	SetCoreProperties(root, &Properties{
+		Title:    "Quote B-00001",
+		Keywords: "quote, sales",
+		Created:  time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+	})
+
+	assert.Equal(`<cp:coreProperties xmlns:cp="`+CORE_PROPERTIES_NAMESPACE+`" xmlns:dc="`+DUBLIN_CORE_NAMESPACE+`" xmlns:dcterms="`+DUBLIN_CORE_TERMS_NAMESPACE+`" xmlns:xsi="`+XML_SCHEMA_INSTANCE_NAMESPACE+`">`+
+		`<dc:title>Quote B-00001</dc:title><dc:creator>Template Author</dc:creator><cp:revision>75</cp:revision><cp:keywords>quote, sales</cp:keywords>`+
+		`<dcterms:created xsi:type="dcterms:W3CDTF">2024-03-01T09:30:00Z</dcterms:created></cp:coreProperties>`, xmltree.Marshal(root))
+}
+
+func TestSetExtendedProperties(t *testing.T) {
+	root := parseRoot(t, ExtendedPropertiesXml)
+	SetExtendedProperties(root, &Properties{Company: "TW Software"})
+
+	assert.Equal(t, `<Properties xmlns="`+EXTENDED_PROPERTIES_NAMESPACE+`" xmlns:vt="`+VARIANT_TYPES_NAMESPACE+`"><Company>TW Software</Company></Properties>`, xmltree.Marshal(root))
+}
+
+func TestSetCustomProperties(t *testing.T) {
+	assert := assert.New(t)
+
+	root := parseRoot(t, `<Properties xmlns="`+CUSTOM_PROPERTIES_NAMESPACE+`" xmlns:vt="`+VARIANT_TYPES_NAMESPACE+`">`+
+		`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="4" name="Client"><vt:lpwstr>Template Client</vt:lpwstr></property></Properties>`)
+	err := SetCustomProperties(root, map[string]any{
+		"Client":   "TW Software",
+		"Approved": true,
+		"Total":    1250.5,
+		"Count":    3,
+		"Due":      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	property := func(pid string, name string, value string) string {
+		return `<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="` + pid + `" name="` + name + `">` + value + `</property>`
+	}
+	assert.Equal(`<Properties xmlns="`+CUSTOM_PROPERTIES_NAMESPACE+`" xmlns:vt="`+VARIANT_TYPES_NAMESPACE+`">`+
+		property("4", "Client", "<vt:lpwstr>TW Software</vt:lpwstr>")+
+		property("5", "Approved", "<vt:bool>true</vt:bool>")+
+		property("6", "Count", "<vt:i4>3</vt:i4>")+
+		property("7", "Due", "<vt:filetime>2024-03-01T00:00:00Z</vt:filetime>")+
+		property("8", "Total", "<vt:r8>1250.5</vt:r8>")+
+		`</Properties>`, xmltree.Marshal(root))
+
+	err = SetCustomProperties(root, map[string]any{"Items": []string{"Widgets"}})
+	assert.EqualError(err, `custom property "Items": []string values can't be stored`)
+}
+
+func TestGetFieldValues(t *testing.T) {
+	assert := assert.New(t)
+
+	core := parseRoot(t, `<cp:coreProperties xmlns:cp="`+CORE_PROPERTIES_NAMESPACE+`" xmlns:dc="`+DUBLIN_CORE_NAMESPACE+`">`+
+		`<dc:title>Quote</dc:title><dc:creator>Tom</dc:creator><dc:description>Notes</dc:description><cp:revision>3</cp:revision></cp:coreProperties>`)
+	assert.Equal(map[string]string{"Title": "Quote", "Author": "Tom", "Comments": "Notes"}, GetFieldValues(core))
+
+	custom := parseRoot(t, CustomPropertiesXml)
+	err := SetCustomProperties(custom, map[string]any{
+		"Client":   "TW Software",
+		"Approved": false,
+		"Total":    12.5,
+		"Due":      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(map[string]string{"Client": "TW Software", "Approved": "N", "Total": "12.5"}, GetFieldValues(custom))
+}
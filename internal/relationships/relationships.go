@@ -0,0 +1,137 @@
+package relationships
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	OFFICE_DOCUMENT_TYPE = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	IMAGE_TYPE           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+	HYPERLINK_TYPE       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+	STYLES_TYPE          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+	SETTINGS_TYPE        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings"
+	NUMBERING_TYPE       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+	HEADER_TYPE          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header"
+	FOOTER_TYPE          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer"
+	FOOTNOTES_TYPE       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes"
+	ENDNOTES_TYPE        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/endnotes"
+	COMMENTS_TYPE        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
+
+	// Package relationships to the parts holding the properties of the document
+	CORE_PROPERTIES_TYPE     = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
+	EXTENDED_PROPERTIES_TYPE = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+	CUSTOM_PROPERTIES_TYPE   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+
+	// Custom XML parts hold data which content controls can be bound to
+	CUSTOM_XML_TYPE       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml"
+	CUSTOM_XML_PROPS_TYPE = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXmlProps"
+
+	// Parts added by newer versions of Word which hold extra details of comments
+	COMMENTS_EXTENDED_TYPE   = "http://schemas.microsoft.com/office/2011/relationships/commentsExtended"
+	COMMENTS_IDS_TYPE        = "http://schemas.microsoft.com/office/2016/09/relationships/commentsIds"
+	COMMENTS_EXTENSIBLE_TYPE = "http://schemas.microsoft.com/office/2018/08/relationships/commentsExtensible"
+)
+
+const RELATIONSHIPS_NAMESPACE = "http://schemas.openxmlformats.org/package/2006/relationships"
+
+type Relationships struct {
+	XMLName       xml.Name       `xml:"Relationships"`
+	Xmlns         string         `xml:"xmlns,attr"`
+	Relationships []Relationship `xml:"Relationship"`
+}
+
+type Relationship struct {
+	Id         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr,omitempty"`
+}
+
+func New() *Relationships {
+	return &Relationships{Xmlns: RELATIONSHIPS_NAMESPACE}
+}
+
+func Parse(data []byte) (*Relationships, error) {
+	var rels Relationships
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, err
+	}
+	if rels.Xmlns == "" {
+		rels.Xmlns = RELATIONSHIPS_NAMESPACE
+	}
+
+	return &rels, nil
+}
+
+// The name of the part holding the relationships of a part, e.g. word/_rels/document.xml.rels for word/document.xml.
+func PartName(partName string) string {
+	dir, file := path.Split(partName)
+	return dir + "_rels/" + file + ".rels"
+}
+
+// Resolve a relationship target relative to the part it belongs to, e.g. media/image1.png becomes word/media/image1.png.
+func ResolveTarget(partName string, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(partName), target)
+}
+
+// Find the first relationship of a type.
+func (r *Relationships) FindByType(relType string) *Relationship {
+	for i := range r.Relationships {
+		if r.Relationships[i].Type == relType {
+			return &r.Relationships[i]
+		}
+	}
+	return nil
+}
+
+// Find a relationship by its ID.
+func (r *Relationships) FindById(id string) *Relationship {
+	for i := range r.Relationships {
+		if r.Relationships[i].Id == id {
+			return &r.Relationships[i]
+		}
+	}
+	return nil
+}
+
+// Add a relationship and return its ID.
+func (r *Relationships) Add(relType string, target string) string {
+	return r.add(Relationship{Type: relType, Target: target})
+}
+
+// Add a relationship to an external target such as a web address and return its ID.
+func (r *Relationships) AddExternal(relType string, target string) string {
+	return r.add(Relationship{Type: relType, Target: target, TargetMode: "External"})
+}
+
+func (r *Relationships) add(relationship Relationship) string {
+	maxId := 0
+	for _, existing := range r.Relationships {
+		if n, err := strconv.Atoi(strings.TrimPrefix(existing.Id, "rId")); err == nil {
+			maxId = max(maxId, n)
+		}
+	}
+
+	relationship.Id = fmt.Sprintf("rId%d", maxId+1)
+	r.Relationships = append(r.Relationships, relationship)
+
+	return relationship.Id
+}
+
+func (r *Relationships) MarshalXml() (string, error) {
+	output, err := xml.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+
+	xmlString := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" + string(output)
+
+	return xmlString, nil
+}
@@ -0,0 +1,50 @@
+package relationships
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const documentRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings" Target="settings.xml"/><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rels, err := Parse([]byte(documentRelationships))
+	require.NoError(err)
+	assert.Len(rels.Relationships, 2)
+	assert.Equal("styles.xml", rels.FindById("rId1").Target)
+	assert.Equal("rId3", rels.FindByType("http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings").Id)
+	assert.Nil(rels.FindByType(IMAGE_TYPE))
+}
+
+func TestAdd(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rels, err := Parse([]byte(documentRelationships))
+	require.NoError(err)
+
+	assert.Equal("rId4", rels.Add(IMAGE_TYPE, "media/image1.png"))
+	assert.Equal("rId5", rels.AddExternal(HYPERLINK_TYPE, "https://example.com"))
+
+	xmlString, err := rels.MarshalXml()
+	require.NoError(err)
+	assert.Contains(xmlString, `<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"></Relationship>`)
+	assert.Contains(xmlString, `Target="https://example.com" TargetMode="External"`)
+}
+
+func TestPartName(t *testing.T) {
+	assert.Equal(t, "word/_rels/document.xml.rels", PartName("word/document.xml"))
+	assert.Equal(t, "_rels/.rels", PartName(""))
+}
+
+func TestResolveTarget(t *testing.T) {
+	assert.Equal(t, "word/media/image1.png", ResolveTarget("word/document.xml", "media/image1.png"))
+	assert.Equal(t, "customXml/item1.xml", ResolveTarget("word/document.xml", "../customXml/item1.xml"))
+	assert.Equal(t, "word/document.xml", ResolveTarget("", "/word/document.xml"))
+}
@@ -0,0 +1,200 @@
+package revisions
+
+import "github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+
+// Elements which record a change to the properties of their parent, holding the properties from before the change.
+var propertyChanges = map[string]bool{
+	"w:rPrChange":       true,
+	"w:pPrChange":       true,
+	"w:sectPrChange":    true,
+	"w:tblPrChange":     true,
+	"w:tblPrExChange":   true,
+	"w:trPrChange":      true,
+	"w:tcPrChange":      true,
+	"w:tblGridChange":   true,
+	"w:numberingChange": true,
+}
+
+// Markers which don't hold any content and can be removed once the changes are resolved.
+var changeMarkers = map[string]bool{
+	"w:moveFromRangeStart":          true,
+	"w:moveFromRangeEnd":            true,
+	"w:moveToRangeStart":            true,
+	"w:moveToRangeEnd":              true,
+	"w:customXmlInsRangeStart":      true,
+	"w:customXmlInsRangeEnd":        true,
+	"w:customXmlDelRangeStart":      true,
+	"w:customXmlDelRangeEnd":        true,
+	"w:customXmlMoveFromRangeStart": true,
+	"w:customXmlMoveFromRangeEnd":   true,
+	"w:customXmlMoveToRangeStart":   true,
+	"w:customXmlMoveToRangeEnd":     true,
+	"w:cellIns":                     true,
+	"w:cellDel":                     true,
+	"w:cellMerge":                   true,
+}
+
+type changeResolver struct {
+	accept bool
+
+	// Paragraphs whose paragraph mark is removed, so their content joins the next paragraph
+	joinedParagraphs map[*xmltree.Element]bool
+}
+
+// Accept every tracked change, keeping insertions and removing deletions, as Word's Accept All Changes does.
+func AcceptAll(nodes []xmltree.Node) []xmltree.Node {
+	r := &changeResolver{accept: true, joinedParagraphs: make(map[*xmltree.Element]bool)}
+	return r.resolve(nodes)
+}
+
+// Reject every tracked change, removing insertions and restoring deletions and previous formatting,
+// as Word's Reject All Changes does.
+func RejectAll(nodes []xmltree.Node) []xmltree.Node {
+	r := &changeResolver{accept: false, joinedParagraphs: make(map[*xmltree.Element]bool)}
+	return r.resolve(nodes)
+}
+
+func isInsertion(element *xmltree.Element) bool {
+	return element.Is("w:ins") || element.Is("w:moveTo")
+}
+
+func isDeletion(element *xmltree.Element) bool {
+	return element.Is("w:del") || element.Is("w:moveFrom")
+}
+
+// Whether the change an element records is removed when changes are resolved.
+func (r *changeResolver) isRemoved(element *xmltree.Element) bool {
+	if r.accept {
+		return isDeletion(element)
+	}
+	return isInsertion(element)
+}
+
+// Whether a paragraph's mark or a table row has a change which removes it. These are recorded in
+// the run properties of the paragraph mark and the row properties.
+func (r *changeResolver) hasRemovedMark(element *xmltree.Element, path ...string) bool {
+	for _, name := range path {
+		if element = element.Find(name); element == nil {
+			return false
+		}
+	}
+	for _, child := range element.Elements() {
+		if r.isRemoved(child) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *changeResolver) resolve(nodes []xmltree.Node) []xmltree.Node {
+	resolved := make([]xmltree.Node, 0, len(nodes))
+	for _, node := range nodes {
+		element, ok := node.(*xmltree.Element)
+		if !ok {
+			resolved = append(resolved, node)
+			continue
+		}
+
+		switch {
+		case isInsertion(element) || isDeletion(element):
+			if r.isRemoved(element) {
+				continue
+			}
+			if isDeletion(element) {
+				restoreDeletedText(element.Children)
+			}
+			resolved = append(resolved, r.resolve(element.Children)...)
+			continue
+		case propertyChanges[element.Tag()] || changeMarkers[element.Tag()]:
+			continue
+		case element.Is("w:tr") && r.hasRemovedMark(element, "w:trPr"):
+			continue
+		case element.Is("w:p") && r.hasRemovedMark(element, "w:pPr", "w:rPr"):
+			r.joinedParagraphs[element] = true
+		}
+
+		if !r.accept {
+			restorePreviousProperties(element)
+		}
+		element.Children = r.joinParagraphs(r.resolve(element.Children))
+		resolved = append(resolved, element)
+	}
+	return resolved
+}
+
+// Move the content of paragraphs whose mark has been removed into the start of the next paragraph.
+func (r *changeResolver) joinParagraphs(nodes []xmltree.Node) []xmltree.Node {
+	joined := make([]xmltree.Node, 0, len(nodes))
+	for i, node := range nodes {
+		paragraph, ok := node.(*xmltree.Element)
+		if !ok || !r.joinedParagraphs[paragraph] {
+			joined = append(joined, node)
+			continue
+		}
+
+		var next *xmltree.Element
+		for _, sibling := range nodes[i+1:] {
+			if element, ok := sibling.(*xmltree.Element); ok {
+				next = element
+				break
+			}
+		}
+		if next == nil || !next.Is("w:p") {
+			joined = append(joined, node)
+			continue
+		}
+
+		content := []xmltree.Node{}
+		for _, child := range paragraph.Children {
+			if element, ok := child.(*xmltree.Element); !ok || !element.Is("w:pPr") {
+				content = append(content, child)
+			}
+		}
+		start := 0
+		if len(next.Children) > 0 {
+			if element, ok := next.Children[0].(*xmltree.Element); ok && element.Is("w:pPr") {
+				start = 1
+			}
+		}
+		next.Children = append(next.Children[:start:start], append(content, next.Children[start:]...)...)
+	}
+	return joined
+}
+
+func restoreDeletedText(nodes []xmltree.Node) {
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		if element, ok := node.(*xmltree.Element); ok {
+			switch {
+			case element.Is("w:delText"):
+				element.Name.Local = "t"
+			case element.Is("w:delInstrText"):
+				element.Name.Local = "instrText"
+			}
+		}
+		return true
+	})
+}
+
+// Replace the properties of an element with those from before a property change, e.g. the
+// run properties from before some text was made bold.
+func restorePreviousProperties(element *xmltree.Element) {
+	change := element.Find(element.Tag() + "Change")
+	if change == nil || !propertyChanges[change.Tag()] {
+		return
+	}
+	previous := change.Elements()
+	if len(previous) == 0 {
+		return
+	}
+
+	children := previous[0].Children
+	// Changes to paragraph properties don't include the paragraph mark or section
+	if element.Is("w:pPr") {
+		for _, child := range element.Elements() {
+			if child.Is("w:rPr") || child.Is("w:sectPr") {
+				children = append(children, child)
+			}
+		}
+	}
+	element.Children = children
+}
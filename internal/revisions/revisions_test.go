@@ -0,0 +1,67 @@
+package revisions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestResolveChanges(t *testing.T) {
+	tests := []struct {
+		name             string
+		inputXml         string
+		expectedAccepted string
+		expectedRejected string
+	}{
+		{
+			name:             "Insertions and deletions",
+			inputXml:         `<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:ins w:id="1" w:author="Jane"><w:r><w:t>{{ .Name }}</w:t></w:r></w:ins><w:del w:id="2" w:author="Jane"><w:r><w:delText>{{ .FirstName }}</w:delText></w:r></w:del></w:p>`,
+			expectedAccepted: `<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:r><w:t>{{ .Name }}</w:t></w:r></w:p>`,
+			expectedRejected: `<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:r><w:t>{{ .FirstName }}</w:t></w:r></w:p>`,
+		},
+		{
+			name:             "Moves",
+			inputXml:         `<w:body><w:moveFromRangeStart w:id="1" w:name="move1"/><w:p><w:moveFrom w:id="2"><w:r><w:t>Moved</w:t></w:r></w:moveFrom></w:p><w:moveFromRangeEnd w:id="1"/><w:p><w:moveTo w:id="3"><w:r><w:t>Moved</w:t></w:r></w:moveTo></w:p></w:body>`,
+			expectedAccepted: `<w:body><w:p/><w:p><w:r><w:t>Moved</w:t></w:r></w:p></w:body>`,
+			expectedRejected: `<w:body><w:p><w:r><w:t>Moved</w:t></w:r></w:p><w:p/></w:body>`,
+		},
+		{
+			name:             "Formatting changes",
+			inputXml:         `<w:p><w:r><w:rPr><w:b/><w:rPrChange w:id="1" w:author="Jane"><w:rPr><w:i/></w:rPr></w:rPrChange></w:rPr><w:t>Text</w:t></w:r></w:p>`,
+			expectedAccepted: `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Text</w:t></w:r></w:p>`,
+			expectedRejected: `<w:p><w:r><w:rPr><w:i/></w:rPr><w:t>Text</w:t></w:r></w:p>`,
+		},
+		{
+			name:             "Paragraph formatting changes keep the paragraph mark",
+			inputXml:         `<w:p><w:pPr><w:jc w:val="center"/><w:rPr><w:b/></w:rPr><w:pPrChange w:id="1"><w:pPr><w:jc w:val="left"/></w:pPr></w:pPrChange></w:pPr></w:p>`,
+			expectedAccepted: `<w:p><w:pPr><w:jc w:val="center"/><w:rPr><w:b/></w:rPr></w:pPr></w:p>`,
+			expectedRejected: `<w:p><w:pPr><w:jc w:val="left"/><w:rPr><w:b/></w:rPr></w:pPr></w:p>`,
+		},
+		{
+			name:             "Inserted paragraph marks",
+			inputXml:         `<w:body><w:p><w:pPr><w:rPr><w:ins w:id="1"/></w:rPr></w:pPr><w:r><w:t xml:space="preserve">First </w:t></w:r></w:p><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>second</w:t></w:r></w:p></w:body>`,
+			expectedAccepted: `<w:body><w:p><w:pPr><w:rPr/></w:pPr><w:r><w:t xml:space="preserve">First </w:t></w:r></w:p><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>second</w:t></w:r></w:p></w:body>`,
+			expectedRejected: `<w:body><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t xml:space="preserve">First </w:t></w:r><w:r><w:t>second</w:t></w:r></w:p></w:body>`,
+		},
+		{
+			name:             "Table rows",
+			inputXml:         `<w:tbl><w:tr><w:trPr><w:ins w:id="1"/></w:trPr><w:tc><w:p/></w:tc></w:tr><w:tr><w:trPr><w:del w:id="2"/></w:trPr><w:tc><w:p/></w:tc></w:tr></w:tbl>`,
+			expectedAccepted: `<w:tbl><w:tr><w:trPr/><w:tc><w:p/></w:tc></w:tr></w:tbl>`,
+			expectedRejected: `<w:tbl><w:tr><w:trPr/><w:tc><w:p/></w:tc></w:tr></w:tbl>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := xmltree.ParseString(tt.inputXml)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAccepted, xmltree.Marshal(AcceptAll(nodes)...))
+
+			nodes, err = xmltree.ParseString(tt.inputXml)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRejected, xmltree.Marshal(RejectAll(nodes)...))
+		})
+	}
+}
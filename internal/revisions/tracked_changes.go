@@ -0,0 +1,267 @@
+package revisions
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// Markers written into the rendered XML around content which should be shown as a tracked change.
+// They are private use characters so they can't be confused with text in the document.
+const (
+	InsertionStart = "\ue000"
+	InsertionEnd   = "\ue001"
+	DeletionStart  = "\ue002"
+	DeletionEnd    = "\ue003"
+)
+
+const markerCharacters = InsertionStart + InsertionEnd + DeletionStart + DeletionEnd
+
+type changeType int
+
+const (
+	unchanged changeType = iota
+	inserted
+	deleted
+)
+
+type changeMarker struct {
+	author string
+	date   string
+	nextId int
+	state  changeType
+}
+
+// Turn the markers in rendered XML into tracked changes made by an author at a date. Runs between
+// InsertionStart and InsertionEnd are wrapped in <w:ins> and runs between DeletionStart and DeletionEnd
+// in <w:del>. Paragraphs which end inside a change have their paragraph mark changed too, so accepting
+// every change gives the same document as rendering without tracked changes.
+func MarkChanges(xmlString string, author string, date time.Time) (string, error) {
+	if !strings.ContainsAny(xmlString, markerCharacters) {
+		return xmlString, nil
+	}
+
+	nodes, err := xmltree.ParseString(xmlString)
+	if err != nil {
+		return "", err
+	}
+
+	m := &changeMarker{
+		author: author,
+		date:   date.UTC().Format("2006-01-02T15:04:05Z"),
+		nextId: getMaxChangeId(nodes) + 1,
+	}
+	nodes = m.markNodes(nodes)
+
+	return xmltree.Marshal(nodes...), nil
+}
+
+func getMaxChangeId(nodes []xmltree.Node) int {
+	maxId := 0
+	xmltree.Walk(nodes, func(node xmltree.Node) bool {
+		if element, ok := node.(*xmltree.Element); ok && (element.Is("w:ins") || element.Is("w:del")) {
+			if value, ok := element.AttrValue("w:id"); ok {
+				if id, err := strconv.Atoi(value); err == nil {
+					maxId = max(maxId, id)
+				}
+			}
+		}
+		return true
+	})
+	return maxId
+}
+
+// Remove the markers from text, updating the current state as each one is passed. Returns the
+// text between the markers along with the state of each piece.
+func (m *changeMarker) splitText(text string) (pieces []string, states []changeType) {
+	for {
+		i := strings.IndexAny(text, markerCharacters)
+		if i == -1 {
+			break
+		}
+		if i > 0 {
+			pieces = append(pieces, text[:i])
+			states = append(states, m.state)
+		}
+
+		marker := text[i : i+len(InsertionStart)]
+		switch marker {
+		case InsertionStart:
+			m.state = inserted
+		case DeletionStart:
+			m.state = deleted
+		default:
+			m.state = unchanged
+		}
+		text = text[i+len(marker):]
+	}
+	if text != "" {
+		pieces = append(pieces, text)
+		states = append(states, m.state)
+	}
+
+	return pieces, states
+}
+
+func (m *changeMarker) markNodes(nodes []xmltree.Node) []xmltree.Node {
+	marked := make([]xmltree.Node, 0, len(nodes))
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *xmltree.Text:
+			pieces, _ := m.splitText(n.Data)
+			if text := strings.Join(pieces, ""); text != "" {
+				marked = append(marked, &xmltree.Text{Data: text})
+			}
+		case *xmltree.Element:
+			for i := range n.Attr {
+				n.Attr[i].Value = strings.Map(removeMarker, n.Attr[i].Value)
+			}
+			if n.Is("w:r") {
+				marked = append(marked, m.markRun(n)...)
+				continue
+			}
+			n.Children = m.markNodes(n.Children)
+			if n.Is("w:p") && m.state != unchanged {
+				m.markParagraph(n)
+			}
+			marked = append(marked, n)
+		default:
+			marked = append(marked, node)
+		}
+	}
+	return marked
+}
+
+func removeMarker(r rune) rune {
+	if strings.ContainsRune(markerCharacters, r) {
+		return -1
+	}
+	return r
+}
+
+// Split a run wherever the state changes and wrap the parts which were inserted or deleted.
+func (m *changeMarker) markRun(run *xmltree.Element) []xmltree.Node {
+	var runProperties *xmltree.Element
+	type piece struct {
+		node  xmltree.Node
+		state changeType
+	}
+	pieces := []piece{}
+
+	for _, child := range run.Children {
+		element, ok := child.(*xmltree.Element)
+		switch {
+		case ok && element.Is("w:rPr"):
+			runProperties = element
+		case ok && (element.Is("w:t") || element.Is("w:instrText")):
+			if len(element.Children) == 0 {
+				pieces = append(pieces, piece{element, m.state})
+			}
+			for _, textChild := range element.Children {
+				text, ok := textChild.(*xmltree.Text)
+				if !ok {
+					// Content such as drawings output into the text by a tag belongs in the run
+					pieces = append(pieces, piece{textChild, m.state})
+					continue
+				}
+				texts, states := m.splitText(text.Data)
+				for i := range texts {
+					pieces = append(pieces, piece{newText(element, texts[i]), states[i]})
+				}
+			}
+		case !ok:
+			if text, ok := child.(*xmltree.Text); ok {
+				m.splitText(text.Data)
+			}
+		default:
+			m.markNodes(element.Children)
+			pieces = append(pieces, piece{element, m.state})
+		}
+	}
+
+	if len(pieces) == 0 {
+		return []xmltree.Node{m.wrap(run, m.state)}
+	}
+
+	nodes := []xmltree.Node{}
+	var currentRun *xmltree.Element
+	var currentState changeType
+	for _, p := range pieces {
+		if currentRun == nil || p.state != currentState {
+			currentRun = &xmltree.Element{Name: run.Name, Attr: run.Attr}
+			if runProperties != nil {
+				currentRun.Children = append(currentRun.Children, runProperties.Clone())
+			}
+			currentState = p.state
+			nodes = append(nodes, m.wrap(currentRun, currentState))
+		}
+		node := p.node
+		if element, ok := node.(*xmltree.Element); ok && currentState == deleted {
+			switch {
+			case element.Is("w:t"):
+				element.Name.Local = "delText"
+			case element.Is("w:instrText"):
+				element.Name.Local = "delInstrText"
+			}
+		}
+		currentRun.Children = append(currentRun.Children, node)
+	}
+
+	return nodes
+}
+
+// Create a text element with the same name and attributes as another.
+func newText(element *xmltree.Element, text string) *xmltree.Element {
+	newElement := &xmltree.Element{Name: element.Name, Attr: append(element.Attr[:0:0], element.Attr...)}
+	newElement.SetText(text)
+	if strings.TrimSpace(text) != text {
+		newElement.SetAttr("xml:space", "preserve")
+	}
+	return newElement
+}
+
+func (m *changeMarker) newChange(state changeType) *xmltree.Element {
+	name := "w:ins"
+	if state == deleted {
+		name = "w:del"
+	}
+	change := xmltree.NewElement(name, "w:id", strconv.Itoa(m.nextId), "w:author", m.author, "w:date", m.date)
+	m.nextId++
+	return change
+}
+
+func (m *changeMarker) wrap(run *xmltree.Element, state changeType) xmltree.Node {
+	if state == unchanged {
+		return run
+	}
+	change := m.newChange(state)
+	change.Children = []xmltree.Node{run}
+	return change
+}
+
+// Mark the paragraph mark as inserted or deleted.
+func (m *changeMarker) markParagraph(paragraph *xmltree.Element) {
+	paragraphProperties := paragraph.Find("w:pPr")
+	if paragraphProperties == nil {
+		paragraphProperties = xmltree.NewElement("w:pPr")
+		paragraph.Children = append([]xmltree.Node{paragraphProperties}, paragraph.Children...)
+	}
+
+	runProperties := paragraphProperties.Find("w:rPr")
+	if runProperties == nil {
+		runProperties = xmltree.NewElement("w:rPr")
+		// The run properties come before any section properties or property changes
+		i := len(paragraphProperties.Children)
+		for j, child := range paragraphProperties.Children {
+			if element, ok := child.(*xmltree.Element); ok && (element.Is("w:sectPr") || element.Is("w:pPrChange")) {
+				i = j
+				break
+			}
+		}
+		paragraphProperties.Children = append(paragraphProperties.Children[:i:i], append([]xmltree.Node{runProperties}, paragraphProperties.Children[i:]...)...)
+	}
+
+	runProperties.Children = append(runProperties.Children, m.newChange(m.state))
+}
@@ -0,0 +1,68 @@
+package revisions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkChanges(t *testing.T) {
+	date := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:              "No changes",
+			inputXml:          `<w:p><w:r><w:t>Hello</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>Hello</w:t></w:r></w:p>`,
+		},
+		{
+			name:              "Insertion in the middle of a run",
+			inputXml:          `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Dear ` + InsertionStart + `Tom` + InsertionEnd + `,</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Dear </w:t></w:r><w:ins w:id="1" w:author="Generator" w:date="2024-03-01T09:30:00Z"><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Tom</w:t></w:r></w:ins><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">,</w:t></w:r></w:p>`,
+		},
+		{
+			name:              "Empty insertions are left out",
+			inputXml:          `<w:p><w:r><w:t>Dear ` + InsertionStart + InsertionEnd + `Sir</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t xml:space="preserve">Dear </w:t><w:t>Sir</w:t></w:r></w:p>`,
+		},
+		{
+			name:              "Deletion uses deleted text",
+			inputXml:          `<w:p><w:r><w:t>Due` + DeletionStart + ` on receipt` + DeletionEnd + `</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>Due</w:t></w:r><w:del w:id="1" w:author="Generator" w:date="2024-03-01T09:30:00Z"><w:r><w:delText xml:space="preserve"> on receipt</w:delText></w:r></w:del></w:p>`,
+		},
+		{
+			name:              "Drawings output into text are moved into the run",
+			inputXml:          `<w:p><w:r><w:t>` + InsertionStart + `<w:drawing/>` + InsertionEnd + `</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:ins w:id="1" w:author="Generator" w:date="2024-03-01T09:30:00Z"><w:r><w:drawing/></w:r></w:ins></w:p>`,
+		},
+		{
+			name:              "Deleted paragraphs have their paragraph mark deleted",
+			inputXml:          `<w:body><w:p><w:r><w:t>Intro` + DeletionStart + `</w:t></w:r></w:p><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>Optional` + DeletionEnd + `</w:t></w:r></w:p></w:body>`,
+			expectedOutputXml: `<w:body><w:p><w:pPr><w:rPr><w:del w:id="1" w:author="Generator" w:date="2024-03-01T09:30:00Z"/></w:rPr></w:pPr><w:r><w:t>Intro</w:t></w:r></w:p><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:del w:id="2" w:author="Generator" w:date="2024-03-01T09:30:00Z"><w:r><w:delText>Optional</w:delText></w:r></w:del></w:p></w:body>`,
+		},
+		{
+			name:              "IDs follow existing changes",
+			inputXml:          `<w:p><w:ins w:id="7" w:author="Jane"><w:r><w:t>Hi</w:t></w:r></w:ins><w:r><w:t>` + InsertionStart + `Tom` + InsertionEnd + `</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:ins w:id="7" w:author="Jane"><w:r><w:t>Hi</w:t></w:r></w:ins><w:ins w:id="8" w:author="Generator" w:date="2024-03-01T09:30:00Z"><w:r><w:t>Tom</w:t></w:r></w:ins></w:p>`,
+		},
+		{
+			name:              "Markers in attributes are removed",
+			inputXml:          `<w:p><w:hyperlink w:tooltip="` + InsertionStart + `Tip` + InsertionEnd + `"><w:r><w:t>Link</w:t></w:r></w:hyperlink></w:p>`,
+			expectedOutputXml: `<w:p><w:hyperlink w:tooltip="Tip"><w:r><w:t>Link</w:t></w:r></w:hyperlink></w:p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputXml, err := MarkChanges(tt.inputXml, "Generator", date)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOutputXml, outputXml)
+		})
+	}
+}
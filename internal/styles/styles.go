@@ -0,0 +1,135 @@
+package styles
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+// The styles defined in a document, e.g. in word/styles.xml.
+type Styles struct {
+	byId map[string]*xmltree.Element
+	// Names are in lower case, as Word stores the names of built-in styles in lower case, e.g. "heading 1"
+	byName map[string]*xmltree.Element
+}
+
+// Read the styles from the root element of a styles part.
+func New(root *xmltree.Element) *Styles {
+	s := &Styles{byId: map[string]*xmltree.Element{}, byName: map[string]*xmltree.Element{}}
+	if root == nil {
+		return s
+	}
+	for _, style := range root.FindAll("w:style") {
+		id, ok := style.AttrValue("w:styleId")
+		if !ok {
+			continue
+		}
+		s.byId[id] = style
+		if name := style.Find("w:name"); name != nil {
+			if value, ok := name.AttrValue("w:val"); ok {
+				s.byName[strings.ToLower(value)] = style
+			}
+		}
+	}
+	return s
+}
+
+// Find a style by its ID or name, e.g. "Heading2" or "Heading 2", returning its ID and type such as
+// paragraph or character. Names are matched ignoring case.
+func (s *Styles) Find(idOrName string) (styleId string, styleType string, ok bool) {
+	style, ok := s.byId[idOrName]
+	if !ok {
+		style, ok = s.byName[strings.ToLower(idOrName)]
+	}
+	if !ok {
+		return "", "", false
+	}
+	styleId, _ = style.AttrValue("w:styleId")
+	styleType, _ = style.AttrValue("w:type")
+	return styleId, styleType, true
+}
+
+// Get the outline level of a paragraph style, from 1 for Heading 1 to 9, following the styles it is based on.
+// Styles for body text have no outline level.
+func (s *Styles) OutlineLevel(styleId string) (int, bool) {
+	seen := map[string]bool{}
+	for styleId != "" && !seen[styleId] {
+		seen[styleId] = true
+		style, ok := s.byId[styleId]
+		if !ok {
+			return 0, false
+		}
+		if properties := style.Find("w:pPr"); properties != nil {
+			if level, ok := ParseOutlineLevel(properties); ok {
+				return level, true
+			} else if properties.Find("w:outlineLvl") != nil {
+				return 0, false
+			}
+		}
+		basedOn := style.Find("w:basedOn")
+		if basedOn == nil {
+			break
+		}
+		styleId, _ = basedOn.AttrValue("w:val")
+	}
+	return 0, false
+}
+
+// Get the numbering properties of a paragraph style, following the styles it is based on, e.g. those of
+// the List Number style. Styles which aren't numbered, or which turn numbering off, have none.
+func (s *Styles) Numbering(styleId string) (*xmltree.Element, bool) {
+	seen := map[string]bool{}
+	for styleId != "" && !seen[styleId] {
+		seen[styleId] = true
+		style, ok := s.byId[styleId]
+		if !ok {
+			return nil, false
+		}
+		if properties := style.Find("w:pPr"); properties != nil {
+			if numbering := properties.Find("w:numPr"); numbering != nil {
+				numId := numbering.Find("w:numId")
+				if numId == nil {
+					return nil, false
+				}
+				value, _ := numId.AttrValue("w:val")
+				return numbering, value != "" && value != "0"
+			}
+		}
+		basedOn := style.Find("w:basedOn")
+		if basedOn == nil {
+			break
+		}
+		styleId, _ = basedOn.AttrValue("w:val")
+	}
+	return nil, false
+}
+
+// Get the numbering properties of each paragraph style which numbers its paragraphs, by style ID.
+func (s *Styles) NumberedStyles() map[string]*xmltree.Element {
+	numbered := map[string]*xmltree.Element{}
+	for styleId, style := range s.byId {
+		if styleType, _ := style.AttrValue("w:type"); styleType != "paragraph" {
+			continue
+		}
+		if numbering, ok := s.Numbering(styleId); ok {
+			numbered[styleId] = numbering
+		}
+	}
+	return numbered
+}
+
+// Get the outline level set in paragraph properties, from 1 to 9. Level 10 in Word, which is stored as 9,
+// is body text so has no outline level.
+func ParseOutlineLevel(properties *xmltree.Element) (int, bool) {
+	outlineLevel := properties.Find("w:outlineLvl")
+	if outlineLevel == nil {
+		return 0, false
+	}
+	value, _ := outlineLevel.AttrValue("w:val")
+	level, err := strconv.Atoi(value)
+	if err != nil || level < 0 || level > 8 {
+		return 0, false
+	}
+	return level + 1, true
+}
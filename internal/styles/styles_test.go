@@ -0,0 +1,114 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomwatkins1994/go-docx-template/internal/xmltree"
+)
+
+func TestOutlineLevel(t *testing.T) {
+	nodes, err := xmltree.ParseString(`<w:styles>` +
+		`<w:style w:type="paragraph" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:pPr><w:outlineLvl w:val="0"/></w:pPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="ChapterTitle"><w:name w:val="Chapter Title"/><w:basedOn w:val="Heading1"/><w:pPr><w:jc w:val="center"/></w:pPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Quiet"><w:name w:val="Quiet"/><w:basedOn w:val="Heading1"/><w:pPr><w:outlineLvl w:val="9"/></w:pPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Loop"><w:name w:val="Loop"/><w:basedOn w:val="Loop"/></w:style>` +
+		`</w:styles>`)
+	require.NoError(t, err)
+	s := New(xmltree.Root(nodes))
+
+	tests := []struct {
+		styleId       string
+		expectedLevel int
+		expectedOk    bool
+	}{
+		{"Heading1", 1, true},
+		{"ChapterTitle", 1, true},
+		{"Normal", 0, false},
+		{"Quiet", 0, false},
+		{"Loop", 0, false},
+		{"Missing", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.styleId, func(t *testing.T) {
+			level, ok := s.OutlineLevel(tt.styleId)
+			assert.Equal(t, tt.expectedLevel, level)
+			assert.Equal(t, tt.expectedOk, ok)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	nodes, err := xmltree.ParseString(`<w:styles>` +
+		`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/></w:style>` +
+		`<w:style w:type="character" w:styleId="Strong"><w:name w:val="Strong"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Quote1"><w:name w:val="Quote"/></w:style>` +
+		`</w:styles>`)
+	require.NoError(t, err)
+	s := New(xmltree.Root(nodes))
+
+	tests := []struct {
+		idOrName     string
+		expectedId   string
+		expectedType string
+		expectedOk   bool
+	}{
+		{"Heading2", "Heading2", "paragraph", true},
+		{"Heading 2", "Heading2", "paragraph", true},
+		{"strong", "Strong", "character", true},
+		{"Quote", "Quote1", "paragraph", true},
+		{"Heading 3", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.idOrName, func(t *testing.T) {
+			styleId, styleType, ok := s.Find(tt.idOrName)
+			assert.Equal(t, tt.expectedId, styleId)
+			assert.Equal(t, tt.expectedType, styleType)
+			assert.Equal(t, tt.expectedOk, ok)
+		})
+	}
+}
+
+func TestNumbering(t *testing.T) {
+	nodes, err := xmltree.ParseString(`<w:styles>` +
+		`<w:style w:type="paragraph" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="ListNumber"><w:name w:val="List Number"/><w:basedOn w:val="Normal"/><w:pPr><w:numPr><w:numId w:val="3"/></w:numPr></w:pPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Steps"><w:name w:val="Steps"/><w:basedOn w:val="ListNumber"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Unnumbered"><w:name w:val="Unnumbered"/><w:basedOn w:val="ListNumber"/><w:pPr><w:numPr><w:numId w:val="0"/></w:numPr></w:pPr></w:style>` +
+		`<w:style w:type="numbering" w:styleId="Outline"><w:name w:val="Outline"/><w:pPr><w:numPr><w:numId w:val="4"/></w:numPr></w:pPr></w:style>` +
+		`</w:styles>`)
+	require.NoError(t, err)
+	s := New(xmltree.Root(nodes))
+
+	tests := []struct {
+		styleId       string
+		expectedNumId string
+		expectedOk    bool
+	}{
+		{"ListNumber", "3", true},
+		{"Steps", "3", true},
+		{"Unnumbered", "", false},
+		{"Normal", "", false},
+		{"Missing", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.styleId, func(t *testing.T) {
+			numbering, ok := s.Numbering(tt.styleId)
+			assert.Equal(t, tt.expectedOk, ok)
+			if ok {
+				numId, _ := numbering.Find("w:numId").AttrValue("w:val")
+				assert.Equal(t, tt.expectedNumId, numId)
+			}
+		})
+	}
+
+	numbered := s.NumberedStyles()
+	assert.Len(t, numbered, 2)
+	assert.Contains(t, numbered, "ListNumber")
+	assert.Contains(t, numbered, "Steps")
+}
@@ -0,0 +1,99 @@
+package tags
+
+import (
+	"text/template/parse"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/revisions"
+)
+
+// Rewrite a template so the output of each action is marked as inserted, and the content of each
+// branch of an if or with which isn't taken is marked as deleted. This
+//
+//	{{if .Paid}}Paid on {{.Date}}{{else}}Due{{end}}
+//
+// is executed as
+//
+//	{{if .Paid}}Paid on [ins]{{.Date}}[/ins][del]Due[/del]{{else}}[del]Paid on [/del]Due{{end}}
+//
+// Only the text of a deleted branch is kept as its actions can't be evaluated.
+func addChangeMarkers(tree *parse.Tree) {
+	if tree == nil || tree.Root == nil {
+		return
+	}
+
+	var addToList func(list *parse.ListNode)
+	addToBranch := func(branch *parse.BranchNode) {
+		listText := getTemplateText(branch.List)
+		elseText := getTemplateText(branch.ElseList)
+		addToList(branch.List)
+		addToList(branch.ElseList)
+
+		if elseText != "" {
+			branch.List.Nodes = append(branch.List.Nodes, newTextNode(branch.Pos, revisions.DeletionStart+elseText+revisions.DeletionEnd))
+		}
+		if listText != "" {
+			if branch.ElseList == nil {
+				branch.ElseList = &parse.ListNode{NodeType: parse.NodeList, Pos: branch.Pos}
+			}
+			branch.ElseList.Nodes = append(
+				[]parse.Node{newTextNode(branch.Pos, revisions.DeletionStart+listText+revisions.DeletionEnd)},
+				branch.ElseList.Nodes...,
+			)
+		}
+	}
+	addToList = func(list *parse.ListNode) {
+		if list == nil {
+			return
+		}
+
+		nodes := make([]parse.Node, 0, len(list.Nodes))
+		for _, node := range list.Nodes {
+			switch n := node.(type) {
+			case *parse.ActionNode:
+				// Actions which declare or assign variables don't output anything
+				if len(n.Pipe.Decl) == 0 {
+					nodes = append(nodes, newTextNode(n.Pos, revisions.InsertionStart), n, newTextNode(n.Pos, revisions.InsertionEnd))
+					continue
+				}
+			case *parse.IfNode:
+				addToBranch(&n.BranchNode)
+			case *parse.WithNode:
+				addToBranch(&n.BranchNode)
+			case *parse.RangeNode:
+				addToList(n.List)
+				addToList(n.ElseList)
+			}
+			nodes = append(nodes, node)
+		}
+		list.Nodes = nodes
+	}
+
+	addToList(tree.Root)
+}
+
+// Get the text of a list and everything nested inside it, leaving out actions. Only the first branch
+// of nested conditions and a single iteration of nested ranges are included.
+func getTemplateText(list *parse.ListNode) string {
+	if list == nil {
+		return ""
+	}
+
+	text := ""
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			text += string(n.Text)
+		case *parse.IfNode:
+			text += getTemplateText(n.List)
+		case *parse.WithNode:
+			text += getTemplateText(n.List)
+		case *parse.RangeNode:
+			text += getTemplateText(n.List)
+		}
+	}
+	return text
+}
+
+func newTextNode(pos parse.Pos, text string) *parse.TextNode {
+	return &parse.TextNode{NodeType: parse.NodeText, Pos: pos, Text: []byte(text)}
+}
@@ -0,0 +1,67 @@
+package tags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomwatkins1994/go-docx-template/internal/functions"
+	"github.com/tomwatkins1994/go-docx-template/internal/revisions"
+)
+
+func TestReplaceTagsInXmlWithChanges(t *testing.T) {
+	tests := []struct {
+		name           string
+		template       string
+		expectedResult string
+	}{
+		{
+			name:           "Values are inserted",
+			template:       "<w:t>Dear {{ .Name }},</w:t>",
+			expectedResult: "<w:t>Dear [ins]Tom[/ins],</w:t>",
+		},
+		{
+			name:           "Variable declarations aren't marked",
+			template:       "<w:t>{{ $name := .Name }}{{ $name }}</w:t>",
+			expectedResult: "<w:t>[ins]Tom[/ins]</w:t>",
+		},
+		{
+			name:           "The else branch is deleted when the condition is met",
+			template:       "<w:t>{{ if .Paid }}Paid{{ else }}Due{{ end }}</w:t>",
+			expectedResult: "<w:t>Paid[del]Due[/del]</w:t>",
+		},
+		{
+			name:           "Conditions which aren't met are deleted",
+			template:       "<w:t>{{ if not .Paid }}Due on {{ .Date }}{{ end }}</w:t>",
+			expectedResult: "<w:t>[del]Due on [/del]</w:t>",
+		},
+		{
+			name:           "Only the first branch of nested conditions is deleted",
+			template:       "<w:t>Dear {{ with .Title }}{{ if .Formal }}Sir{{ else }}friend{{ end }}{{ else }}{{ .Name }}{{ end }}</w:t>",
+			expectedResult: "<w:t>Dear [del]Sir[/del][ins]Tom[/ins]</w:t>",
+		},
+		{
+			name:           "Values in loops are inserted",
+			template:       "<w:t>{{ range .Items }}{{ . }};{{ end }}</w:t>",
+			expectedResult: "<w:t>[ins]A[/ins];[ins]B[/ins];</w:t>",
+		},
+	}
+
+	readableMarkers := strings.NewReplacer(
+		revisions.InsertionStart, "[ins]",
+		revisions.InsertionEnd, "[/ins]",
+		revisions.DeletionStart, "[del]",
+		revisions.DeletionEnd, "[/del]",
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			data := map[string]any{"Name": "Tom", "Paid": true, "Date": "1 March", "Items": []string{"A", "B"}}
+			result, err := ReplaceTagsInXmlWithChanges(tt.template, data, functions.DefaultFuncMap, DefaultDelimiters)
+			assert.NoError(err)
+			assert.Equal(tt.expectedResult, readableMarkers.Replace(result))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tags
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"text/template/parse"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/numbering"
+)
+
+var paragraphRegex = regexp.MustCompile(`(?s)<w:p(?:\s[^>]*)?>.*?</w:p>`)
+
+// Mark the ranges in a template which repeat numbered lists along with other paragraphs, such as a
+// heading and a list for each customer, so each iteration can number its lists from the start. This
+//
+//	{{range .Customers}}...{{end}}
+//
+// is executed as
+//
+//	[loop]{{range .Customers}}[iteration]...{{end}}[/loop]
+//
+// Ranges which only repeat the items of a list, and so build a single list, aren't marked. Paragraphs are
+// numbered by numbering properties of their own or by one of the numbered styles given.
+func addListRestartMarkers(tree *parse.Tree, numberedStyles []string) {
+	if tree == nil || tree.Root == nil {
+		return
+	}
+
+	var addToList func(list *parse.ListNode)
+	addToList = func(list *parse.ListNode) {
+		if list == nil {
+			return
+		}
+
+		nodes := make([]parse.Node, 0, len(list.Nodes))
+		for _, node := range list.Nodes {
+			switch n := node.(type) {
+			case *parse.IfNode:
+				addToList(n.List)
+				addToList(n.ElseList)
+			case *parse.WithNode:
+				addToList(n.List)
+				addToList(n.ElseList)
+			case *parse.RangeNode:
+				addToList(n.List)
+				addToList(n.ElseList)
+				if repeatsLists(getTemplateText(n.List), numberedStyles) {
+					n.List.Nodes = append([]parse.Node{newTextNode(n.Pos, numbering.IterationStart)}, n.List.Nodes...)
+					nodes = append(nodes, newTextNode(n.Pos, numbering.LoopStart), n, newTextNode(n.Pos, numbering.LoopEnd))
+					continue
+				}
+			}
+			nodes = append(nodes, node)
+		}
+		list.Nodes = nodes
+	}
+
+	addToList(tree.Root)
+}
+
+// Whether the text of a range has whole paragraphs which are numbered and others which aren't.
+func repeatsLists(text string, numberedStyles []string) bool {
+	numbered, other := false, false
+	for _, paragraph := range paragraphRegex.FindAllString(text, -1) {
+		if isNumberedParagraph(paragraph, numberedStyles) {
+			numbered = true
+		} else {
+			other = true
+		}
+	}
+	return numbered && other
+}
+
+var paragraphStyleRegex = regexp.MustCompile(`<w:pStyle w:val="([^"]*)"`)
+
+func isNumberedParagraph(paragraph string, numberedStyles []string) bool {
+	if strings.Contains(paragraph, "<w:numPr>") {
+		return true
+	}
+	if match := paragraphStyleRegex.FindStringSubmatch(paragraph); match != nil {
+		return slices.Contains(numberedStyles, match[1])
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package tags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomwatkins1994/go-docx-template/internal/functions"
+	"github.com/tomwatkins1994/go-docx-template/internal/numbering"
+)
+
+func TestAddListRestartMarkers(t *testing.T) {
+	item := `<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>{{ . }}</w:t></w:r></w:p>`
+	styledItem := `<w:p><w:pPr><w:pStyle w:val="ListNumber"/></w:pPr><w:r><w:t>{{ .Name }}</w:t></w:r></w:p>`
+
+	tests := []struct {
+		name           string
+		template       string
+		expectedResult string
+	}{
+		{
+			name:     "Loops repeating lists with other paragraphs are marked",
+			template: `<w:p><w:r><w:t>{{ range .Customers }}</w:t></w:r></w:p><w:p><w:r><w:t>{{ .Name }}</w:t></w:r></w:p><w:p><w:r><w:t>{{ range .Items }}</w:t></w:r></w:p>` + item + `<w:p><w:r><w:t>{{ end }}{{ end }}</w:t></w:r></w:p>`,
+			expectedResult: `<w:p><w:r><w:t>[loop][iteration]</w:t></w:r></w:p><w:p><w:r><w:t>Acme</w:t></w:r></w:p><w:p><w:r><w:t></w:t></w:r></w:p>` + strings.ReplaceAll(item, "{{ . }}", "A") +
+				`<w:p><w:r><w:t></w:t></w:r></w:p>` + strings.ReplaceAll(item, "{{ . }}", "B") + `<w:p><w:r><w:t>[/loop]</w:t></w:r></w:p>`,
+		},
+		{
+			name:           "Loops only repeating list items aren't marked",
+			template:       `<w:p><w:r><w:t>{{ range .Items }}</w:t></w:r></w:p>` + item + `<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>`,
+			expectedResult: `<w:p><w:r><w:t></w:t></w:r></w:p>` + strings.ReplaceAll(item, "{{ . }}", "A") + `<w:p><w:r><w:t></w:t></w:r></w:p>` + strings.ReplaceAll(item, "{{ . }}", "B") + `<w:p><w:r><w:t></w:t></w:r></w:p>`,
+		},
+		{
+			name:     "Loops repeating lists numbered by their style with other paragraphs are marked",
+			template: `<w:p><w:r><w:t>{{ range .Customers }}</w:t></w:r></w:p><w:p><w:r><w:t>{{ .Name }}</w:t></w:r></w:p>` + styledItem + `<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>`,
+			expectedResult: `<w:p><w:r><w:t>[loop][iteration]</w:t></w:r></w:p><w:p><w:r><w:t>Acme</w:t></w:r></w:p>` + strings.ReplaceAll(styledItem, "{{ .Name }}", "Acme") +
+				`<w:p><w:r><w:t>[/loop]</w:t></w:r></w:p>`,
+		},
+		{
+			name:           "Loops without lists aren't marked",
+			template:       `<w:p><w:r><w:t>{{ range .Items }}</w:t></w:r></w:p><w:p><w:r><w:t>{{ . }}</w:t></w:r></w:p><w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>`,
+			expectedResult: `<w:p><w:r><w:t></w:t></w:r></w:p><w:p><w:r><w:t>A</w:t></w:r></w:p><w:p><w:r><w:t></w:t></w:r></w:p><w:p><w:r><w:t>B</w:t></w:r></w:p><w:p><w:r><w:t></w:t></w:r></w:p>`,
+		},
+	}
+
+	readableMarkers := strings.NewReplacer(
+		numbering.LoopStart, "[loop]",
+		numbering.IterationStart, "[iteration]",
+		numbering.LoopEnd, "[/loop]",
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]any{"Customers": []map[string]any{{"Name": "Acme", "Items": []string{"A", "B"}}}, "Items": []string{"A", "B"}}
+			result, err := ReplaceTagsInXml(tt.template, data, functions.DefaultFuncMap, DefaultDelimiters, "ListNumber")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, readableMarkers.Replace(result))
+		})
+	}
+}
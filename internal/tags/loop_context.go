@@ -0,0 +1,185 @@
+package tags
+
+import (
+	"fmt"
+	"reflect"
+	"text/template"
+	"text/template/parse"
+)
+
+// The action which declares $loop outside of any range so templates using it can be parsed.
+// Inside each range it is redeclared with the context of that loop.
+const loopVariableDeclaration = "$loop := noLoop"
+
+var loopFuncMap = template.FuncMap{
+	"noLoop":      func() *loop { return nil },
+	"loopContext": newLoop,
+}
+
+// The context of a range, available in templates as $loop.
+//
+//	{{range .Items}}{{$loop.Index}}. {{.Name}}{{if not $loop.Last}}, {{end}}{{end}}
+type loop struct {
+	items  any
+	index0 int
+	length int
+	parent *loop
+}
+
+func newLoop(items any, parent ...*loop) *loop {
+	l := &loop{items: items, index0: -1, length: -1}
+	if len(parent) > 0 {
+		l.parent = parent[0]
+	}
+
+	value := reflect.ValueOf(items)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		l.length = value.Len()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		l.length = int(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		l.length = int(value.Uint())
+	}
+
+	return l
+}
+
+// The value being ranged over.
+func (l *loop) Items() any {
+	return l.items
+}
+
+// Move on to the next iteration.
+func (l *loop) Next() *loop {
+	l.index0++
+	return l
+}
+
+// The current iteration starting from 1.
+func (l *loop) Index() int {
+	return l.index0 + 1
+}
+
+// The current iteration starting from 0.
+func (l *loop) Index0() int {
+	return l.index0
+}
+
+// The number of iterations remaining including the current one, so it is 1 on the last iteration.
+func (l *loop) RevIndex() int {
+	return l.length - l.index0
+}
+
+func (l *loop) First() bool {
+	return l.index0 == 0
+}
+
+// Always false when ranging over a channel or an iterator as their length isn't known.
+func (l *loop) Last() bool {
+	return l.length >= 0 && l.index0 == l.length-1
+}
+
+// The number of items being ranged over, or -1 for channels and iterators.
+func (l *loop) Length() int {
+	return l.length
+}
+
+// Whether the 1-based index is odd, so the first iteration is odd.
+func (l *loop) Odd() bool {
+	return l.Index()%2 == 1
+}
+
+func (l *loop) Even() bool {
+	return l.Index()%2 == 0
+}
+
+// The context of the enclosing range, or nil in the outermost range.
+func (l *loop) Parent() *loop {
+	return l.parent
+}
+
+// Rewrite each range in a template so $loop holds its context. This
+//
+//	{{range .Items}}...{{end}}
+//
+// is executed as
+//
+//	{{$__loop1 := loopContext (.Items) $loop}}{{range $__loop1.Items}}{{$loop := $__loop1.Next}}...{{end}}
+//
+// so the pipeline is only evaluated once and anything declared by the range is left as it is.
+// The parent is only passed to ranges nested inside another range.
+func addLoopContext(tree *parse.Tree) {
+	if tree == nil || tree.Root == nil {
+		return
+	}
+
+	loopCount := 0
+	var addToList func(list *parse.ListNode, nested bool)
+	addToList = func(list *parse.ListNode, nested bool) {
+		if list == nil {
+			return
+		}
+
+		nodes := make([]parse.Node, 0, len(list.Nodes))
+		for _, node := range list.Nodes {
+			switch n := node.(type) {
+			case *parse.IfNode:
+				addToList(n.List, nested)
+				addToList(n.ElseList, nested)
+			case *parse.WithNode:
+				addToList(n.List, nested)
+				addToList(n.ElseList, nested)
+			case *parse.RangeNode:
+				loopCount++
+				loopVariable := fmt.Sprintf("$__loop%d", loopCount)
+
+				contextArgs := []parse.Node{
+					parse.NewIdentifier("loopContext").SetPos(n.Pipe.Pos),
+					&parse.PipeNode{NodeType: parse.NodePipe, Pos: n.Pipe.Pos, Line: n.Pipe.Line, Cmds: n.Pipe.Cmds},
+				}
+				if nested {
+					contextArgs = append(contextArgs, newVariableNode(n.Pipe.Pos, "$loop"))
+				}
+				nodes = append(nodes, newDeclarationNode(n.Pipe.Pos, loopVariable, contextArgs...))
+
+				n.Pipe.Cmds = []*parse.CommandNode{newCommandNode(n.Pipe.Pos, newVariableNode(n.Pipe.Pos, loopVariable, "Items"))}
+				addToList(n.List, true)
+				addToList(n.ElseList, nested)
+				n.List.Nodes = append(
+					[]parse.Node{newDeclarationNode(n.Pipe.Pos, "$loop", newVariableNode(n.Pipe.Pos, loopVariable, "Next"))},
+					n.List.Nodes...,
+				)
+			}
+			nodes = append(nodes, node)
+		}
+		list.Nodes = nodes
+	}
+
+	addToList(tree.Root, false)
+}
+
+func newVariableNode(pos parse.Pos, ident ...string) *parse.VariableNode {
+	return &parse.VariableNode{NodeType: parse.NodeVariable, Pos: pos, Ident: ident}
+}
+
+func newCommandNode(pos parse.Pos, args ...parse.Node) *parse.CommandNode {
+	return &parse.CommandNode{NodeType: parse.NodeCommand, Pos: pos, Args: args}
+}
+
+// Create the equivalent of {{$variable := args...}}
+func newDeclarationNode(pos parse.Pos, variable string, args ...parse.Node) *parse.ActionNode {
+	return &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      pos,
+		Pipe: &parse.PipeNode{
+			NodeType: parse.NodePipe,
+			Pos:      pos,
+			Decl:     []*parse.VariableNode{newVariableNode(pos, variable)},
+			Cmds:     []*parse.CommandNode{newCommandNode(pos, args...)},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomwatkins1994/go-docx-template/internal/functions"
+)
+
+func TestLoopContext(t *testing.T) {
+	people := []map[string]any{
+		{"Name": "Tom", "Pets": []string{"Cat", "Dog"}},
+		{"Name": "Evie", "Pets": []string{"Fish"}},
+		{"Name": "Sam", "Pets": []string{}},
+	}
+
+	tests := []struct {
+		name           string
+		template       string
+		expectedResult string
+		expectError    bool
+	}{
+		{
+			name:           "Index",
+			template:       `{{range .People}}{{$loop.Index}}. {{.Name}} {{end}}`,
+			expectedResult: "1. Tom 2. Evie 3. Sam ",
+		},
+		{
+			name:           "Index0 and RevIndex",
+			template:       `{{range .People}}{{$loop.Index0}}{{$loop.RevIndex}} {{end}}`,
+			expectedResult: "03 12 21 ",
+		},
+		{
+			name:           "First and last",
+			template:       `{{range .People}}{{if $loop.First}}[{{end}}{{.Name}}{{if not $loop.Last}}, {{else}}]{{end}}{{end}}`,
+			expectedResult: "[Tom, Evie, Sam]",
+		},
+		{
+			name:           "Length",
+			template:       `{{range .People}}{{$loop.Index}}/{{$loop.Length}} {{end}}`,
+			expectedResult: "1/3 2/3 3/3 ",
+		},
+		{
+			name:           "Odd and even",
+			template:       `{{range .People}}{{if $loop.Odd}}odd{{end}}{{if $loop.Even}}even{{end}} {{end}}`,
+			expectedResult: "odd even odd ",
+		},
+		{
+			name:           "Parent",
+			template:       `{{range .People}}{{range .Pets}}{{$loop.Parent.Index}}.{{$loop.Index}} {{.}} {{end}}{{end}}`,
+			expectedResult: "1.1 Cat 1.2 Dog 2.1 Fish ",
+		},
+		{
+			name:           "Loop is restored after a nested range",
+			template:       `{{range .People}}{{range .Pets}}{{end}}{{$loop.Index}}{{end}}`,
+			expectedResult: "123",
+		},
+		{
+			name:           "Declared variables are kept",
+			template:       `{{range $i, $person := .People}}{{$i}}{{$loop.Index}}{{$person.Name}} {{end}}`,
+			expectedResult: "01Tom 12Evie 23Sam ",
+		},
+		{
+			name:           "Map",
+			template:       `{{range $key, $value := .Totals}}{{$key}}={{$value}}{{if not $loop.Last}},{{end}}{{end}}`,
+			expectedResult: "a=1,b=2",
+		},
+		{
+			name:           "Integer",
+			template:       `{{range 3}}{{$loop.Index}}{{end}}`,
+			expectedResult: "123",
+		},
+		{
+			name:           "Else",
+			template:       `{{range .Missing}}{{.}}{{else}}None{{end}}`,
+			expectedResult: "None",
+		},
+		{
+			name:           "Pipeline",
+			template:       `{{range sortBy "Name" .People}}{{$loop.Index}}{{.Name}}{{end}}`,
+			expectedResult: "1Evie2Sam3Tom",
+		},
+		{
+			name:           "Table rows",
+			template:       `<w:tbl><w:tr><w:t>{{range .People}}</w:t></w:tr><w:tr><w:t>{{$loop.Index}} {{.Name}}</w:t></w:tr><w:tr><w:t>{{end}}</w:t></w:tr></w:tbl>`,
+			expectedResult: `<w:tbl><w:tr><w:t>1 Tom</w:t></w:tr><w:tr><w:t>2 Evie</w:t></w:tr><w:tr><w:t>3 Sam</w:t></w:tr></w:tbl>`,
+		},
+		{
+			name:        "Outside of a range",
+			template:    `{{$loop.Index}}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			data := map[string]any{
+				"People": people,
+				"Totals": map[string]int{"b": 2, "a": 1},
+			}
+			result, err := ReplaceTagsInXml(tt.template, data, functions.DefaultFuncMap, DefaultDelimiters)
+			assert.Equal(tt.expectError, err != nil, "unexpected error state: %v", err)
+			assert.Equal(tt.expectedResult, result)
+		})
+	}
+}
@@ -1,16 +1,85 @@
 package tags
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/xmlutils"
 )
 
-var tagRegex = regexp.MustCompile("{{.*?}}")
-var incompleteTagRegex = regexp.MustCompile("{{[^}]*?(}$|$)|{$|^}")
+// The delimiters used to mark tags in a document, {{ and }} by default.
+type Delimiters struct {
+	Left  string
+	Right string
+
+	tagRegex *regexp.Regexp
+}
+
+var DefaultDelimiters = mustNewDelimiters("{{", "}}")
+
+func NewDelimiters(left string, right string) (*Delimiters, error) {
+	if left == "" || right == "" {
+		return nil, fmt.Errorf("delimiters cannot be empty")
+	}
+	if left == right {
+		return nil, fmt.Errorf("left and right delimiters must be different, got %q", left)
+	}
+	if strings.ContainsAny(left+right, `\"`) {
+		return nil, fmt.Errorf("delimiters cannot contain backslashes or double quotes")
+	}
+
+	return &Delimiters{
+		Left:     left,
+		Right:    right,
+		tagRegex: regexp.MustCompile(regexp.QuoteMeta(left) + ".*?" + regexp.QuoteMeta(right)),
+	}, nil
+}
 
-func TextContainsTags(text string) bool {
-	return tagRegex.MatchString(text)
+func mustNewDelimiters(left string, right string) *Delimiters {
+	delims, err := NewDelimiters(left, right)
+	if err != nil {
+		panic(err)
+	}
+	return delims
 }
 
-func TextContainsIncompleteTags(text string) bool {
-	return incompleteTagRegex.MatchString(text)
+func (d *Delimiters) TextContainsTags(text string) bool {
+	return d.tagRegex.MatchString(text)
+}
+
+// Whether text contains the start of a tag that hasn't been closed, or ends or starts
+// with part of a delimiter, meaning the rest of the tag is in the next piece of text.
+func (d *Delimiters) TextContainsIncompleteTags(text string) bool {
+	if lastLeft := strings.LastIndex(text, d.Left); lastLeft > -1 {
+		if !strings.Contains(text[lastLeft+len(d.Left):], d.Right) {
+			return true
+		}
+	}
+	for i := 1; i < len(d.Left); i++ {
+		if strings.HasSuffix(text, d.Left[:i]) {
+			return true
+		}
+	}
+	for i := 1; i < len(d.Right); i++ {
+		if strings.HasPrefix(text, d.Right[i:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The delimiters as they appear in document XML, e.g. << becomes &lt;&lt;
+func (d *Delimiters) xmlEscaped() (left string, right string, err error) {
+	left, err = xmlutils.EscapeXmlString(d.Left)
+	if err != nil {
+		return "", "", err
+	}
+	right, err = xmlutils.EscapeXmlString(d.Right)
+	if err != nil {
+		return "", "", err
+	}
+
+	return left, right, nil
 }
@@ -36,7 +36,7 @@ func TestTextContainsTags(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := TextContainsTags(tt.text)
+			result := DefaultDelimiters.TextContainsTags(tt.text)
 			assert.Equal(t, result, tt.expectedResult)
 		})
 	}
@@ -72,8 +72,58 @@ func TestTextContainsIncompleteTags(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := TextContainsIncompleteTags(tt.text)
+			result := DefaultDelimiters.TextContainsIncompleteTags(tt.text)
 			assert.Equal(t, result, tt.expectedResult)
 		})
 	}
 }
+
+func TestNewDelimiters(t *testing.T) {
+	tests := []struct {
+		name        string
+		left        string
+		right       string
+		expectError bool
+	}{
+		{name: "Square brackets", left: "[[", right: "]]"},
+		{name: "Angle brackets", left: "<<", right: ">>"},
+		{name: "Empty delimiter", left: "", right: "]]", expectError: true},
+		{name: "Same delimiters", left: "%%", right: "%%", expectError: true},
+		{name: "Backslash", left: `\[`, right: "]]", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDelimiters(tt.left, tt.right)
+			assert.Equal(t, tt.expectError, err != nil)
+		})
+	}
+}
+
+func TestCustomDelimiters(t *testing.T) {
+	delims, err := NewDelimiters("<<", ">>")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name                   string
+		text                   string
+		expectedTags           bool
+		expectedIncompleteTags bool
+	}{
+		{name: "Complete tag", text: "Name: <<.Name>>", expectedTags: true},
+		{name: "Default delimiters are text", text: "Name: {{.Name}}"},
+		{name: "Unclosed tag", text: "Name: <<.Na", expectedIncompleteTags: true},
+		{name: "Partial left delimiter", text: "Name: <", expectedIncompleteTags: true},
+		{name: "Partial right delimiter", text: ">", expectedIncompleteTags: true},
+		{name: "Single angle brackets", text: "1 < 2 and 3 > 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(tt.expectedTags, delims.TextContainsTags(tt.text))
+			assert.Equal(tt.expectedIncompleteTags, delims.TextContainsIncompleteTags(tt.text))
+		})
+	}
+}
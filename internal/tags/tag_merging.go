@@ -0,0 +1,56 @@
+package tags
+
+import "strings"
+
+// Merge tags which have been split across the text nodes of a paragraph, e.g. because part of a tag
+// has different formatting or was flagged by the spell checker. Takes the text of each node in order
+// and returns the new text of each node.
+//
+// The whole of a tag is moved into the node where it starts so it keeps the formatting of its first
+// character. Text before and after the tag stays in its own node so it keeps its own formatting, and
+// nodes only containing part of the tag are left empty. Tags are normalised as they are merged.
+//
+//	["Hello {{ .Fir", "stName }} and", " welcome"] => ["Hello {{ .FirstName }}", " and", " welcome"]
+func (d *Delimiters) MergeTags(texts []string) []string {
+	fullText := strings.Join(texts, "")
+
+	// The node each character of the full text belongs to
+	owners := make([]int, 0, len(fullText))
+	for i, text := range texts {
+		for range len(text) {
+			owners = append(owners, i)
+		}
+	}
+
+	for start := 0; start < len(fullText); {
+		left := strings.Index(fullText[start:], d.Left)
+		if left == -1 {
+			break
+		}
+		left += start
+
+		right := strings.Index(fullText[left+len(d.Left):], d.Right)
+		if right == -1 {
+			// The tag isn't closed in this paragraph so it is left as it is
+			break
+		}
+		end := left + len(d.Left) + right + len(d.Right)
+
+		for i := left; i < end; i++ {
+			owners[i] = owners[left]
+		}
+		start = end
+	}
+
+	builders := make([]strings.Builder, len(texts))
+	for i, owner := range owners {
+		builders[owner].WriteByte(fullText[i])
+	}
+
+	mergedTexts := make([]string, len(texts))
+	for i := range builders {
+		mergedTexts[i] = d.NormaliseTags(builders[i].String())
+	}
+
+	return mergedTexts
+}
@@ -0,0 +1,67 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTags(t *testing.T) {
+	tests := []struct {
+		name           string
+		texts          []string
+		expectedResult []string
+	}{
+		{
+			name:           "Tags in a single node",
+			texts:          []string{"Hello {{ .Name }}", " welcome"},
+			expectedResult: []string{"Hello {{ .Name }}", " welcome"},
+		},
+		{
+			name:           "Tag split across nodes is moved into the node it starts in",
+			texts:          []string{"{{ .tag ", "}}"},
+			expectedResult: []string{"{{ .tag }}", ""},
+		},
+		{
+			name:           "Text around a split tag stays in its own node",
+			texts:          []string{"Hello {{ .Fir", "stName }} and", " welcome"},
+			expectedResult: []string{"Hello {{ .FirstName }}", " and", " welcome"},
+		},
+		{
+			name:           "Tag split across several nodes",
+			texts:          []string{"{{ ", ".First", "Name", " }}!"},
+			expectedResult: []string{"{{ .FirstName }}", "", "", "!"},
+		},
+		{
+			name:           "Delimiters split across nodes",
+			texts:          []string{"Dear {", "{ .Name }", "},"},
+			expectedResult: []string{"Dear {{ .Name }}", "", ","},
+		},
+		{
+			name:           "Several split tags",
+			texts:          []string{"{{ .A", " }} and {{ .B", " }}"},
+			expectedResult: []string{"{{ .A }}", " and {{ .B }}", ""},
+		},
+		{
+			name:           "Unclosed tags are left as they are",
+			texts:          []string{"{{ .Name }} {{ .A", "ge"},
+			expectedResult: []string{"{{ .Name }} {{ .A", "ge"},
+		},
+		{
+			name:           "Autocorrected characters in merged tags are normalised",
+			texts:          []string{"\u201cPaid\u201d: {{ if eq .Status ", "\u201cPaid\u201d }}"},
+			expectedResult: []string{"\u201cPaid\u201d: {{ if eq .Status \"Paid\" }}", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedResult, DefaultDelimiters.MergeTags(tt.texts))
+		})
+	}
+
+	t.Run("Custom delimiters", func(t *testing.T) {
+		delims := mustNewDelimiters("[[", "]]")
+		assert.Equal(t, []string{"[[ .Name ]]", "{{", " }}"}, delims.MergeTags([]string{"[[ .Na", "me ]]{{", " }}"}))
+	})
+}
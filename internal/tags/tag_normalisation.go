@@ -0,0 +1,37 @@
+package tags
+
+import "strings"
+
+// Word's autocorrect replaces characters as they are typed, which stops tags from being parsed.
+var tagNormaliser = strings.NewReplacer(
+	// Smart quotes
+	"\u201c", `"`,
+	"\u201d", `"`,
+	"\u201e", `"`,
+	"\u201f", `"`,
+	"\u2018", "'",
+	"\u2019", "'",
+	"\u201a", "'",
+	"\u201b", "'",
+	// Figure, en and em dashes and minus signs, e.g. {{- becoming {{–
+	"\u2012", "-",
+	"\u2013", "-",
+	"\u2014", "-",
+	"\u2212", "-",
+	// Non-breaking spaces
+	"\u00a0", " ",
+	"\u2007", " ",
+	"\u202f", " ",
+	// Zero-width spaces, joiners and byte order marks
+	"\u200b", "",
+	"\u200c", "",
+	"\u200d", "",
+	"\u2060", "",
+	"\ufeff", "",
+)
+
+// Replace smart quotes, typographic dashes, non-breaking spaces and zero-width characters inside tags
+// with the characters the template parser expects. Text outside of tags is left as it is.
+func (d *Delimiters) NormaliseTags(text string) string {
+	return d.tagRegex.ReplaceAllStringFunc(text, tagNormaliser.Replace)
+}
@@ -0,0 +1,52 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseTags(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		expectedResult string
+	}{
+		{
+			name:           "Smart double quotes",
+			text:           "{{ if eq .Status \u201cPaid\u201d }}",
+			expectedResult: `{{ if eq .Status "Paid" }}`,
+		},
+		{
+			name:           "Smart single quotes",
+			text:           "{{ if eq .Grade \u2018A\u2019 }}",
+			expectedResult: "{{ if eq .Grade 'A' }}",
+		},
+		{
+			name:           "Dashes",
+			text:           "{{\u2013 .Name \u2014}}",
+			expectedResult: "{{- .Name -}}",
+		},
+		{
+			name:           "Non-breaking spaces",
+			text:           "{{\u00a0.Name\u202f}}",
+			expectedResult: "{{ .Name }}",
+		},
+		{
+			name:           "Zero-width characters",
+			text:           "{{ .Na\u200bme\ufeff }}",
+			expectedResult: "{{ .Name }}",
+		},
+		{
+			name:           "Text outside of tags is left as it is",
+			text:           "\u201cQuoted\u201d\u00a0\u2013 {{ upper \u201cquoted\u201d }} \u2013\u00a0\u201cQuoted\u201d",
+			expectedResult: "\u201cQuoted\u201d\u00a0\u2013 {{ upper \"quoted\" }} \u2013\u00a0\u201cQuoted\u201d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedResult, DefaultDelimiters.NormaliseTags(tt.text))
+		})
+	}
+}
@@ -3,23 +3,50 @@ package tags
 import (
 	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 	"text/template"
 
+	"github.com/tomwatkins1994/go-docx-template/internal/contentcontrols"
 	"github.com/tomwatkins1994/go-docx-template/internal/xmlutils"
 )
 
-// Data should already be processed and have been XML escaped (aside from embedded objects like images) before being passed into this function
-func ReplaceTagsInXml(xmlString string, data map[string]any, funcMap template.FuncMap) (string, error) {
+// Data should already be processed and have been XML escaped (aside from embedded objects like images) before being passed into this function.
+// The numbered styles are the IDs of paragraph styles which number their paragraphs, e.g. ListNumber, so
+// loops repeating lists in those styles are found.
+func ReplaceTagsInXml(xmlString string, data map[string]any, funcMap template.FuncMap, delims *Delimiters, numberedStyles ...string) (string, error) {
+	return replaceTagsInXml(xmlString, data, funcMap, delims, false, numberedStyles)
+}
+
+// Replace tags as ReplaceTagsInXml does, marking the output of each tag as inserted and the content of
+// conditions which aren't met as deleted. The markers are turned into tracked changes by revisions.MarkChanges.
+func ReplaceTagsInXmlWithChanges(xmlString string, data map[string]any, funcMap template.FuncMap, delims *Delimiters, numberedStyles ...string) (string, error) {
+	return replaceTagsInXml(xmlString, data, funcMap, delims, true, numberedStyles)
+}
+
+func replaceTagsInXml(xmlString string, data map[string]any, funcMap template.FuncMap, delims *Delimiters, markChanges bool, numberedStyles []string) (string, error) {
+	left, right, err := delims.xmlEscaped()
+	if err != nil {
+		return "", err
+	}
+
 	// Prepare the XML for tag replacement
-	preparedXmlString, err := xmlutils.PrepareXmlForTagReplacement(xmlString)
+	preparedXmlString, err := xmlutils.PrepareXmlForTagReplacement(xmlString, left, right)
 	if err != nil {
 		return "", err
 	}
+	preparedXmlString = replaceEscapedDelimiters(preparedXmlString, left, right)
 
-	tmpl, err := template.New("").Funcs(funcMap).Parse(preparedXmlString)
+	loopDeclaration := left + loopVariableDeclaration + right
+	tmpl, err := template.New("").Delims(left, right).Funcs(funcMap).Funcs(loopFuncMap).Funcs(contentcontrols.FuncMap).Parse(loopDeclaration + preparedXmlString)
 	if err != nil {
 		return "", fmt.Errorf("error parsing template: %v", err)
 	}
+	addLoopContext(tmpl.Tree)
+	if markChanges {
+		addChangeMarkers(tmpl.Tree)
+	}
+	addListRestartMarkers(tmpl.Tree, numberedStyles)
 
 	buf := &bytes.Buffer{}
 	err = tmpl.Execute(buf, data)
@@ -32,3 +59,12 @@ func ReplaceTagsInXml(xmlString string, data map[string]any, funcMap template.Fu
 
 	return outputXmlString, err
 }
+
+// Delimiters preceded by a backslash are written out as they are, so \{{ .Name }} renders as {{ .Name }}.
+// They are replaced with tags which print the delimiter.
+func replaceEscapedDelimiters(xmlString string, left string, right string) string {
+	return strings.NewReplacer(
+		`\`+left, left+strconv.Quote(left)+right,
+		`\`+right, left+strconv.Quote(right)+right,
+	).Replace(xmlString)
+}
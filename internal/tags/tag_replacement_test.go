@@ -107,6 +107,16 @@ func TestReplaceTagsInText(t *testing.T) {
 			funcMap:     functions.DefaultFuncMap,
 			expectError: false,
 		},
+		{
+			name:              "Basic template with date function call",
+			inputXml:          `<w:p><w:r><w:t>Due: {{ .DueDate | addDays 14 | dateFormat "%d %B %Y" }}</w:t></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r><w:t>Due: 19 March 2024</w:t></w:r></w:p>`,
+			data: map[string]any{
+				"DueDate": "2024-03-05",
+			},
+			funcMap:     functions.DefaultFuncMap,
+			expectError: false,
+		},
 		{
 			name: "Template with table",
 			inputXml: `
@@ -223,7 +233,7 @@ func TestReplaceTagsInText(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			assert := assert.New(t)
 
-			outputXml, err := ReplaceTagsInXml(tt.inputXml, tt.data, tt.funcMap)
+			outputXml, err := ReplaceTagsInXml(tt.inputXml, tt.data, tt.funcMap, DefaultDelimiters)
 			assert.Equal((err != nil), tt.expectError)
 			assert.Equal(removeXmlFormatting(outputXml), removeXmlFormatting(tt.expectedOutputXml))
 		})
@@ -239,3 +249,59 @@ func removeXmlFormatting(originalXML string) string {
 
 	return newXml
 }
+
+func TestReplaceTagsInXmlWithDelimiters(t *testing.T) {
+	squareDelims, err := NewDelimiters("[[", "]]")
+	assert.NoError(t, err)
+	angleDelims, err := NewDelimiters("<<", ">>")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+		delims            *Delimiters
+	}{
+		{
+			name:              "Square brackets",
+			inputXml:          "<w:t>[[ .Name ]] {{ handlebars }}</w:t>",
+			expectedOutputXml: "<w:t>Tom Watkins {{ handlebars }}</w:t>",
+			delims:            squareDelims,
+		},
+		{
+			name:              "Angle brackets are escaped in the XML",
+			inputXml:          "<w:t>&lt;&lt; upper .Name &gt;&gt;</w:t>",
+			expectedOutputXml: "<w:t>TOM WATKINS</w:t>",
+			delims:            angleDelims,
+		},
+		{
+			name:              "Escaped delimiters",
+			inputXml:          `<w:t>\{{ .Name \}} is {{ .Name }}</w:t>`,
+			expectedOutputXml: "<w:t>{{ .Name }} is Tom Watkins</w:t>",
+			delims:            DefaultDelimiters,
+		},
+		{
+			name:              "Escaped custom delimiters",
+			inputXml:          `<w:t>\&lt;&lt;literal\&gt;&gt;</w:t>`,
+			expectedOutputXml: "<w:t>&lt;&lt;literal&gt;&gt;</w:t>",
+			delims:            angleDelims,
+		},
+		{
+			name:              "Escaped quotes in tags",
+			inputXml:          "<w:t>{{ if eq .Name &#34;Tom Watkins&#34; }}Match{{ end }}</w:t>",
+			expectedOutputXml: "<w:t>Match</w:t>",
+			delims:            DefaultDelimiters,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			data := map[string]any{"Name": "Tom Watkins"}
+			outputXml, err := ReplaceTagsInXml(tt.inputXml, data, functions.DefaultFuncMap, tt.delims)
+			assert.NoError(err)
+			assert.Equal(tt.expectedOutputXml, outputXml)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package templatedata
+
+// A bulleted or numbered list to render in place of a tag. It is kept as it is when data is converted
+// into a map so it can be rendered as a list.
+type List struct {
+	// The items of the list, usually strings. A slice after an item holds the items nested under it.
+	Items any
+	// Whether the list is numbered rather than bulleted
+	Numbered bool
+}
@@ -3,6 +3,7 @@ package templatedata
 import (
 	"fmt"
 	"reflect"
+	"time"
 )
 
 func DataToMap(data any) (map[string]any, error) {
@@ -41,11 +42,11 @@ func convertStructToMap(s any) (map[string]any, error) {
 		value := val.Field(i)
 
 		// Store the field name and value in the map
-		if value.Kind() == reflect.Slice {
+		if value.Kind() == reflect.Slice && IsStructOrMapSlice(value.Type()) {
 			newMapSlice := make([]map[string]any, value.Len())
 			for j := range value.Len() {
 				sliceValue := value.Index(j)
-				if sliceValue.Kind() == reflect.Struct {
+				if sliceValue.Kind() == reflect.Struct || sliceValue.Kind() == reflect.Ptr {
 					newMap, err := convertStructToMap(sliceValue.Interface())
 					if err != nil {
 						return nil, err
@@ -56,7 +57,7 @@ func convertStructToMap(s any) (map[string]any, error) {
 				}
 			}
 			result[field.Name] = newMapSlice
-		} else if value.Kind() == reflect.Struct {
+		} else if value.Kind() == reflect.Struct && isConvertedStruct(value.Type()) {
 			newMap, err := convertStructToMap(value.Interface())
 			if err != nil {
 				return nil, err
@@ -69,3 +70,23 @@ func convertStructToMap(s any) (map[string]any, error) {
 
 	return result, nil
 }
+
+// Whether a slice holds structs or maps which should be converted into a []map[string]any.
+// Other slices such as []string are left as they are.
+func IsStructOrMapSlice(sliceType reflect.Type) bool {
+	elemType := sliceType.Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() == reflect.Struct {
+		return isConvertedStruct(elemType)
+	}
+
+	return elemType == reflect.TypeFor[map[string]any]()
+}
+
+// Whether a struct should be converted into a map. Dates are kept so they can be formatted by the date
+// functions and lists are kept so they can be rendered as lists.
+func isConvertedStruct(structType reflect.Type) bool {
+	return structType != reflect.TypeFor[time.Time]() && structType != reflect.TypeFor[List]()
+}
@@ -2,6 +2,7 @@ package templatedata
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -118,6 +119,59 @@ func TestConvertStructToMap(t *testing.T) {
 		assert.Nil(err)
 	})
 
+	t.Run("Struct with a time should keep the time", func(t *testing.T) {
+		assert := assert.New(t)
+
+		createdAt := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+		data := struct {
+			ProjectNumber string
+			CreatedAt     time.Time
+		}{
+			ProjectNumber: "B-00001",
+			CreatedAt:     createdAt,
+		}
+		outputMap, err := convertStructToMap(data)
+		assert.Equal(map[string]any{
+			"ProjectNumber": "B-00001",
+			"CreatedAt":     createdAt,
+		}, outputMap)
+		assert.Nil(err)
+	})
+
+	t.Run("Struct with a list should keep the list", func(t *testing.T) {
+		assert := assert.New(t)
+
+		steps := List{Items: []string{"Measure", "Cut"}, Numbered: true}
+		data := struct {
+			Steps List
+		}{
+			Steps: steps,
+		}
+		outputMap, err := convertStructToMap(data)
+		assert.Equal(map[string]any{
+			"Steps": steps,
+		}, outputMap)
+		assert.Nil(err)
+	})
+
+	t.Run("Struct with slices of values should keep the slices", func(t *testing.T) {
+		assert := assert.New(t)
+
+		data := struct {
+			Tags    []string
+			Amounts []float64
+		}{
+			Tags:    []string{"urgent", "internal"},
+			Amounts: []float64{10.5, 20},
+		}
+		outputMap, err := convertStructToMap(data)
+		assert.Equal(map[string]any{
+			"Tags":    []string{"urgent", "internal"},
+			"Amounts": []float64{10.5, 20},
+		}, outputMap)
+		assert.Nil(err)
+	})
+
 	t.Run("Pointer to a struct", func(t *testing.T) {
 		assert := assert.New(t)
 
@@ -0,0 +1,322 @@
+package xmltree
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// A node in an XML tree. Either an *Element, *Text or *Raw.
+type Node interface {
+	writeTo(buf *strings.Builder)
+}
+
+// An element, keeping its namespace prefix and attributes exactly as they were parsed
+// so documents can be written back out without changes.
+type Element struct {
+	// Name.Space holds the prefix rather than the namespace URL, e.g. {Space: "w", Local: "p"}
+	Name     xml.Name
+	Attr     []xml.Attr
+	Children []Node
+}
+
+// Character data, stored unescaped.
+type Text struct {
+	Data string
+}
+
+// Comments, processing instructions and directives, written out as they are.
+type Raw struct {
+	Data string
+}
+
+// Parse XML into a list of top level nodes, e.g. the XML declaration and the root element.
+func Parse(data []byte) ([]Node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	root := &Element{}
+	stack := []*Element{root}
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &Element{Name: t.Name, Attr: t.Copy().Attr}
+			parent.Children = append(parent.Children, element)
+			stack = append(stack, element)
+		case xml.EndElement:
+			if len(stack) == 1 || t.Name != parent.Name {
+				return nil, fmt.Errorf("unexpected end element </%s>", qualifiedName(t.Name))
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.Children = append(parent.Children, &Text{string(t)})
+		case xml.Comment:
+			parent.Children = append(parent.Children, &Raw{"<!--" + string(t) + "-->"})
+		case xml.ProcInst:
+			parent.Children = append(parent.Children, &Raw{"<?" + t.Target + " " + string(t.Inst) + "?>"})
+		case xml.Directive:
+			parent.Children = append(parent.Children, &Raw{"<!" + string(t) + ">"})
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("element <%s> is not closed", qualifiedName(stack[len(stack)-1].Name))
+	}
+
+	return root.Children, nil
+}
+
+// Parse a string of XML such as "<w:r><w:t>Text</w:t></w:r>".
+func ParseString(xmlString string) ([]Node, error) {
+	return Parse([]byte(xmlString))
+}
+
+// Write nodes out as XML.
+func Marshal(nodes ...Node) string {
+	var buf strings.Builder
+	for _, node := range nodes {
+		node.writeTo(&buf)
+	}
+	return buf.String()
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+func splitQualifiedName(name string) xml.Name {
+	if prefix, local, ok := strings.Cut(name, ":"); ok {
+		return xml.Name{Space: prefix, Local: local}
+	}
+	return xml.Name{Local: name}
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+var attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+
+func (e *Element) writeTo(buf *strings.Builder) {
+	buf.WriteString("<")
+	buf.WriteString(qualifiedName(e.Name))
+	for _, attr := range e.Attr {
+		buf.WriteString(" ")
+		buf.WriteString(qualifiedName(attr.Name))
+		buf.WriteString(`="`)
+		buf.WriteString(attrEscaper.Replace(attr.Value))
+		buf.WriteString(`"`)
+	}
+	if len(e.Children) == 0 {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteString(">")
+	for _, child := range e.Children {
+		child.writeTo(buf)
+	}
+	buf.WriteString("</")
+	buf.WriteString(qualifiedName(e.Name))
+	buf.WriteString(">")
+}
+
+func (t *Text) writeTo(buf *strings.Builder) {
+	buf.WriteString(textEscaper.Replace(t.Data))
+}
+
+func (r *Raw) writeTo(buf *strings.Builder) {
+	buf.WriteString(r.Data)
+}
+
+// Create an element from a qualified name and pairs of attribute names and values.
+//
+//	NewElement("w:pStyle", "w:val", "Heading1")
+func NewElement(name string, attrs ...string) *Element {
+	element := &Element{Name: splitQualifiedName(name)}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		element.SetAttr(attrs[i], attrs[i+1])
+	}
+	return element
+}
+
+// The qualified name of the element, e.g. "w:p".
+func (e *Element) Tag() string {
+	return qualifiedName(e.Name)
+}
+
+// Whether the element has a qualified name, e.g. e.Is("w:p").
+func (e *Element) Is(name string) bool {
+	return e.Tag() == name
+}
+
+// Get the value of an attribute by its qualified name, e.g. "w:val".
+func (e *Element) AttrValue(name string) (string, bool) {
+	for _, attr := range e.Attr {
+		if qualifiedName(attr.Name) == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set an attribute by its qualified name, adding it if it doesn't exist.
+func (e *Element) SetAttr(name string, value string) {
+	for i, attr := range e.Attr {
+		if qualifiedName(attr.Name) == name {
+			e.Attr[i].Value = value
+			return
+		}
+	}
+	e.Attr = append(e.Attr, xml.Attr{Name: splitQualifiedName(name), Value: value})
+}
+
+// Remove an attribute by its qualified name.
+func (e *Element) RemoveAttr(name string) {
+	for i, attr := range e.Attr {
+		if qualifiedName(attr.Name) == name {
+			e.Attr = append(e.Attr[:i], e.Attr[i+1:]...)
+			return
+		}
+	}
+}
+
+// The child elements, ignoring text and other nodes.
+func (e *Element) Elements() []*Element {
+	elements := []*Element{}
+	for _, child := range e.Children {
+		if element, ok := child.(*Element); ok {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// Find the first child element with a qualified name.
+func (e *Element) Find(name string) *Element {
+	for _, child := range e.Children {
+		if element, ok := child.(*Element); ok && element.Is(name) {
+			return element
+		}
+	}
+	return nil
+}
+
+// Find all child elements with a qualified name.
+func (e *Element) FindAll(name string) []*Element {
+	elements := []*Element{}
+	for _, child := range e.Children {
+		if element, ok := child.(*Element); ok && element.Is(name) {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// Insert a child element before the first child element with one of the qualified names given, or at
+// the end if there are none, e.g. to keep children in the order a schema requires.
+func (e *Element) InsertBefore(element *Element, names ...string) {
+	for i, child := range e.Children {
+		if childElement, ok := child.(*Element); ok && slices.ContainsFunc(names, childElement.Is) {
+			e.Children = slices.Insert(e.Children, i, Node(element))
+			return
+		}
+	}
+	e.Children = append(e.Children, element)
+}
+
+// The text of the element and its descendants.
+func (e *Element) Text() string {
+	var buf strings.Builder
+	Walk(e.Children, func(node Node) bool {
+		if text, ok := node.(*Text); ok {
+			buf.WriteString(text.Data)
+		}
+		return true
+	})
+	return buf.String()
+}
+
+// Replace the children of the element with a single text node, or remove them if the text is empty.
+func (e *Element) SetText(text string) {
+	if text == "" {
+		e.Children = nil
+		return
+	}
+	e.Children = []Node{&Text{text}}
+}
+
+// Make a deep copy of the element.
+func (e *Element) Clone() *Element {
+	clone := &Element{Name: e.Name, Attr: append([]xml.Attr{}, e.Attr...)}
+	for _, child := range e.Children {
+		switch c := child.(type) {
+		case *Element:
+			clone.Children = append(clone.Children, c.Clone())
+		case *Text:
+			clone.Children = append(clone.Children, &Text{c.Data})
+		case *Raw:
+			clone.Children = append(clone.Children, &Raw{c.Data})
+		}
+	}
+	return clone
+}
+
+// Visit nodes depth first in document order. Children are only visited if fn returns true.
+func Walk(nodes []Node, fn func(node Node) bool) {
+	for _, node := range nodes {
+		if fn(node) {
+			if element, ok := node.(*Element); ok {
+				Walk(element.Children, fn)
+			}
+		}
+	}
+}
+
+// Find all descendant elements with a qualified name in document order,
+// including those nested inside other matching elements.
+func FindDescendants(nodes []Node, name string) []*Element {
+	elements := []*Element{}
+	Walk(nodes, func(node Node) bool {
+		if element, ok := node.(*Element); ok && element.Is(name) {
+			elements = append(elements, element)
+		}
+		return true
+	})
+	return elements
+}
+
+// Find the first element with a qualified name, searching depth first.
+func FindFirst(nodes []Node, name string) *Element {
+	var found *Element
+	Walk(nodes, func(node Node) bool {
+		if found != nil {
+			return false
+		}
+		if element, ok := node.(*Element); ok && element.Is(name) {
+			found = element
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Find the root element of a parsed document.
+func Root(nodes []Node) *Element {
+	for _, node := range nodes {
+		if element, ok := node.(*Element); ok {
+			return element
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package xmltree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAndMarshal(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:              "Declaration and namespaces are kept",
+			inputXml:          `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body/></w:document>`,
+			expectedOutputXml: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body/></w:document>`,
+		},
+		{
+			name:              "Attributes keep their order and prefixes",
+			inputXml:          `<w:p w:rsidR="00F543E2" w14:paraId="1A2B"><w:t xml:space="preserve"> Text </w:t></w:p>`,
+			expectedOutputXml: `<w:p w:rsidR="00F543E2" w14:paraId="1A2B"><w:t xml:space="preserve"> Text </w:t></w:p>`,
+		},
+		{
+			name:              "Text is escaped",
+			inputXml:          `<w:t>&lt;tag&gt; &amp; "quotes" &#34;entities&#34;</w:t>`,
+			expectedOutputXml: `<w:t>&lt;tag&gt; &amp; "quotes" "entities"</w:t>`,
+		},
+		{
+			name:              "Attributes are escaped",
+			inputXml:          `<w:fldSimple w:instr=" MERGEFIELD &quot;Name&quot; &amp; more "/>`,
+			expectedOutputXml: `<w:fldSimple w:instr=" MERGEFIELD &quot;Name&quot; &amp; more "/>`,
+		},
+		{
+			name:              "Comments are kept",
+			inputXml:          `<w:p><!-- comment --></w:p>`,
+			expectedOutputXml: `<w:p><!-- comment --></w:p>`,
+		},
+		{
+			name:              "Empty elements are self closing",
+			inputXml:          `<w:p><w:r></w:r></w:p>`,
+			expectedOutputXml: `<w:p><w:r/></w:p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			nodes, err := ParseString(tt.inputXml)
+			assert.NoError(err)
+			assert.Equal(tt.expectedOutputXml, Marshal(nodes...))
+		})
+	}
+
+	t.Run("Mismatched elements should return an error", func(t *testing.T) {
+		_, err := ParseString("<w:p><w:r></w:p></w:r>")
+		assert.Error(t, err)
+	})
+
+	t.Run("Unclosed elements should return an error", func(t *testing.T) {
+		_, err := ParseString("<w:p><w:r>")
+		assert.Error(t, err)
+	})
+}
+
+func TestElement(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	nodes, err := ParseString(`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Hello</w:t></w:r><w:hyperlink><w:r><w:t> World</w:t></w:r></w:hyperlink></w:p>`)
+	require.NoError(err)
+
+	paragraph := Root(nodes)
+	require.NotNil(paragraph)
+	assert.True(paragraph.Is("w:p"))
+	assert.Equal("Hello World", paragraph.Text())
+	assert.Len(paragraph.FindAll("w:r"), 1)
+	assert.Len(FindDescendants(nodes, "w:r"), 2)
+
+	style := FindFirst(nodes, "w:pStyle")
+	require.NotNil(style)
+	value, ok := style.AttrValue("w:val")
+	assert.True(ok)
+	assert.Equal("Heading1", value)
+
+	style.SetAttr("w:val", "Heading2")
+	style.SetAttr("w:other", "1")
+	style.RemoveAttr("w:other")
+	assert.Equal(`<w:pStyle w:val="Heading2"/>`, Marshal(style))
+
+	clone := paragraph.Clone()
+	clone.Find("w:r").Find("w:t").SetText("Goodbye")
+	assert.Equal("Hello World", paragraph.Text())
+	assert.Equal("Goodbye World", clone.Text())
+
+	assert.Equal(`<w:b w:val="0"/>`, Marshal(NewElement("w:b", "w:val", "0")))
+
+	properties := paragraph.Find("w:pPr")
+	properties.InsertBefore(NewElement("w:jc"), "w:rPr")
+	properties.InsertBefore(NewElement("w:keepNext"), "w:jc", "w:rPr")
+	assert.Equal(`<w:pPr><w:pStyle w:val="Heading2"/><w:keepNext/><w:jc/></w:pPr>`, Marshal(properties))
+}
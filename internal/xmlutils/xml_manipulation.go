@@ -1,41 +1,70 @@
 package xmlutils
 
 import (
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/dlclark/regexp2"
 )
 
-func PrepareXmlForTagReplacement(xmlString string) (string, error) {
-	newXmlString, err := replaceTableRangeRows(xmlString)
+// Delimiters should be passed as they appear in the XML, e.g. &lt;&lt; rather than <<
+func PrepareXmlForTagReplacement(xmlString string, leftDelim string, rightDelim string) (string, error) {
+	newXmlString, err := replaceTableRangeRows(xmlString, leftDelim, rightDelim)
+	if err != nil {
+		return "", err
+	}
 
-	return newXmlString, err
+	return unescapeQuotesInTags(newXmlString, leftDelim, rightDelim), nil
 }
 
-var tableRangeRowRegex = regexp2.MustCompile("<w:tr>(?:(?!<w:tr>).)*?({{range .*?}}|{{ range .*? }}|{{end}}|{{ end }})(?:(?!<w:tr>).)*?</w:tr>", 0)
+func tableRangeRowRegex(leftDelim string, rightDelim string) *regexp2.Regexp {
+	left := regexp2.Escape(leftDelim)
+	right := regexp2.Escape(rightDelim)
+
+	// Escaped delimiters (preceded by a backslash) are not tags
+	rangeTag := `(?<!\\)` + left + `(?:range .*?| range .*? |end| end )` + right
+	// Rows can have attributes such as w:rsidR when the XML hasn't been rewritten by a library
+	regex := regexp2.MustCompile(`<w:tr(?:\s[^>]*)?>(?:(?!<w:tr[\s>]).)*?(`+rangeTag+`)(?:(?!<w:tr[\s>]).)*?</w:tr>`, 0)
+	regex.MatchTimeout = 50 * time.Millisecond
+
+	return regex
+}
 
-func replaceTableRangeRows(xmlString string) (string, error) {
-	tableRangeRowRegex.MatchTimeout = 50 * time.Millisecond
+func replaceTableRangeRows(xmlString string, leftDelim string, rightDelim string) (string, error) {
+	regex := tableRangeRowRegex(leftDelim, rightDelim)
 
 	newXmlString := xmlString
 
-	m, err := tableRangeRowRegex.FindStringMatch(xmlString)
+	m, err := regex.FindStringMatch(xmlString)
 	if err != nil {
 		return "", err
 	}
 	for m != nil {
 		gps := m.Groups()
 		newXmlString = strings.Replace(newXmlString, m.String(), gps[1].Captures[0].String(), 1)
-		m, _ = tableRangeRowRegex.FindNextMatch(m)
+		m, _ = regex.FindNextMatch(m)
 	}
 
 	return newXmlString, nil
 }
 
+var quoteEntityReplacer = strings.NewReplacer("&#34;", `"`, "&quot;", `"`, "&#39;", "'", "&apos;", "'")
+
+// Quotes are escaped when the document XML is generated, which stops string arguments
+// such as {{ dateFormat "%d %B %Y" .Date }} from being parsed. Other entities are left
+// escaped so text inside tags is still valid XML when it is output.
+func unescapeQuotesInTags(xmlString string, leftDelim string, rightDelim string) string {
+	tagRegex := regexp.MustCompile(regexp.QuoteMeta(leftDelim) + "[^<]*?" + regexp.QuoteMeta(rightDelim))
+
+	return tagRegex.ReplaceAllStringFunc(xmlString, quoteEntityReplacer.Replace)
+}
+
+var drawingInTextRegex = regexp.MustCompile(`<w:t(?:\s[^>]*)?><w:drawing>`)
+
 func FixXmlIssuesPostTagReplacement(xmlString string) string {
 	// Fix issues with drawings in text nodes
-	xmlString = strings.ReplaceAll(xmlString, "<w:t><w:drawing>", "<w:drawing>")
+	xmlString = drawingInTextRegex.ReplaceAllString(xmlString, "<w:drawing>")
 	xmlString = strings.ReplaceAll(xmlString, "</w:drawing></w:t>", "</w:drawing>")
 
 	return xmlString
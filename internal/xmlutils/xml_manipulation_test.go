@@ -47,19 +47,94 @@ func TestReplaceTableRangeRows(t *testing.T) {
 			inputXml:          "<w:tbl><w:tr>{{range . }}</w:tr><w:tr></w:tr><w:tr>{{end}}</w:tr></w:tbl>",
 			expectedOutputXml: "<w:tbl>{{range . }}<w:tr></w:tr>{{end}}</w:tbl>",
 		},
+		{
+			name:              "Rows with attributes",
+			inputXml:          `<w:tbl><w:tr w:rsidR="00A1"><w:trPr/>{{range . }}</w:tr><w:tr w:rsidR="00A2"></w:tr><w:tr>{{end}}</w:tr></w:tbl>`,
+			expectedOutputXml: `<w:tbl>{{range . }}<w:tr w:rsidR="00A2"></w:tr>{{end}}</w:tbl>`,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			assert := assert.New(t)
 
-			outputXml, err := replaceTableRangeRows(tt.inputXml)
+			outputXml, err := replaceTableRangeRows(tt.inputXml, "{{", "}}")
 			assert.Nil(err)
 			assert.Equal(outputXml, tt.expectedOutputXml)
 		})
 	}
 }
 
+func TestReplaceTableRangeRowsWithCustomDelimiters(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:              "Full table",
+			inputXml:          "<w:tbl><w:tr>&lt;&lt;range . &gt;&gt;</w:tr><w:tr></w:tr><w:tr>&lt;&lt;end&gt;&gt;</w:tr></w:tbl>",
+			expectedOutputXml: "<w:tbl>&lt;&lt;range . &gt;&gt;<w:tr></w:tr>&lt;&lt;end&gt;&gt;</w:tbl>",
+		},
+		{
+			name:              "Default delimiters are left as they are",
+			inputXml:          "<w:tbl><w:tr>{{range . }}</w:tr></w:tbl>",
+			expectedOutputXml: "<w:tbl><w:tr>{{range . }}</w:tr></w:tbl>",
+		},
+		{
+			name:              "Escaped delimiters are left as they are",
+			inputXml:          "<w:tbl><w:tr>\\&lt;&lt;range . &gt;&gt;</w:tr></w:tbl>",
+			expectedOutputXml: "<w:tbl><w:tr>\\&lt;&lt;range . &gt;&gt;</w:tr></w:tbl>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			outputXml, err := replaceTableRangeRows(tt.inputXml, "&lt;&lt;", "&gt;&gt;")
+			assert.Nil(err)
+			assert.Equal(outputXml, tt.expectedOutputXml)
+		})
+	}
+}
+
+func TestUnescapeQuotesInTags(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputXml          string
+		expectedOutputXml string
+	}{
+		{
+			name:              "Quotes in a tag",
+			inputXml:          "<w:t>{{ dateFormat &#34;%d %B %Y&#34; .Date }}</w:t>",
+			expectedOutputXml: "<w:t>{{ dateFormat \"%d %B %Y\" .Date }}</w:t>",
+		},
+		{
+			name:              "Single quotes in a tag",
+			inputXml:          "<w:t>{{ if eq .Grade &#39;A&#39; }}</w:t>",
+			expectedOutputXml: "<w:t>{{ if eq .Grade 'A' }}</w:t>",
+		},
+		{
+			name:              "Quotes outside of tags",
+			inputXml:          "<w:t>&#34;Quoted&#34; {{ .Name }}</w:t>",
+			expectedOutputXml: "<w:t>&#34;Quoted&#34; {{ .Name }}</w:t>",
+		},
+		{
+			name:              "Other entities in a tag",
+			inputXml:          "<w:t>{{ if eq .Department &#34;R&amp;D&#34; }}</w:t>",
+			expectedOutputXml: "<w:t>{{ if eq .Department \"R&amp;D\" }}</w:t>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputXml := unescapeQuotesInTags(tt.inputXml, "{{", "}}")
+			assert.Equal(t, tt.expectedOutputXml, outputXml)
+		})
+	}
+}
+
 func TestFixXmlIssuesPostTagReplacement(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -71,6 +146,11 @@ func TestFixXmlIssuesPostTagReplacement(t *testing.T) {
 			inputXml:          "<w:t><w:drawing>...</w:drawing></w:t>",
 			expectedOutputXml: "<w:drawing>...</w:drawing>",
 		},
+		{
+			name:              "Drawing tags in text with preserved spaces",
+			inputXml:          `<w:t xml:space="preserve"><w:drawing>...</w:drawing></w:t>`,
+			expectedOutputXml: "<w:drawing>...</w:drawing>",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,29 @@
+package docxtpl
+
+import (
+	"errors"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/docxwrappers"
+	"github.com/tomwatkins1994/go-docx-template/internal/templatedata"
+)
+
+// A bulleted or numbered list to render in place of a tag, with a paragraph for each item. Items are
+// usually strings, and a slice after an item holds the items nested under it. Numbered lists start
+// from 1 each time they are rendered.
+//
+//	data := map[string]any{
+//		"Steps": docxtpl.List{
+//			Items:    []any{"Measure", []string{"Twice"}, "Cut"},
+//			Numbered: true,
+//		},
+//	}
+type List = templatedata.List
+
+func (d *DocxTmpl) addList(items any, numbered bool) (string, error) {
+	docx, ok := d.docx.(docxwrappers.ListsDocxWrapper)
+	if !ok {
+		return "", errors.New("lists are not supported by this document")
+	}
+
+	return docx.AddList(items, numbered)
+}
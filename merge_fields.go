@@ -0,0 +1,58 @@
+package docxtpl
+
+import (
+	"fmt"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/docxwrappers"
+	"github.com/tomwatkins1994/go-docx-template/internal/mergefields"
+)
+
+// A mail merge field which couldn't be converted into a tag, with the reason why.
+type MergeFieldIssue = mergefields.Issue
+
+// Convert the MERGEFIELD and IF fields of a Word mail merge template into tags, so it can be rendered
+// or saved as a template. For example { MERGEFIELD FirstName } becomes {{ .FirstName }} and
+// { IF { MERGEFIELD Paid } = "Yes" "Thank you" "" } becomes {{ if eq (print .Paid) "Yes" }}Thank you{{ end -}}.
+//
+// Fields which can't be converted are left in the document and returned, apart from NEXT fields which are
+// removed as a template has no records to move between.
+//
+//	issues, err := doc.ConvertMergeFields()
+//	for _, issue := range issues {
+//		fmt.Println(issue)
+//	}
+func (d *DocxTmpl) ConvertMergeFields() ([]MergeFieldIssue, error) {
+	issues := []MergeFieldIssue{}
+
+	if parts, ok := d.docx.(docxwrappers.PartsDocxWrapper); ok {
+		for _, partName := range parts.GetTemplatePartNames() {
+			partXmlString, err := parts.GetPartXml(partName)
+			if err != nil {
+				return nil, err
+			}
+			partXmlString, partIssues, err := mergefields.Convert(partXmlString)
+			if err != nil {
+				return nil, fmt.Errorf("error converting %s: %w", partName, err)
+			}
+			if err := parts.ReplacePartXml(partName, partXmlString); err != nil {
+				return nil, err
+			}
+			issues = append(issues, partIssues...)
+		}
+	}
+
+	documentXmlString, err := d.docx.GetDocumentXml()
+	if err != nil {
+		return nil, err
+	}
+	documentXmlString, documentIssues, err := mergefields.Convert(documentXmlString)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.docx.ReplaceDocumentXml(documentXmlString); err != nil {
+		return nil, err
+	}
+
+	// Issues in the document are listed first as that's where most fields are
+	return append(documentIssues, issues...), nil
+}
@@ -0,0 +1,147 @@
+package docxtpl
+
+import (
+	"time"
+
+	"github.com/tomwatkins1994/go-docx-template/internal/properties"
+	"golang.org/x/text/language"
+)
+
+// An option which changes how a document is rendered.
+//
+//	err = doc.Render(data, docxtpl.WithLocale(language.German))
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	locale     language.Tag
+	leftDelim  string
+	rightDelim string
+
+	trackChanges bool
+	changeAuthor string
+	changeDate   time.Time
+
+	properties *properties.Properties
+
+	updateFieldsOnOpen bool
+}
+
+func newRenderOptions(options ...RenderOption) *renderOptions {
+	renderOptions := &renderOptions{
+		locale:     language.English,
+		leftDelim:  "{{",
+		rightDelim: "}}",
+	}
+	for _, option := range options {
+		option(renderOptions)
+	}
+
+	return renderOptions
+}
+
+// Set the locale used by the built-in functions when formatting numbers, currencies,
+// percentages, dates and title case text. Defaults to English.
+func WithLocale(locale language.Tag) RenderOption {
+	return func(o *renderOptions) {
+		o.locale = locale
+	}
+}
+
+// Change the delimiters used to mark tags in the document from {{ and }}.
+// Delimiters preceded by a backslash are written out as they are, e.g. \[[ renders as [[.
+//
+//	err = doc.Render(data, docxtpl.WithDelimiters("[[", "]]"))
+func WithDelimiters(left string, right string) RenderOption {
+	return func(o *renderOptions) {
+		o.leftDelim = left
+		o.rightDelim = right
+	}
+}
+
+// Show the values substituted into the document as tracked changes made by an author, so they are
+// highlighted when the document is opened in Word and can be accepted. Content removed because a
+// condition wasn't met is shown as deleted.
+//
+//	err = doc.Render(data, docxtpl.WithTrackedChanges("Document Generator"))
+func WithTrackedChanges(author string) RenderOption {
+	return func(o *renderOptions) {
+		o.trackChanges = true
+		o.changeAuthor = author
+		o.changeDate = time.Now()
+	}
+}
+
+// The properties of a document, such as its title and author, which Word shows under File > Info.
+type DocumentProperties = properties.Properties
+
+// Set the properties of the document, such as its title, author and dates. Empty values are left as
+// they are in the template, and values can contain tags which are filled in from the data.
+//
+//	err = doc.Render(data, docxtpl.WithDocumentProperties(docxtpl.DocumentProperties{
+//		Title:  "Quote {{ .QuoteNumber }}",
+//		Author: "Sales Team",
+//		Custom: map[string]any{"Client": "TW Software"},
+//	}))
+func WithDocumentProperties(properties DocumentProperties) RenderOption {
+	return func(o *renderOptions) {
+		o.properties = &properties
+	}
+}
+
+// Have Word update every field when the document is opened, such as page numbers in a table of contents
+// and cross-references. Word asks the reader before updating them.
+//
+//	err = doc.Render(data, docxtpl.WithUpdateFieldsOnOpen())
+func WithUpdateFieldsOnOpen() RenderOption {
+	return func(o *renderOptions) {
+		o.updateFieldsOnOpen = true
+	}
+}
+
+// An option which changes how a document is parsed.
+//
+//	doc, err := docxtpl.ParseFromFilename("template.docx", docxtpl.WithAcceptedRevisions(), docxtpl.WithoutComments())
+type ParseOption func(*parseOptions)
+
+type revisionHandling int
+
+const (
+	keepRevisions revisionHandling = iota
+	acceptRevisions
+	rejectRevisions
+)
+
+type parseOptions struct {
+	revisions      revisionHandling
+	removeComments bool
+}
+
+func newParseOptions(options ...ParseOption) *parseOptions {
+	parseOptions := &parseOptions{}
+	for _, option := range options {
+		option(parseOptions)
+	}
+
+	return parseOptions
+}
+
+// Accept every tracked change in the template when it is parsed, as Word's Accept All Changes does.
+func WithAcceptedRevisions() ParseOption {
+	return func(o *parseOptions) {
+		o.revisions = acceptRevisions
+	}
+}
+
+// Reject every tracked change in the template when it is parsed, as Word's Reject All Changes does.
+func WithRejectedRevisions() ParseOption {
+	return func(o *parseOptions) {
+		o.revisions = rejectRevisions
+	}
+}
+
+// Remove every comment from the template when it is parsed.
+func WithoutComments() ParseOption {
+	return func(o *parseOptions) {
+		o.removeComments = true
+	}
+}